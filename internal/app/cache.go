@@ -0,0 +1,46 @@
+package app
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/gi8lino/tiledash/internal/cache"
+	"github.com/gi8lino/tiledash/internal/flag"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// newCacheFactory returns the factory creating each provider's response cache per the CLI flags,
+// plus a cleanup func releasing shared resources (e.g. the Redis client) on shutdown.
+func newCacheFactory(flags flag.Config) (cache.Factory, func(), error) {
+	switch flags.CacheBackend {
+	case "file":
+		return func(provider string) (cache.Cache, error) {
+			dir := filepath.Join(flags.CacheDir, filepath.Base(filepath.Clean("/"+provider)))
+			return cache.NewFileCache(dir,
+				cache.WithMaxEntries(flags.CacheMaxEntries),
+				cache.WithMaxBytes(flags.CacheMaxBytes),
+				cache.WithSweepInterval(flags.CacheSweepInterval),
+			)
+		}, func() {}, nil
+
+	case "redis":
+		opts, err := redis.ParseURL(flags.CacheRedisURL)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid redis cache url: %w", err)
+		}
+		client := redis.NewClient(opts) // one pooled client shared by all providers
+		return func(provider string) (cache.Cache, error) {
+			return cache.NewRedisCache(client, flags.CacheRedisPrefix+":"+provider+":"), nil
+		}, func() { _ = client.Close() }, nil
+
+	default:
+		return func(string) (cache.Cache, error) {
+			return cache.NewMemCache(
+				cache.WithMaxEntries(flags.CacheMaxEntries),
+				cache.WithMaxBytes(flags.CacheMaxBytes),
+				cache.WithSweepInterval(flags.CacheSweepInterval),
+			), nil
+		}, func() {}, nil
+	}
+}
@@ -0,0 +1,72 @@
+package app
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gi8lino/tiledash/internal/cache"
+	"github.com/gi8lino/tiledash/internal/flag"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewCacheFactory(t *testing.T) {
+	t.Parallel()
+
+	t.Run("memory backend", func(t *testing.T) {
+		t.Parallel()
+		newCache, cleanup, err := newCacheFactory(flag.Config{CacheBackend: "memory", CacheMaxEntries: 1})
+		require.NoError(t, err)
+		t.Cleanup(cleanup)
+
+		c, err := newCache("jira")
+		require.NoError(t, err)
+		t.Cleanup(c.Close)
+		assert.IsType(t, &cache.MemCache{}, c)
+	})
+
+	t.Run("file backend uses a directory per provider", func(t *testing.T) {
+		t.Parallel()
+		dir := t.TempDir()
+		newCache, cleanup, err := newCacheFactory(flag.Config{CacheBackend: "file", CacheDir: dir})
+		require.NoError(t, err)
+		t.Cleanup(cleanup)
+
+		c, err := newCache("../jira")
+		require.NoError(t, err)
+		t.Cleanup(c.Close)
+		assert.IsType(t, &cache.FileCache{}, c)
+
+		c.Set("k", cache.Entry{Value: map[string]any{"v": 1}}, time.Minute)
+		assert.FileExists(t, filepath.Join(dir, "jira", "k.json"))
+	})
+
+	t.Run("redis backend namespaces keys per provider", func(t *testing.T) {
+		t.Parallel()
+		srv := miniredis.RunT(t)
+		newCache, cleanup, err := newCacheFactory(flag.Config{
+			CacheBackend:     "redis",
+			CacheRedisURL:    "redis://" + srv.Addr() + "/0",
+			CacheRedisPrefix: "td",
+		})
+		require.NoError(t, err)
+		t.Cleanup(cleanup)
+
+		c, err := newCache("jira")
+		require.NoError(t, err)
+		assert.IsType(t, &cache.RedisCache{}, c)
+
+		c.Set("k", cache.Entry{Value: map[string]any{"v": 1}}, time.Minute)
+		assert.True(t, srv.Exists("td:jira:k"))
+	})
+
+	t.Run("redis backend rejects invalid urls", func(t *testing.T) {
+		t.Parallel()
+		_, _, err := newCacheFactory(flag.Config{CacheBackend: "redis", CacheRedisURL: "http://nope"})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid redis cache url")
+	})
+}
@@ -0,0 +1,134 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html/template"
+	"io"
+	"io/fs"
+
+	"github.com/gi8lino/tiledash/internal/config"
+	"github.com/gi8lino/tiledash/internal/flag"
+	"github.com/gi8lino/tiledash/internal/providers"
+	"github.com/gi8lino/tiledash/internal/templates"
+	"github.com/gi8lino/tiledash/internal/transform"
+
+	"github.com/containeroo/tinyflags"
+)
+
+// runCommand runs the subcommand named by the first argument, if any.
+// It reports false when args don't start with a subcommand, so Run starts the server.
+func runCommand(ctx context.Context, webFS fs.FS, version string, args []string, stdOut, stdErr io.Writer) (bool, error) {
+	if len(args) == 0 {
+		return false, nil
+	}
+	var err error
+	switch args[0] {
+	case flag.CommandValidate:
+		err = runValidate(version, args[1:], stdOut)
+	case flag.CommandSchema:
+		err = runSchema(version, args[1:], stdOut)
+	case flag.CommandRender:
+		err = runRender(ctx, version, args[1:], stdOut)
+	case flag.CommandFetch:
+		err = runFetch(ctx, version, args[1:], stdOut, stdErr)
+	case flag.CommandExport:
+		err = runExport(ctx, webFS, version, args[1:], stdOut, stdErr)
+	default:
+		return false, nil
+	}
+
+	if tinyflags.IsHelpRequested(err) || tinyflags.IsVersionRequested(err) {
+		_, _ = fmt.Fprint(stdOut, err)
+		return true, nil
+	}
+	if err != nil {
+		_, _ = fmt.Fprintln(stdErr, err)
+	}
+	return true, err
+}
+
+// runValidate loads, validates and compiles the config the same way the server does at
+// startup, without serving anything. It is meant for CI checks of config changes.
+func runValidate(version string, args []string, stdOut io.Writer) error {
+	flags, err := flag.ParseValidateArgs(args, version)
+	if err != nil {
+		return err
+	}
+
+	cfg, _, err := loadConfig(flags.Config, flags.TemplateDir)
+	if err != nil {
+		return err
+	}
+	if flags.ResolveAuth {
+		if err := cfg.ResolveProvidersAuth(); err != nil {
+			return err
+		}
+	}
+
+	// Compile every tile request so request template errors are caught as well.
+	reg, err := providers.BuildRegistry(cfg.Providers, nil)
+	if err != nil {
+		return err
+	}
+	defer reg.Close()
+
+	pool := providers.NewRunnerPool(reg)
+	boards := cfg.Boards()
+	var errs []error
+	tiles := 0
+	for _, board := range boards {
+		if _, err := pool.Build(board.Tiles); err != nil {
+			if board.Name != "" {
+				err = fmt.Errorf("dashboard %q: %w", board.Name, err)
+			}
+			errs = append(errs, err)
+		}
+		tiles += len(board.Tiles)
+	}
+	if err := errors.Join(errs...); err != nil {
+		return err
+	}
+
+	_, _ = fmt.Fprintf(stdOut, "%s: ok (%s, %s, %s)\n", flags.Config,
+		countOf(len(boards), "dashboard"), countOf(tiles, "tile"), countOf(len(cfg.Playlists), "playlist"))
+	return nil
+}
+
+// countOf formats n with noun, adding an "s" unless n is 1.
+func countOf(n int, noun string) string {
+	if n != 1 {
+		noun += "s"
+	}
+	return fmt.Sprintf("%d %s", n, noun)
+}
+
+// loadConfig loads the config and templates and validates them, as the server does at startup.
+func loadConfig(path, templateDir string) (config.DashboardConfig, *template.Template, error) {
+	cfg, err := config.LoadConfig(path)
+	if err != nil {
+		return config.DashboardConfig{}, nil, err
+	}
+	cellTmpl, err := templates.ParseCellTemplates(templateDir, templates.TemplateFuncMap())
+	if err != nil {
+		return config.DashboardConfig{}, nil, fmt.Errorf("parse templates: %w", err)
+	}
+	if err := cfg.Validate(cellTmpl, transform.Check); err != nil {
+		return config.DashboardConfig{}, nil, err
+	}
+	return cfg, cellTmpl, nil
+}
+
+// runSchema prints the JSON Schema of the config.
+func runSchema(version string, args []string, stdOut io.Writer) error {
+	if err := flag.ParseSchemaArgs(args, version); err != nil {
+		return err
+	}
+	b, err := config.SchemaJSON()
+	if err != nil {
+		return err
+	}
+	_, err = stdOut.Write(b)
+	return err
+}
@@ -0,0 +1,119 @@
+package app_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"github.com/gi8lino/tiledash/internal/app"
+	"github.com/gi8lino/tiledash/internal/testutils"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunCommands(t *testing.T) {
+	t.Parallel()
+
+	webFS := fstest.MapFS{}
+
+	t.Run("validate succeeds", func(t *testing.T) {
+		t.Parallel()
+
+		tmp := t.TempDir()
+		cfgPath := filepath.Join(tmp, "config.yaml")
+		tplDir := filepath.Join(tmp, "templates")
+		testutils.MustWriteFile(t, cfgPath, `
+grid: { columns: 1, rows: 1 }
+refreshInterval: 1s
+providers:
+  api: { baseURL: "http://localhost" }
+tiles:
+  - title: Tile
+    template: tile.gohtml
+    position: { row: 1, col: 1 }
+    request: { provider: api, path: "/{{ .Vars.x }}" }
+    vars: { x: y }
+`)
+		testutils.MustWriteFile(t, filepath.Join(tplDir, "tile.gohtml"), `{{define "tile.gohtml"}}ok{{end}}`)
+
+		var out, errOut bytes.Buffer
+		err := app.Run(t.Context(), webFS, "v1", "abc", []string{"validate", "--config=" + cfgPath, "--template-dir=" + tplDir}, &out, &errOut)
+		require.NoError(t, err)
+		assert.Equal(t, cfgPath+": ok (1 dashboard, 1 tile, 0 playlists)\n", out.String())
+		assert.Empty(t, errOut.String())
+	})
+
+	t.Run("validate reports all errors", func(t *testing.T) {
+		t.Parallel()
+
+		tmp := t.TempDir()
+		cfgPath := filepath.Join(tmp, "config.yaml")
+		tplDir := filepath.Join(tmp, "templates")
+		testutils.MustWriteFile(t, cfgPath, `
+grid: { columns: 1, rows: 1 }
+refreshInterval: 1s
+tiles:
+  - title: A
+    template: missing.gohtml
+    position: { row: 1, col: 1 }
+    request: { provider: nope, path: /x }
+`)
+		testutils.MustWriteFile(t, filepath.Join(tplDir, "tile.gohtml"), `{{define "tile.gohtml"}}ok{{end}}`)
+
+		var out, errOut bytes.Buffer
+		err := app.Run(t.Context(), webFS, "v1", "abc", []string{"validate", "--config=" + cfgPath, "--template-dir=" + tplDir}, &out, &errOut)
+		require.Error(t, err)
+		assert.Empty(t, out.String())
+		assert.Contains(t, errOut.String(), `template "missing.gohtml" not found`)
+		assert.Contains(t, errOut.String(), `provider "nope"`)
+	})
+
+	t.Run("validate reports request template errors", func(t *testing.T) {
+		t.Parallel()
+
+		tmp := t.TempDir()
+		cfgPath := filepath.Join(tmp, "config.yaml")
+		tplDir := filepath.Join(tmp, "templates")
+		testutils.MustWriteFile(t, cfgPath, `
+grid: { columns: 1, rows: 1 }
+refreshInterval: 1s
+providers:
+  api: { baseURL: "http://localhost" }
+tiles:
+  - title: Broken
+    template: tile.gohtml
+    position: { row: 1, col: 1 }
+    request: { provider: api, path: "/{{ .Vars.x" }
+`)
+		testutils.MustWriteFile(t, filepath.Join(tplDir, "tile.gohtml"), `{{define "tile.gohtml"}}ok{{end}}`)
+
+		var out, errOut bytes.Buffer
+		err := app.Run(t.Context(), webFS, "v1", "abc", []string{"validate", "--config=" + cfgPath, "--template-dir=" + tplDir}, &out, &errOut)
+		require.Error(t, err)
+		assert.Contains(t, errOut.String(), "tile 0 (Broken): request template")
+	})
+
+	t.Run("validate help", func(t *testing.T) {
+		t.Parallel()
+
+		var out, errOut bytes.Buffer
+		err := app.Run(t.Context(), webFS, "v1", "abc", []string{"validate", "--help"}, &out, &errOut)
+		require.NoError(t, err)
+		assert.Contains(t, out.String(), "--resolve-auth")
+	})
+
+	t.Run("schema prints JSON", func(t *testing.T) {
+		t.Parallel()
+
+		var out, errOut bytes.Buffer
+		err := app.Run(t.Context(), webFS, "v1", "abc", []string{"schema"}, &out, &errOut)
+		require.NoError(t, err)
+
+		var schema map[string]any
+		require.NoError(t, json.Unmarshal(out.Bytes(), &schema))
+		assert.Equal(t, "#/$defs/DashboardConfig", schema["$ref"])
+	})
+}
@@ -0,0 +1,137 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/gi8lino/tiledash/internal/config"
+	"github.com/gi8lino/tiledash/internal/flag"
+	"github.com/gi8lino/tiledash/internal/providers"
+	"github.com/gi8lino/tiledash/internal/render"
+	"github.com/gi8lino/tiledash/internal/templates"
+
+	"github.com/containeroo/httpgrace/server"
+)
+
+// exportTarget is a dashboard to snapshot with its renderer and parameters.
+type exportTarget struct {
+	name     string
+	params   config.Params
+	renderer *render.TileRenderer
+}
+
+// runExport writes static HTML snapshots of dashboards, once to a file or stdout,
+// or into a directory on a schedule until interrupted.
+func runExport(ctx context.Context, webFS fs.FS, version string, args []string, stdOut, stdErr io.Writer) error {
+	flags, err := flag.ParseExportArgs(args, version)
+	if err != nil {
+		return err
+	}
+
+	cfg, cellTmpl, err := loadConfig(flags.Config, flags.TemplateDir)
+	if err != nil {
+		return err
+	}
+	if err := cfg.ResolveProvidersAuth(); err != nil {
+		return err
+	}
+	cfg.SortCellsByPosition()
+
+	// Exporting into a directory without --dashboard snapshots every dashboard.
+	boards := cfg.Boards()
+	if flags.Dir == "" || flags.Dashboard != "" {
+		board, err := selectBoard(cfg, flags.Dashboard)
+		if err != nil {
+			return err
+		}
+		boards = []config.DashboardConfig{board}
+	}
+
+	reg, err := providers.BuildRegistry(cfg.Providers, nil)
+	if err != nil {
+		return err
+	}
+	defer reg.Close()
+
+	logger := slog.New(slog.NewTextHandler(stdErr, nil))
+	pool := providers.NewRunnerPool(reg)
+	targets := make([]exportTarget, 0, len(boards))
+	for _, board := range boards {
+		params, err := parseParams(board, flags.Params)
+		if err != nil {
+			return err
+		}
+		runners, err := pool.Build(board.Tiles)
+		if err != nil {
+			return err
+		}
+		tmpl, err := templates.ForDashboard(cellTmpl, board)
+		if err != nil {
+			return err
+		}
+		targets = append(targets, exportTarget{
+			name:     board.Name,
+			params:   params,
+			renderer: render.NewTileRenderer(board, runners, tmpl, logger),
+		})
+	}
+
+	exporter := render.NewExporter(webFS, version)
+
+	if flags.Dir == "" {
+		t := targets[0]
+		ctx = config.WithParams(ctx, t.params)
+		if flags.Out == "" {
+			return exporter.Export(ctx, stdOut, t.renderer)
+		}
+		f, err := os.Create(flags.Out)
+		if err != nil {
+			return fmt.Errorf("export: %w", err)
+		}
+		if err := exporter.Export(ctx, f, t.renderer); err != nil {
+			_ = f.Close()
+			return err
+		}
+		return f.Close()
+	}
+
+	exportAll := func() error {
+		var errs []error
+		for _, t := range targets {
+			path, err := exporter.ExportFile(config.WithParams(ctx, t.params), flags.Dir, t.renderer)
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			_, _ = fmt.Fprintln(stdOut, path)
+		}
+		return errors.Join(errs...)
+	}
+
+	if err := exportAll(); err != nil || flags.Every <= 0 {
+		return err
+	}
+
+	ctx, stop := server.SignalContext(ctx)
+	defer stop()
+
+	ticker := time.NewTicker(flags.Every)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			// A failed round is logged; the next tick tries again.
+			if err := exportAll(); err != nil {
+				logger.Error("export failed", "error", err)
+			}
+		}
+	}
+}
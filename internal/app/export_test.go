@@ -0,0 +1,116 @@
+package app_test
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/gi8lino/tiledash/internal/app"
+	"github.com/gi8lino/tiledash/internal/testutils"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunExport(t *testing.T) {
+	t.Parallel()
+
+	webFS := fstest.MapFS{
+		"web/templates/export.gohtml": &fstest.MapFile{Data: []byte(
+			`{{define "export"}}{{.Title}}:{{range .Cells}}[{{.HTML}}]{{end}}{{end}}`,
+		)},
+		"web/templates/css/page.gohtml":    &fstest.MapFile{Data: []byte(`{{define "css_page"}}{{end}}`)},
+		"web/templates/errors/tile.gohtml": &fstest.MapFile{Data: []byte(`{{define "tile_error"}}error{{end}}`)},
+	}
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, `{"count":`+strings.TrimPrefix(r.URL.Path, "/")+`}`)
+	}))
+	t.Cleanup(ts.Close)
+
+	setup := func(t *testing.T) (string, string) {
+		t.Helper()
+		tmp := t.TempDir()
+		cfgPath := filepath.Join(tmp, "config.yaml")
+		tplDir := filepath.Join(tmp, "templates")
+		testutils.MustWriteFile(t, cfgPath, `
+refreshInterval: 1s
+providers:
+  api: { baseURL: "`+ts.URL+`" }
+dashboards:
+  - name: ops
+    title: Ops
+    grid: { columns: 1, rows: 1 }
+    tiles:
+      - title: A
+        template: tile.gohtml
+        position: { row: 1, col: 1 }
+        request: { provider: api, path: /1 }
+  - name: team
+    title: Team
+    grid: { columns: 1, rows: 1 }
+    params:
+      - name: team
+        values: [payments, billing]
+    tiles:
+      - title: B
+        template: tile.gohtml
+        position: { row: 1, col: 1 }
+        request: { provider: api, path: /2 }
+`)
+		testutils.MustWriteFile(t, filepath.Join(tplDir, "tile.gohtml"),
+			`{{define "tile.gohtml"}}{{.Title}}={{.Data.count}}{{with .Params}}/{{.team}}{{end}}{{end}}`)
+		return cfgPath, tplDir
+	}
+
+	t.Run("writes one dashboard to stdout", func(t *testing.T) {
+		t.Parallel()
+
+		cfgPath, tplDir := setup(t)
+		var out, errOut bytes.Buffer
+		err := app.Run(t.Context(), webFS, "v1", "abc", []string{
+			"export", "--config=" + cfgPath, "--template-dir=" + tplDir,
+			"--dashboard=team", "--param=team=billing",
+		}, &out, &errOut)
+		require.NoError(t, err)
+		assert.Equal(t, "Team:[B=2/billing]", out.String())
+	})
+
+	t.Run("writes every dashboard into a directory", func(t *testing.T) {
+		t.Parallel()
+
+		cfgPath, tplDir := setup(t)
+		dir := filepath.Join(t.TempDir(), "snapshots")
+		var out, errOut bytes.Buffer
+		err := app.Run(t.Context(), webFS, "v1", "abc", []string{
+			"export", "--config=" + cfgPath, "--template-dir=" + tplDir, "--dir=" + dir,
+		}, &out, &errOut)
+		require.NoError(t, err)
+
+		paths := strings.Fields(out.String())
+		require.Len(t, paths, 2)
+		assert.Contains(t, filepath.Base(paths[0]), "tiledash-ops-")
+		assert.Contains(t, filepath.Base(paths[1]), "tiledash-team-")
+
+		data, err := os.ReadFile(paths[1])
+		require.NoError(t, err)
+		assert.Equal(t, "Team:[B=2/payments]", string(data))
+	})
+
+	t.Run("requires a dashboard without --dir", func(t *testing.T) {
+		t.Parallel()
+
+		cfgPath, tplDir := setup(t)
+		var out, errOut bytes.Buffer
+		err := app.Run(t.Context(), webFS, "v1", "abc", []string{
+			"export", "--config=" + cfgPath, "--template-dir=" + tplDir,
+		}, &out, &errOut)
+		require.EqualError(t, err, "--dashboard is required (one of: ops, team)")
+	})
+}
@@ -0,0 +1,114 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/gi8lino/tiledash/internal/config"
+	"github.com/gi8lino/tiledash/internal/fetcher"
+	"github.com/gi8lino/tiledash/internal/flag"
+	"github.com/gi8lino/tiledash/internal/logging"
+	"github.com/gi8lino/tiledash/internal/providers"
+)
+
+// runFetch runs one tile's upstream request with tracing and prints the accumulator as JSON.
+// Each request is logged to stdErr with its URL, headers (credentials redacted), status and
+// timing; the pages can be saved as fixtures for the render subcommand.
+func runFetch(ctx context.Context, version string, args []string, stdOut, stdErr io.Writer) error {
+	flags, err := flag.ParseFetchArgs(args, version)
+	if err != nil {
+		return err
+	}
+
+	cfg, _, err := loadConfig(flags.Config, flags.TemplateDir)
+	if err != nil {
+		return err
+	}
+	if err := cfg.ResolveProvidersAuth(); err != nil {
+		return err
+	}
+	cfg.SortCellsByPosition() // tile ids match the ones served by the API
+
+	board, err := selectBoard(cfg, flags.Dashboard)
+	if err != nil {
+		return err
+	}
+	tile, err := board.GetCellByIndex(flags.Tile)
+	if err != nil {
+		return fmt.Errorf("tile %d: %w", flags.Tile, err)
+	}
+	params, err := parseParams(board, flags.Params)
+	if err != nil {
+		return err
+	}
+
+	reg, err := providers.BuildRegistry(cfg.Providers, nil)
+	if err != nil {
+		return err
+	}
+	defer reg.Close()
+	reg.Trace(logging.SetupLogger(flag.Config{LogFormat: flags.LogFormat}, stdErr))
+
+	runners, err := providers.BuildRunners(reg, []config.Tile{tile})
+	if err != nil {
+		var tileErr *providers.TileError
+		if errors.As(err, &tileErr) {
+			tileErr.Index = flags.Tile // built on its own, so report the tile's id on the board
+		}
+		return err
+	}
+
+	ctx = config.WithParams(ctx, params)
+	ctx = context.WithValue(ctx, fetcher.ContextKey("nocache"), true)
+	acc, pages, _, runErr := runners[0].Do(ctx)
+
+	// Pages fetched before a failure are still worth keeping.
+	if flags.SaveDir != "" {
+		if err := savePages(flags.SaveDir, acc); err != nil {
+			return err
+		}
+	}
+	if runErr != nil {
+		return fmt.Errorf("tile %d (%s): failed after %d pages: %w", flags.Tile, tile.Title, pages, runErr)
+	}
+
+	enc := json.NewEncoder(stdOut)
+	enc.SetIndent("", "  ")
+	return enc.Encode(publicAccumulator(acc))
+}
+
+// publicAccumulator drops internal bookkeeping keys (e.g. "__seen") from an accumulator.
+func publicAccumulator(acc providers.Accumulator) map[string]any {
+	out := make(map[string]any, len(acc))
+	for k, v := range acc {
+		if !strings.HasPrefix(k, "__") {
+			out[k] = v
+		}
+	}
+	return out
+}
+
+// savePages writes each page of the accumulator to dir as page-1.json, page-2.json, ...
+func savePages(dir string, acc providers.Accumulator) error {
+	pages, _ := acc["pages"].([]map[string]any)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("save pages: %w", err)
+	}
+	for i, page := range pages {
+		b, err := json.MarshalIndent(page, "", "  ")
+		if err != nil {
+			return fmt.Errorf("save page %d: %w", i+1, err)
+		}
+		path := filepath.Join(dir, fmt.Sprintf("page-%d.json", i+1))
+		if err := os.WriteFile(path, append(b, '\n'), 0o644); err != nil {
+			return fmt.Errorf("save page %d: %w", i+1, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,123 @@
+package app_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"testing/fstest"
+
+	"github.com/gi8lino/tiledash/internal/app"
+	"github.com/gi8lino/tiledash/internal/testutils"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunFetch(t *testing.T) {
+	t.Parallel()
+
+	webFS := fstest.MapFS{}
+
+	// Upstream serving two pages of issues; 404 for anything else.
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/search" {
+			http.Error(w, "not found", http.StatusNotFound)
+			return
+		}
+		start, _ := strconv.Atoi(r.URL.Query().Get("startAt"))
+		_, _ = io.WriteString(w, `{"startAt":`+strconv.Itoa(start)+`,"maxResults":1,"total":2,"issues":[{"key":"A-`+strconv.Itoa(start+1)+`"}]}`)
+	}))
+	t.Cleanup(ts.Close)
+
+	setup := func(t *testing.T, path string) (string, string) {
+		t.Helper()
+		tmp := t.TempDir()
+		cfgPath := filepath.Join(tmp, "config.yaml")
+		tplDir := filepath.Join(tmp, "templates")
+		testutils.MustWriteFile(t, cfgPath, `
+grid: { columns: 1, rows: 1 }
+refreshInterval: 1s
+providers:
+  jira:
+    baseURL: `+ts.URL+`
+    auth: { bearer: { token: s3cr3t } }
+tiles:
+  - title: Issues
+    template: tile.gohtml
+    position: { row: 1, col: 1 }
+    request:
+      provider: jira
+      path: `+path+`
+      paginate: true
+      page: { startField: startAt, limitField: maxResults, totalField: total, reqStart: startAt, reqLimit: maxResults }
+`)
+		testutils.MustWriteFile(t, filepath.Join(tplDir, "tile.gohtml"), `{{define "tile.gohtml"}}ok{{end}}`)
+		return cfgPath, tplDir
+	}
+
+	t.Run("prints accumulator and traces requests", func(t *testing.T) {
+		t.Parallel()
+
+		cfgPath, tplDir := setup(t, "/search")
+		saveDir := filepath.Join(t.TempDir(), "fixtures")
+
+		var out, errOut bytes.Buffer
+		err := app.Run(t.Context(), webFS, "v1", "abc", []string{
+			"fetch", "--config=" + cfgPath, "--template-dir=" + tplDir, "--tile=0", "--save-pages=" + saveDir,
+		}, &out, &errOut)
+		require.NoError(t, err)
+
+		var acc struct {
+			Merged map[string][]map[string]any `json:"merged"`
+			Pages  []map[string]any            `json:"pages"`
+		}
+		require.NoError(t, json.Unmarshal(out.Bytes(), &acc))
+		assert.Len(t, acc.Pages, 2)
+		assert.Len(t, acc.Merged["issues"], 2)
+		assert.NotContains(t, out.String(), "__seen")
+
+		logs := errOut.String()
+		assert.Contains(t, logs, "msg=request provider=jira method=GET")
+		assert.Contains(t, logs, "status=200")
+		assert.Contains(t, logs, "Authorization:[REDACTED]")
+		assert.NotContains(t, logs, "s3cr3t")
+
+		page2, err := os.ReadFile(filepath.Join(saveDir, "page-2.json"))
+		require.NoError(t, err)
+		assert.Contains(t, string(page2), `"key": "A-2"`)
+	})
+
+	t.Run("reports upstream errors", func(t *testing.T) {
+		t.Parallel()
+
+		cfgPath, tplDir := setup(t, "/missing")
+
+		var out, errOut bytes.Buffer
+		err := app.Run(t.Context(), webFS, "v1", "abc", []string{
+			"fetch", "--config=" + cfgPath, "--template-dir=" + tplDir, "--tile=0",
+		}, &out, &errOut)
+		require.Error(t, err)
+		assert.Empty(t, out.String())
+		assert.Contains(t, errOut.String(), "status=404")
+		assert.Contains(t, errOut.String(), "tile 0 (Issues): failed after 0 pages: upstream 404")
+	})
+
+	t.Run("rejects unknown tile", func(t *testing.T) {
+		t.Parallel()
+
+		cfgPath, tplDir := setup(t, "/search")
+
+		var out, errOut bytes.Buffer
+		err := app.Run(t.Context(), webFS, "v1", "abc", []string{
+			"fetch", "--config=" + cfgPath, "--template-dir=" + tplDir, "--tile=4",
+		}, &out, &errOut)
+		require.Error(t, err)
+		assert.Contains(t, errOut.String(), "tile 4: index out of range")
+	})
+}
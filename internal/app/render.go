@@ -0,0 +1,138 @@
+package app
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/gi8lino/tiledash/internal/config"
+	"github.com/gi8lino/tiledash/internal/flag"
+	"github.com/gi8lino/tiledash/internal/providers"
+	"github.com/gi8lino/tiledash/internal/templates"
+	"github.com/gi8lino/tiledash/internal/transform"
+)
+
+// runRender renders one tile from saved JSON responses instead of calling its provider.
+// The fixtures go through the same accumulator and RenderCell path as live responses,
+// so templates can be developed and regression-tested offline.
+func runRender(ctx context.Context, version string, args []string, stdOut io.Writer) error {
+	flags, err := flag.ParseRenderArgs(args, version)
+	if err != nil {
+		return err
+	}
+
+	cfg, cellTmpl, err := loadConfig(flags.Config, flags.TemplateDir)
+	if err != nil {
+		return err
+	}
+	cfg.SortCellsByPosition() // tile ids match the ones served by the API
+
+	board, err := selectBoard(cfg, flags.Dashboard)
+	if err != nil {
+		return err
+	}
+	params, err := parseParams(board, flags.Params)
+	if err != nil {
+		return err
+	}
+	pages, err := loadFixtures(flags.Fixtures)
+	if err != nil {
+		return err
+	}
+
+	tmpl, err := templates.ForDashboard(cellTmpl, board)
+	if err != nil {
+		return err
+	}
+
+	tile, err := board.GetCellByIndex(flags.Tile)
+	if err != nil {
+		return fmt.Errorf("tile %d: %w", flags.Tile, err)
+	}
+	fn, err := transform.Compile(tile.Request.Transform.JQ, tile.Request.Transform.JMESPath)
+	if err != nil {
+		return fmt.Errorf("tile %d (%s): request.transform: %w", flags.Tile, tile.Title, err)
+	}
+
+	ctx = config.WithParams(ctx, params)
+	html, renderErr := templates.RenderCell(ctx, flags.Tile, board, tmpl, providers.AccumulatePages(pages...), fn)
+	if renderErr != nil {
+		return renderErr
+	}
+
+	if flags.Out == "" {
+		_, err = io.WriteString(stdOut, string(html))
+		return err
+	}
+	return os.WriteFile(flags.Out, []byte(html), 0o644)
+}
+
+// selectBoard returns the named dashboard, or the config itself when it has no named dashboards.
+func selectBoard(cfg config.DashboardConfig, name string) (config.DashboardConfig, error) {
+	if len(cfg.Dashboards) == 0 {
+		if name != "" {
+			return config.DashboardConfig{}, fmt.Errorf("dashboard %q: config has no named dashboards", name)
+		}
+		return cfg, nil
+	}
+	names := make([]string, 0, len(cfg.Dashboards))
+	for _, d := range cfg.Dashboards {
+		if d.Name == name {
+			return d, nil
+		}
+		names = append(names, d.Name)
+	}
+	if name == "" {
+		return config.DashboardConfig{}, fmt.Errorf("--dashboard is required (one of: %s)", strings.Join(names, ", "))
+	}
+	return config.DashboardConfig{}, fmt.Errorf("no dashboard %q (one of: %s)", name, strings.Join(names, ", "))
+}
+
+// parseParams resolves name=value pairs against the dashboard's declared parameters.
+func parseParams(board config.DashboardConfig, pairs []string) (config.Params, error) {
+	q := url.Values{}
+	for _, p := range pairs {
+		name, value, ok := strings.Cut(p, "=")
+		if !ok || name == "" {
+			return nil, fmt.Errorf("param %q: expected name=value", p)
+		}
+		q.Set(name, value)
+	}
+	return board.ResolveParams(q)
+}
+
+// loadFixtures reads saved responses in order. A file holds one JSON object (a page)
+// or an array of objects (several pages). Numbers decode as json.Number, as live responses do.
+func loadFixtures(paths []string) ([]map[string]any, error) {
+	var pages []map[string]any
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("read fixture: %w", err)
+		}
+
+		var page map[string]any
+		if err := decodeFixture(data, &page); err == nil {
+			pages = append(pages, page)
+			continue
+		}
+		var many []map[string]any
+		if err := decodeFixture(data, &many); err != nil {
+			return nil, fmt.Errorf("fixture %s: expected a JSON object or an array of objects: %w", path, err)
+		}
+		pages = append(pages, many...)
+	}
+	return pages, nil
+}
+
+// decodeFixture decodes data into v using UseNumber to preserve integer precision.
+func decodeFixture(data []byte, v any) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	return dec.Decode(v)
+}
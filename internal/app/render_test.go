@@ -0,0 +1,183 @@
+package app_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"github.com/gi8lino/tiledash/internal/app"
+	"github.com/gi8lino/tiledash/internal/testutils"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunRender(t *testing.T) {
+	t.Parallel()
+
+	webFS := fstest.MapFS{}
+
+	// setup writes a config with one tile (or one tile per named dashboard) and its template.
+	setup := func(t *testing.T, cfg, tmpl string) (string, string) {
+		t.Helper()
+		tmp := t.TempDir()
+		cfgPath := filepath.Join(tmp, "config.yaml")
+		tplDir := filepath.Join(tmp, "templates")
+		testutils.MustWriteFile(t, cfgPath, cfg)
+		testutils.MustWriteFile(t, filepath.Join(tplDir, "tile.gohtml"), tmpl)
+		return cfgPath, tplDir
+	}
+
+	const single = `
+grid: { columns: 1, rows: 1 }
+refreshInterval: 1s
+params:
+  - name: team
+    values: [payments, billing]
+providers:
+  api: { baseURL: "http://localhost" }
+tiles:
+  - title: Issues
+    template: tile.gohtml
+    position: { row: 1, col: 1 }
+    request: { provider: api, path: /search }
+`
+	const tmpl = `{{define "tile.gohtml"}}{{.Title}}/{{.Params.team}}:{{range .Data.issues}}{{.key}} {{end}}({{len .Acc.pages}} pages){{end}}`
+
+	t.Run("renders merged pages", func(t *testing.T) {
+		t.Parallel()
+
+		cfgPath, tplDir := setup(t, single, tmpl)
+		dir := filepath.Dir(cfgPath)
+		page1 := filepath.Join(dir, "page1.json")
+		page2 := filepath.Join(dir, "page2.json")
+		testutils.MustWriteFile(t, page1, `{"issues":[{"id":1,"key":"A-1"},{"id":2,"key":"A-2"}]}`)
+		testutils.MustWriteFile(t, page2, `{"issues":[{"id":3,"key":"A-3"}]}`)
+
+		var out, errOut bytes.Buffer
+		err := app.Run(t.Context(), webFS, "v1", "abc", []string{
+			"render", "--config=" + cfgPath, "--template-dir=" + tplDir,
+			"--tile=0", "--fixture=" + page1, "--fixture=" + page2, "--param=team=billing",
+		}, &out, &errOut)
+		require.NoError(t, err)
+		assert.Equal(t, "Issues/billing:A-1 A-2 A-3 (2 pages)", out.String())
+	})
+
+	t.Run("array fixture and output file", func(t *testing.T) {
+		t.Parallel()
+
+		cfgPath, tplDir := setup(t, single, tmpl)
+		dir := filepath.Dir(cfgPath)
+		fixture := filepath.Join(dir, "pages.json")
+		testutils.MustWriteFile(t, fixture, `[{"issues":[{"id":1,"key":"A-1"}]},{"issues":[{"id":2,"key":"A-2"}]}]`)
+		outFile := filepath.Join(dir, "tile.html")
+
+		var out, errOut bytes.Buffer
+		err := app.Run(t.Context(), webFS, "v1", "abc", []string{
+			"render", "--config=" + cfgPath, "--template-dir=" + tplDir,
+			"--tile=0", "--fixture=" + fixture, "--out=" + outFile,
+		}, &out, &errOut)
+		require.NoError(t, err)
+		assert.Empty(t, out.String())
+
+		html, err := os.ReadFile(outFile)
+		require.NoError(t, err)
+		assert.Equal(t, "Issues/payments:A-1 A-2 (2 pages)", string(html))
+	})
+
+	t.Run("keeps number precision like live responses", func(t *testing.T) {
+		t.Parallel()
+
+		cfgPath, tplDir := setup(t, single, `{{define "tile.gohtml"}}{{ .Data.id }}{{end}}`)
+		fixture := filepath.Join(filepath.Dir(cfgPath), "page.json")
+		testutils.MustWriteFile(t, fixture, `{"id":9007199254740993}`)
+
+		var out, errOut bytes.Buffer
+		err := app.Run(t.Context(), webFS, "v1", "abc", []string{
+			"render", "--config=" + cfgPath, "--template-dir=" + tplDir, "--tile=0", "--fixture=" + fixture,
+		}, &out, &errOut)
+		require.NoError(t, err)
+		assert.Equal(t, "9007199254740993", out.String())
+	})
+
+	t.Run("prints render errors", func(t *testing.T) {
+		t.Parallel()
+
+		cfgPath, tplDir := setup(t, single, `{{define "tile.gohtml"}}{{ index .Data "issues" 5 }}{{end}}`)
+		fixture := filepath.Join(filepath.Dir(cfgPath), "page.json")
+		testutils.MustWriteFile(t, fixture, `{"issues":[]}`)
+
+		var out, errOut bytes.Buffer
+		err := app.Run(t.Context(), webFS, "v1", "abc", []string{
+			"render", "--config=" + cfgPath, "--template-dir=" + tplDir, "--tile=0", "--fixture=" + fixture,
+		}, &out, &errOut)
+		require.Error(t, err)
+		assert.Empty(t, out.String())
+		assert.Contains(t, errOut.String(), "template: Template rendering failed")
+	})
+
+	t.Run("rejects invalid input", func(t *testing.T) {
+		t.Parallel()
+
+		cfgPath, tplDir := setup(t, single, tmpl)
+		fixture := filepath.Join(filepath.Dir(cfgPath), "page.json")
+		testutils.MustWriteFile(t, fixture, `"just a string"`)
+
+		tests := map[string][]string{
+			`expected a JSON object or an array of objects`: {"--tile=0", "--fixture=" + fixture},
+			`value "ops" is not allowed`:                    {"--tile=0", "--fixture=" + fixture, "--param=team=ops"},
+			`param "team": expected name=value`:             {"--tile=0", "--fixture=" + fixture, "--param=team"},
+			`config has no named dashboards`:                {"--tile=0", "--fixture=" + fixture, "--dashboard=x"},
+		}
+		for want, extra := range tests {
+			var out, errOut bytes.Buffer
+			args := append([]string{"render", "--config=" + cfgPath, "--template-dir=" + tplDir}, extra...)
+			err := app.Run(t.Context(), webFS, "v1", "abc", args, &out, &errOut)
+			require.Error(t, err, want)
+			assert.Contains(t, errOut.String(), want)
+		}
+	})
+
+	t.Run("selects named dashboard", func(t *testing.T) {
+		t.Parallel()
+
+		cfgPath, tplDir := setup(t, `
+refreshInterval: 1s
+providers:
+  api: { baseURL: "http://localhost" }
+dashboards:
+  - name: ops
+    grid: { columns: 1, rows: 1 }
+    tiles:
+      - title: Ops
+        template: tile.gohtml
+        position: { row: 1, col: 1 }
+        request: { provider: api, path: /ops }
+  - name: dev
+    grid: { columns: 1, rows: 1 }
+    tiles:
+      - title: Dev
+        template: tile.gohtml
+        position: { row: 1, col: 1 }
+        request: { provider: api, path: /dev }
+`, `{{define "tile.gohtml"}}{{.Title}}={{.Data.n}}{{end}}`)
+		fixture := filepath.Join(filepath.Dir(cfgPath), "page.json")
+		testutils.MustWriteFile(t, fixture, `{"n":7}`)
+
+		var out, errOut bytes.Buffer
+		err := app.Run(t.Context(), webFS, "v1", "abc", []string{
+			"render", "--config=" + cfgPath, "--template-dir=" + tplDir, "--tile=0", "--fixture=" + fixture, "--dashboard=dev",
+		}, &out, &errOut)
+		require.NoError(t, err)
+		assert.Equal(t, "Dev=7", out.String())
+
+		errOut.Reset()
+		err = app.Run(t.Context(), webFS, "v1", "abc", []string{
+			"render", "--config=" + cfgPath, "--template-dir=" + tplDir, "--tile=0", "--fixture=" + fixture,
+		}, &out, &errOut)
+		require.Error(t, err)
+		assert.Contains(t, errOut.String(), "--dashboard is required (one of: ops, dev)")
+	})
+}
@@ -3,21 +3,25 @@ package app
 import (
 	"context"
 	"fmt"
+	"html/template"
 	"io"
 	"io/fs"
 
 	"github.com/gi8lino/tiledash/internal/config"
 	"github.com/gi8lino/tiledash/internal/flag"
+	"github.com/gi8lino/tiledash/internal/handlers"
 	"github.com/gi8lino/tiledash/internal/logging"
 	"github.com/gi8lino/tiledash/internal/providers"
+	"github.com/gi8lino/tiledash/internal/render"
 	"github.com/gi8lino/tiledash/internal/routes"
 	"github.com/gi8lino/tiledash/internal/templates"
+	"github.com/gi8lino/tiledash/internal/transform"
 
 	"github.com/containeroo/httpgrace/server"
 	"github.com/containeroo/tinyflags"
 )
 
-// Run starts the tiledash application.
+// Run starts the tiledash application, or runs the subcommand given as the first argument.
 func Run(
 	ctx context.Context,
 	webFS fs.FS,
@@ -25,6 +29,11 @@ func Run(
 	args []string,
 	stdOut, stdErr io.Writer,
 ) error {
+	// Subcommands (validate, schema, render, fetch, export) run instead of the server
+	if handled, err := runCommand(ctx, webFS, version, args, stdOut, stdErr); handled {
+		return err
+	}
+
 	// Parse CLI flags
 	flags, err := flag.ParseArgs(args, version)
 	if err != nil {
@@ -56,7 +65,7 @@ func Run(
 	}
 
 	// Validate config
-	if err := cfg.Validate(cellTmpl); err != nil {
+	if err := cfg.Validate(cellTmpl, transform.Check); err != nil {
 		setupLog.Error("config validation error", "error", err)
 		return err
 	}
@@ -76,36 +85,71 @@ func Run(
 	cfg.SortCellsByPosition() // Sorts all tiles top-to-bottom, left-to-right
 
 	// Providers → registry
-	reg, err := providers.BuildRegistry(cfg.Providers) // uses config.Provider
+	newCache, closeCache, err := newCacheFactory(flags)
 	if err != nil {
-		setupLog.Error("error building registry", "error", err)
+		setupLog.Error("error setting up cache", "backend", flags.CacheBackend, "error", err)
 		return err
 	}
+	defer closeCache()
 
-	// Compile runners, one per tile
-	runners, err := providers.BuildRunners(reg, cfg.Tiles)
+	reg, err := providers.BuildRegistry(cfg.Providers, newCache)
 	if err != nil {
-		setupLog.Error("error building runners", "error", err)
+		setupLog.Error("error building registry", "error", err)
 		return err
 	}
+	defer reg.Close() // stop cache janitors
 
-	// HTTP server
+	ctx, stop := server.SignalContext(ctx)
+	defer stop()
+
+	// Compile runners (one per tile, shared across dashboards for identical requests)
+	// and a renderer per dashboard; warm renders in the background so the first page
+	// load finds hashes.
 	serverLog := logger.With("component", "server")
+	pool := providers.NewRunnerPool(reg)
+	boards := cfg.Boards()
+	dashboards := make([]handlers.Dashboard, 0, len(boards))
+	// Bind time helpers to each dashboard's timezone and locale before any tile renders.
+	boardTmpls := make([]*template.Template, len(boards))
+	for i, board := range boards {
+		if boardTmpls[i], err = templates.ForDashboard(cellTmpl, board); err != nil {
+			setupLog.Error("error preparing templates", "dashboard", board.Name, "error", err)
+			return err
+		}
+	}
+	for i, board := range boards {
+		runners, err := pool.Build(board.Tiles)
+		if err != nil {
+			setupLog.Error("error building runners", "dashboard", board.Name, "error", err)
+			return err
+		}
+		renderer := render.NewTileRenderer(board, runners, boardTmpls[i], serverLog)
+		if flags.PrewarmWorkers > 0 {
+			go func() {
+				n := renderer.Prewarm(ctx, flags.PrewarmWorkers, flags.PrewarmTimeout)
+				setupLog.Debug("Prewarmed tiles", "dashboard", board.Name, "rendered", n, "tiles", len(board.Tiles))
+			}()
+		}
+		dashboards = append(dashboards, handlers.Dashboard{Config: board, Runners: runners, Renderer: renderer})
+	}
+	if len(cfg.Dashboards) > 0 {
+		setupLog.Info("Serving dashboards", "dashboards", len(dashboards), "playlists", len(cfg.Playlists), "runners", pool.Len())
+	}
+
+	// HTTP server
 	router := routes.NewRouter(
 		webFS,
-		cellTmpl,
 		errTmpl,
-		cfg,
+		dashboards,
+		cfg.Playlists,
 		serverLog,
-		runners,
+		reg,
 		flags.Debug,
 		version,
 		flags.RoutePrefix,
+		flags.AdminToken,
 	)
 
-	ctx, stop := server.SignalContext(ctx)
-	defer stop()
-
 	if err := server.Run(ctx, flags.ListenAddr, router, serverLog); err != nil {
 		setupLog.Error("server run", "listen_address", flags.ListenAddr, "error", err)
 		return err
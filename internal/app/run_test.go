@@ -26,6 +26,7 @@ func TestRun(t *testing.T) {
 		"web/templates/footer.gohtml":      &fstest.MapFile{Data: []byte(`{{define "footer"}}f{{end}}`)},
 		"web/templates/errors/page.gohtml": &fstest.MapFile{Data: []byte(`{{define "page_error"}}err{{end}}`)},
 		"web/templates/errors/tile.gohtml": &fstest.MapFile{Data: []byte(`{{define "tile_error"}}terr{{end}}`)},
+		"web/templates/export.gohtml":      &fstest.MapFile{Data: []byte(`{{define "export"}}export{{end}}`)},
 	}
 
 	t.Run("Success (minimal config, empty tiles, ephemeral port)", func(t *testing.T) {
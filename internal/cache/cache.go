@@ -1,51 +1,302 @@
 package cache
 
 import (
+	"container/list"
+	"encoding/json"
 	"maps"
 	"sync"
 	"time"
 )
 
-// MemCache is a minimal TTL map[string] -> JSON object cache.
+// Entry is a decoded upstream page plus the HTTP validators needed to revalidate it.
+type Entry struct {
+	Value        map[string]any `json:"value"`
+	ETag         string         `json:"etag,omitempty"`
+	LastModified string         `json:"lastModified,omitempty"`
+	FreshUntil   time.Time      `json:"freshUntil,omitzero"` // after this, revalidate before use
+}
+
+// HasValidators reports whether the entry can be revalidated with a conditional request.
+func (e Entry) HasValidators() bool {
+	return e.ETag != "" || e.LastModified != ""
+}
+
+// IsFresh reports whether the entry may be served without contacting the upstream.
+func (e Entry) IsFresh(now time.Time) bool {
+	return now.Before(e.FreshUntil)
+}
+
+// Cache stores decoded upstream pages keyed by a stable request hash.
+//
+// The ttl passed to Set is the retention period; freshness is tracked separately
+// via Entry.FreshUntil so stale entries with validators can still be revalidated.
+type Cache interface {
+	// Get returns the entry for key if present and not expired.
+	Get(key string) (Entry, bool)
+	// Set stores e under key for ttl.
+	Set(key string, e Entry, ttl time.Duration)
+	// Delete removes key and reports whether it was present.
+	Delete(key string) bool
+	// Purge removes all entries and returns how many were dropped; on error the count
+	// covers the entries dropped before it.
+	Purge() (int, error)
+	// Stats returns a snapshot of the cache counters.
+	Stats() Stats
+	// Close releases background resources.
+	Close()
+}
+
+// Factory creates the cache used by a named provider.
+type Factory func(provider string) (Cache, error)
+
+var (
+	_ Cache = (*MemCache)(nil)
+	_ Cache = (*FileCache)(nil)
+	_ Cache = (*RedisCache)(nil)
+)
+
+// MemCache is a TTL map[string] -> Entry cache with optional LRU bounds.
+//
+// Entries are evicted least-recently-used first once MaxEntries or MaxBytes is
+// exceeded, and a background janitor (when enabled) drops expired entries that
+// are never read again.
 type MemCache struct {
-	mu   sync.RWMutex
-	data map[string]memItem
+	mu    sync.Mutex
+	data  map[string]*list.Element // key -> element in lru (value is *memItem)
+	lru   *list.List               // front = most recently used
+	bytes int64                    // approximate size of all values
+
+	maxEntries int
+	maxBytes   int64
+
+	hits        uint64
+	misses      uint64
+	evictions   uint64
+	expirations uint64
+
+	stop     chan struct{}
+	stopOnce sync.Once
 }
 
-// memItem stores a value and its expiry time.
+// memItem stores an entry and its expiry time.
 type memItem struct {
-	val   map[string]any
+	key   string
+	val   Entry
 	expAt time.Time
+	size  int64
+}
+
+// Option configures a MemCache or FileCache.
+type Option func(*options)
+
+// options are the settings shared by the local (memory and file) caches.
+type options struct {
+	maxEntries    int
+	maxBytes      int64
+	sweepInterval time.Duration
+}
+
+// WithMaxEntries bounds the number of entries; 0 disables the limit.
+func WithMaxEntries(n int) Option {
+	return func(o *options) { o.maxEntries = max(n, 0) }
+}
+
+// WithMaxBytes bounds the approximate size (JSON-encoded) of all values; 0 disables the limit.
+func WithMaxBytes(n int64) Option {
+	return func(o *options) { o.maxBytes = max(n, 0) }
+}
+
+// WithSweepInterval starts a janitor that drops expired entries every interval; 0 disables it.
+func WithSweepInterval(d time.Duration) Option {
+	return func(o *options) { o.sweepInterval = max(d, 0) }
+}
+
+// applyOptions returns the settings configured by opts.
+func applyOptions(opts []Option) options {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// Stats is a point-in-time snapshot of cache counters.
+type Stats struct {
+	Entries     int    `json:"entries"`
+	Bytes       int64  `json:"bytes"`
+	Hits        uint64 `json:"hits"`
+	Misses      uint64 `json:"misses"`
+	Evictions   uint64 `json:"evictions"`   // dropped to stay within bounds
+	Expirations uint64 `json:"expirations"` // dropped because the TTL passed
 }
 
 // NewMemCache constructs an in-memory TTL cache.
-func NewMemCache() *MemCache { return &MemCache{data: make(map[string]memItem)} }
+func NewMemCache(opts ...Option) *MemCache {
+	o := applyOptions(opts)
+	m := &MemCache{
+		data:       make(map[string]*list.Element),
+		lru:        list.New(),
+		maxEntries: o.maxEntries,
+		maxBytes:   o.maxBytes,
+	}
+	if o.sweepInterval > 0 {
+		m.stop = make(chan struct{})
+		go m.janitor(o.sweepInterval, m.stop)
+	}
+	return m
+}
 
-// Get retrieves a cached value if not expired.
-func (m *MemCache) Get(key string) (map[string]any, bool) {
-	m.mu.RLock()
-	item, ok := m.data[key]
-	m.mu.RUnlock()
+// Get retrieves a cached entry if not expired.
+func (m *MemCache) Get(key string) (Entry, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	el, ok := m.data[key]
 	if !ok {
-		return nil, false
+		m.misses++
+		return Entry{}, false
 	}
+	item := el.Value.(*memItem)
 	if time.Now().After(item.expAt) {
-		m.mu.Lock()
-		delete(m.data, key)
-		m.mu.Unlock()
-		return nil, false
+		m.removeElement(el)
+		m.expirations++
+		m.misses++
+		return Entry{}, false
 	}
+	m.lru.MoveToFront(el)
+	m.hits++
+
 	// return a shallow copy to avoid callers mutating cached map
-	out := make(map[string]any, len(item.val))
-	maps.Copy(out, item.val)
+	out := item.val
+	out.Value = make(map[string]any, len(item.val.Value))
+	maps.Copy(out.Value, item.val.Value)
 	return out, true
 }
 
-// Set stores a value with TTL.
-func (m *MemCache) Set(key string, v map[string]any, ttl time.Duration) {
+// Set stores an entry with TTL.
+func (m *MemCache) Set(key string, e Entry, ttl time.Duration) {
+	cp := make(map[string]any, len(e.Value))
+	maps.Copy(cp, e.Value)
+	e.Value = cp
+	item := &memItem{key: key, val: e, expAt: time.Now().Add(ttl), size: approxSize(cp)}
+
 	m.mu.Lock()
 	defer m.mu.Unlock()
-	cp := make(map[string]any, len(v))
-	maps.Copy(cp, v)
-	m.data[key] = memItem{val: cp, expAt: time.Now().Add(ttl)}
+
+	if el, ok := m.data[key]; ok {
+		m.removeElement(el)
+	}
+	m.data[key] = m.lru.PushFront(item)
+	m.bytes += item.size
+
+	m.evict()
+}
+
+// Delete removes a single key from the cache.
+func (m *MemCache) Delete(key string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	el, ok := m.data[key]
+	if ok {
+		m.removeElement(el)
+	}
+	return ok
+}
+
+// Purge removes all entries and returns how many were dropped.
+func (m *MemCache) Purge() (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	n := len(m.data)
+	m.data = make(map[string]*list.Element)
+	m.lru.Init()
+	m.bytes = 0
+	return n, nil
+}
+
+// Sweep drops all expired entries and returns how many were removed.
+func (m *MemCache) Sweep() int {
+	now := time.Now()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	n := 0
+	for el := m.lru.Back(); el != nil; {
+		prev := el.Prev()
+		if now.After(el.Value.(*memItem).expAt) {
+			m.removeElement(el)
+			n++
+		}
+		el = prev
+	}
+	m.expirations += uint64(n)
+	return n
+}
+
+// Stats returns a snapshot of the cache counters.
+func (m *MemCache) Stats() Stats {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return Stats{
+		Entries:     len(m.data),
+		Bytes:       m.bytes,
+		Hits:        m.hits,
+		Misses:      m.misses,
+		Evictions:   m.evictions,
+		Expirations: m.expirations,
+	}
+}
+
+// Close stops the janitor goroutine (if any). It is safe to call more than once.
+func (m *MemCache) Close() {
+	m.stopOnce.Do(func() {
+		if m.stop != nil {
+			close(m.stop)
+		}
+	})
+}
+
+// janitor periodically sweeps expired entries until stop is closed.
+func (m *MemCache) janitor(interval time.Duration, stop <-chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			m.Sweep()
+		case <-stop:
+			return
+		}
+	}
+}
+
+// evict drops least-recently-used entries until the cache fits its bounds; callers hold mu.
+func (m *MemCache) evict() {
+	for m.lru.Len() > 0 {
+		overEntries := m.maxEntries > 0 && len(m.data) > m.maxEntries
+		overBytes := m.maxBytes > 0 && m.bytes > m.maxBytes
+		if !overEntries && !overBytes {
+			return
+		}
+		m.removeElement(m.lru.Back())
+		m.evictions++
+	}
+}
+
+// removeElement unlinks el from the LRU list and index; callers hold mu.
+func (m *MemCache) removeElement(el *list.Element) {
+	item := el.Value.(*memItem)
+	m.lru.Remove(el)
+	delete(m.data, item.key)
+	m.bytes -= item.size
+}
+
+// approxSize estimates the memory footprint of v by its JSON-encoded length.
+func approxSize(v map[string]any) int64 {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return 0
+	}
+	return int64(len(b))
 }
@@ -24,18 +24,18 @@ func TestMemCache(t *testing.T) {
 		t.Parallel()
 		m := cache.NewMemCache()
 		in := map[string]any{"x": 1}
-		m.Set("k", in, time.Minute)
+		m.Set("k", cache.Entry{Value: in}, time.Minute)
 
 		// First retrieval
 		got1, ok := m.Get("k")
 		require.True(t, ok)
-		assert.Equal(t, 1, got1["x"])
+		assert.Equal(t, 1, got1.Value["x"])
 
 		// Mutate the map returned by Get — should NOT affect cached value
-		got1["x"] = 999
+		got1.Value["x"] = 999
 		got2, ok := m.Get("k")
 		require.True(t, ok)
-		assert.Equal(t, 1, got2["x"], "cache should not reflect mutations on a previously returned copy")
+		assert.Equal(t, 1, got2.Value["x"], "cache should not reflect mutations on a previously returned copy")
 	})
 
 	t.Run("Set stores a shallow copy; mutating original top-level after Set does not affect cache", func(t *testing.T) {
@@ -46,7 +46,7 @@ func TestMemCache(t *testing.T) {
 			// Note: nested maps are not deep-copied (documented limitation)
 			"nested": map[string]any{"y": 2},
 		}
-		m.Set("k", in, time.Minute)
+		m.Set("k", cache.Entry{Value: in}, time.Minute)
 
 		// Mutate top-level fields on the original after Set
 		in["x"] = 12345
@@ -54,8 +54,8 @@ func TestMemCache(t *testing.T) {
 
 		got, ok := m.Get("k")
 		require.True(t, ok)
-		assert.Equal(t, 1, got["x"], "top-level field should be isolated via shallow copy on Set")
-		assert.NotContains(t, got, "new", "new top-level keys added to original should not appear in cached value")
+		assert.Equal(t, 1, got.Value["x"], "top-level field should be isolated via shallow copy on Set")
+		assert.NotContains(t, got.Value, "new", "new top-level keys added to original should not appear in cached value")
 
 		// (Optional) Document shallow-copy behavior for nested structures:
 		// If we mutate the original nested map, the cache may observe it because Set is shallow.
@@ -65,13 +65,13 @@ func TestMemCache(t *testing.T) {
 		got2, ok := m.Get("k")
 		require.True(t, ok)
 		// Depending on desired semantics, this shows current implementation limitation:
-		assert.Equal(t, 777, got2["nested"].(map[string]any)["y"], "nested maps share references due to shallow copies")
+		assert.Equal(t, 777, got2.Value["nested"].(map[string]any)["y"], "nested maps share references due to shallow copies")
 	})
 
 	t.Run("TTL expiry evicts entries lazily", func(t *testing.T) {
 		t.Parallel()
 		m := cache.NewMemCache()
-		m.Set("soon", map[string]any{"v": 1}, 30*time.Millisecond)
+		m.Set("soon", cache.Entry{Value: map[string]any{"v": 1}}, 30*time.Millisecond)
 
 		// Immediately available
 		_, ok := m.Get("soon")
@@ -86,11 +86,42 @@ func TestMemCache(t *testing.T) {
 	t.Run("negative TTL results in immediate expiry", func(t *testing.T) {
 		t.Parallel()
 		m := cache.NewMemCache()
-		m.Set("neg", map[string]any{"v": 1}, -1*time.Nanosecond)
+		m.Set("neg", cache.Entry{Value: map[string]any{"v": 1}}, -1*time.Nanosecond)
 		_, ok := m.Get("neg")
 		assert.False(t, ok)
 	})
 
+	t.Run("Delete removes a single key", func(t *testing.T) {
+		t.Parallel()
+		m := cache.NewMemCache()
+		m.Set("a", cache.Entry{Value: map[string]any{"v": 1}}, time.Minute)
+		m.Set("b", cache.Entry{Value: map[string]any{"v": 2}}, time.Minute)
+
+		assert.True(t, m.Delete("a"))
+		assert.False(t, m.Delete("missing"))
+
+		_, ok := m.Get("a")
+		assert.False(t, ok)
+		_, ok = m.Get("b")
+		assert.True(t, ok)
+	})
+
+	t.Run("Purge drops everything and reports the count", func(t *testing.T) {
+		t.Parallel()
+		m := cache.NewMemCache()
+		m.Set("a", cache.Entry{Value: map[string]any{"v": 1}}, time.Minute)
+		m.Set("b", cache.Entry{Value: map[string]any{"v": 2}}, time.Minute)
+
+		n, err := m.Purge()
+		require.NoError(t, err)
+		assert.Equal(t, 2, n)
+		n, err = m.Purge()
+		require.NoError(t, err)
+		assert.Equal(t, 0, n)
+		_, ok := m.Get("a")
+		assert.False(t, ok)
+	})
+
 	t.Run("concurrent Set/Get is safe", func(t *testing.T) {
 		t.Parallel()
 		m := cache.NewMemCache()
@@ -103,7 +134,7 @@ func TestMemCache(t *testing.T) {
 			go func() {
 				defer wg.Done()
 				for i := range 200 {
-					m.Set(k, map[string]any{"i": i}, time.Second)
+					m.Set(k, cache.Entry{Value: map[string]any{"i": i}}, time.Second)
 					_, _ = m.Get(k) // best-effort read; we just care that it doesn't race/panic
 				}
 			}()
@@ -114,8 +145,109 @@ func TestMemCache(t *testing.T) {
 		for _, k := range keys {
 			got, ok := m.Get(k)
 			require.True(t, ok, "expected key %q to exist", k)
-			_, present := got["i"]
+			_, present := got.Value["i"]
 			assert.True(t, present, "expected 'i' field to exist for key %q", k)
 		}
 	})
 }
+
+func TestMemCacheBounds(t *testing.T) {
+	t.Parallel()
+
+	t.Run("MaxEntries evicts least recently used", func(t *testing.T) {
+		t.Parallel()
+		m := cache.NewMemCache(cache.WithMaxEntries(2))
+		m.Set("a", cache.Entry{Value: map[string]any{"v": 1}}, time.Minute)
+		m.Set("b", cache.Entry{Value: map[string]any{"v": 2}}, time.Minute)
+
+		// Touch "a" so "b" becomes the LRU entry.
+		_, ok := m.Get("a")
+		require.True(t, ok)
+
+		m.Set("c", cache.Entry{Value: map[string]any{"v": 3}}, time.Minute)
+
+		_, ok = m.Get("b")
+		assert.False(t, ok, "b should have been evicted")
+		_, ok = m.Get("a")
+		assert.True(t, ok)
+		_, ok = m.Get("c")
+		assert.True(t, ok)
+
+		st := m.Stats()
+		assert.Equal(t, 2, st.Entries)
+		assert.Equal(t, uint64(1), st.Evictions)
+	})
+
+	t.Run("MaxBytes evicts until the cache fits", func(t *testing.T) {
+		t.Parallel()
+		// {"v":"xxxxxxxxxx"} is 18 bytes; allow two of them.
+		m := cache.NewMemCache(cache.WithMaxBytes(40))
+		val := map[string]any{"v": "xxxxxxxxxx"}
+		m.Set("a", cache.Entry{Value: val}, time.Minute)
+		m.Set("b", cache.Entry{Value: val}, time.Minute)
+		assert.Equal(t, int64(36), m.Stats().Bytes)
+
+		m.Set("c", cache.Entry{Value: val}, time.Minute)
+		st := m.Stats()
+		assert.Equal(t, 2, st.Entries)
+		assert.Equal(t, int64(36), st.Bytes)
+		_, ok := m.Get("a")
+		assert.False(t, ok)
+	})
+
+	t.Run("overwriting a key does not grow the cache", func(t *testing.T) {
+		t.Parallel()
+		m := cache.NewMemCache(cache.WithMaxEntries(1))
+		m.Set("a", cache.Entry{Value: map[string]any{"v": 1}}, time.Minute)
+		m.Set("a", cache.Entry{Value: map[string]any{"v": 2}}, time.Minute)
+
+		got, ok := m.Get("a")
+		require.True(t, ok)
+		assert.Equal(t, 2, got.Value["v"])
+		assert.Equal(t, uint64(0), m.Stats().Evictions)
+	})
+}
+
+func TestMemCacheSweep(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Sweep drops expired entries without reads", func(t *testing.T) {
+		t.Parallel()
+		m := cache.NewMemCache()
+		m.Set("old", cache.Entry{Value: map[string]any{"v": 1}}, -time.Second)
+		m.Set("new", cache.Entry{Value: map[string]any{"v": 2}}, time.Minute)
+
+		assert.Equal(t, 1, m.Sweep())
+		st := m.Stats()
+		assert.Equal(t, 1, st.Entries)
+		assert.Equal(t, uint64(1), st.Expirations)
+	})
+
+	t.Run("janitor sweeps in the background", func(t *testing.T) {
+		t.Parallel()
+		m := cache.NewMemCache(cache.WithSweepInterval(10 * time.Millisecond))
+		t.Cleanup(m.Close)
+
+		m.Set("soon", cache.Entry{Value: map[string]any{"v": 1}}, 5*time.Millisecond)
+		assert.Eventually(t, func() bool { return m.Stats().Entries == 0 }, time.Second, 10*time.Millisecond)
+
+		m.Close()
+		m.Close() // idempotent
+	})
+}
+
+func TestMemCacheStats(t *testing.T) {
+	t.Parallel()
+
+	m := cache.NewMemCache()
+	m.Set("a", cache.Entry{Value: map[string]any{"v": 1}}, time.Minute)
+	_, _ = m.Get("a")
+	_, _ = m.Get("a")
+	_, _ = m.Get("missing")
+
+	st := m.Stats()
+	assert.Equal(t, uint64(2), st.Hits)
+	assert.Equal(t, uint64(1), st.Misses)
+	assert.Equal(t, 1, st.Entries)
+	assert.Equal(t, int64(len(`{"v":1}`)), st.Bytes)
+}
@@ -0,0 +1,329 @@
+package cache
+
+import (
+	"bytes"
+	"container/list"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// fileExt is the suffix of every entry written by FileCache.
+const fileExt = ".json"
+
+// FileCache persists entries as one JSON file per key in a directory,
+// so a restarted process can serve warm data immediately.
+//
+// Values are decoded with json.Decoder.UseNumber on read, matching how
+// providers decode upstream responses. Once MaxEntries or MaxBytes is exceeded,
+// the least recently written entries are removed first. The entry count and size
+// are tracked in memory; the directory is only scanned at startup and by Sweep.
+type FileCache struct {
+	dir string
+	mu  sync.Mutex // serializes writes/removals and guards the index; reads rely on atomic renames
+
+	files map[string]*list.Element // file name -> element in order (value is *fileItem)
+	order *list.List               // front = most recently written
+	bytes int64                    // size of all indexed files
+
+	maxEntries int
+	maxBytes   int64
+
+	hits        atomic.Uint64
+	misses      atomic.Uint64
+	evictions   atomic.Uint64
+	expirations atomic.Uint64
+
+	stop     chan struct{}
+	stopOnce sync.Once
+}
+
+// fileItem is an indexed cache file.
+type fileItem struct {
+	name string
+	size int64
+}
+
+// fileEntry is the on-disk representation of a cached entry.
+type fileEntry struct {
+	ExpiresAt time.Time `json:"expiresAt"`
+	Entry
+}
+
+// NewFileCache creates dir if needed and returns a cache persisting entries there.
+// WithSweepInterval starts a janitor that removes expired files.
+func NewFileCache(dir string, opts ...Option) (*FileCache, error) {
+	if strings.TrimSpace(dir) == "" {
+		return nil, errors.New("file cache: directory is required")
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("file cache: create directory: %w", err)
+	}
+	o := applyOptions(opts)
+	c := &FileCache{dir: dir, maxEntries: o.maxEntries, maxBytes: o.maxBytes}
+	c.mu.Lock()
+	c.reindex()
+	c.evict() // the limits may be lower than in a previous run
+	c.mu.Unlock()
+	if o.sweepInterval > 0 {
+		c.stop = make(chan struct{})
+		go c.janitor(o.sweepInterval, c.stop)
+	}
+	return c, nil
+}
+
+// Get retrieves a cached entry if present and not expired.
+func (c *FileCache) Get(key string) (Entry, bool) {
+	entry, err := c.read(c.path(key))
+	if err != nil {
+		c.misses.Add(1)
+		return Entry{}, false
+	}
+	if time.Now().After(entry.ExpiresAt) {
+		c.Delete(key)
+		c.expirations.Add(1)
+		c.misses.Add(1)
+		return Entry{}, false
+	}
+	c.hits.Add(1)
+	return entry.Entry, true
+}
+
+// Set stores an entry with TTL. Write errors are dropped; the cache is best-effort.
+func (c *FileCache) Set(key string, e Entry, ttl time.Duration) {
+	raw, err := json.Marshal(fileEntry{ExpiresAt: time.Now().Add(ttl), Entry: e})
+	if err != nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// Write to a temp file and rename so readers never observe partial entries.
+	tmp, err := os.CreateTemp(c.dir, ".tmp-*")
+	if err != nil {
+		return
+	}
+	defer os.Remove(tmp.Name()) // nolint:errcheck // no-op after a successful rename
+
+	if _, err := tmp.Write(raw); err != nil {
+		tmp.Close() // nolint:errcheck
+		return
+	}
+	if err := tmp.Close(); err != nil {
+		return
+	}
+	path := c.path(key)
+	if os.Rename(tmp.Name(), path) == nil {
+		c.track(filepath.Base(path), int64(len(raw)))
+		c.evict()
+	}
+}
+
+// Delete removes a single key from the cache.
+func (c *FileCache) Delete(key string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	path := c.path(key)
+	c.untrack(filepath.Base(path))
+	return os.Remove(path) == nil
+}
+
+// Purge removes all entries and returns how many were dropped.
+func (c *FileCache) Purge() (int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	n := 0
+	var errs []error
+	for _, name := range c.entries() {
+		switch err := os.Remove(filepath.Join(c.dir, name)); {
+		case err == nil:
+			c.untrack(name)
+			n++
+		case errors.Is(err, fs.ErrNotExist):
+			c.untrack(name)
+		default:
+			errs = append(errs, err)
+		}
+	}
+	if err := errors.Join(errs...); err != nil {
+		return n, fmt.Errorf("file cache: purge: %w", err)
+	}
+	return n, nil
+}
+
+// Sweep removes all expired entries and returns how many were dropped.
+func (c *FileCache) Sweep() int {
+	now := time.Now()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	n := 0
+	for _, name := range c.entries() {
+		path := filepath.Join(c.dir, name)
+		entry, err := c.read(path)
+		if err == nil && !now.After(entry.ExpiresAt) {
+			continue
+		}
+		// Expired or unreadable (corrupt) entries are removed alike.
+		if os.Remove(path) == nil {
+			c.untrack(name)
+			n++
+		}
+	}
+	c.expirations.Add(uint64(n))
+	return n
+}
+
+// Stats returns a snapshot of the cache counters.
+func (c *FileCache) Stats() Stats {
+	c.mu.Lock()
+	entries, bytes := c.order.Len(), c.bytes
+	c.mu.Unlock()
+	return Stats{
+		Hits:        c.hits.Load(),
+		Misses:      c.misses.Load(),
+		Evictions:   c.evictions.Load(),
+		Expirations: c.expirations.Load(),
+		Entries:     entries,
+		Bytes:       bytes,
+	}
+}
+
+// Close stops the janitor goroutine (if any). It is safe to call more than once.
+func (c *FileCache) Close() {
+	c.stopOnce.Do(func() {
+		if c.stop != nil {
+			close(c.stop)
+		}
+	})
+}
+
+// janitor periodically sweeps expired entries until stop is closed.
+func (c *FileCache) janitor(interval time.Duration, stop <-chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			c.Sweep()
+		case <-stop:
+			return
+		}
+	}
+}
+
+// evict removes the least recently written entries until the cache fits its bounds; callers hold mu.
+func (c *FileCache) evict() {
+	for {
+		overEntries := c.maxEntries > 0 && c.order.Len() > c.maxEntries
+		overBytes := c.maxBytes > 0 && c.bytes > c.maxBytes
+		if !overEntries && !overBytes {
+			return
+		}
+		it := c.order.Back().Value.(*fileItem)
+		c.untrack(it.name)
+		if os.Remove(filepath.Join(c.dir, it.name)) == nil {
+			c.evictions.Add(1)
+		}
+	}
+}
+
+// track records a written file as the most recent entry; callers hold mu.
+func (c *FileCache) track(name string, size int64) {
+	c.untrack(name)
+	c.files[name] = c.order.PushFront(&fileItem{name: name, size: size})
+	c.bytes += size
+}
+
+// untrack drops a file from the index; callers hold mu.
+func (c *FileCache) untrack(name string) {
+	if el, ok := c.files[name]; ok {
+		c.bytes -= el.Value.(*fileItem).size
+		c.order.Remove(el)
+		delete(c.files, name)
+	}
+}
+
+// reindex builds the index from the directory at startup, ordered by modification time; callers hold mu.
+func (c *FileCache) reindex() {
+	type file struct {
+		name    string
+		size    int64
+		written time.Time
+	}
+	var files []file
+	for _, name := range c.entries() {
+		if info, err := os.Stat(filepath.Join(c.dir, name)); err == nil {
+			files = append(files, file{name: name, size: info.Size(), written: info.ModTime()})
+		}
+	}
+	slices.SortStableFunc(files, func(a, b file) int { return a.written.Compare(b.written) })
+
+	c.files = make(map[string]*list.Element, len(files))
+	c.order = list.New()
+	c.bytes = 0
+	for _, f := range files {
+		c.track(f.name, f.size)
+	}
+}
+
+// path maps a cache key to its file. Keys are hex hashes; anything else is escaped defensively.
+func (c *FileCache) path(key string) string {
+	return filepath.Join(c.dir, safeFileName(key)+fileExt)
+}
+
+// entries lists the file names of all cache entries (temp files excluded).
+func (c *FileCache) entries() []string {
+	dirents, err := os.ReadDir(c.dir)
+	if err != nil {
+		return nil
+	}
+	out := make([]string, 0, len(dirents))
+	for _, d := range dirents {
+		if d.Type().IsRegular() && strings.HasSuffix(d.Name(), fileExt) {
+			out = append(out, d.Name())
+		}
+	}
+	return out
+}
+
+// read decodes a cache file, preserving number precision.
+func (c *FileCache) read(path string) (fileEntry, error) {
+	var entry fileEntry
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return entry, err
+	}
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	if err := dec.Decode(&entry); err != nil {
+		return entry, err
+	}
+	if entry.Value == nil {
+		return entry, fs.ErrNotExist
+	}
+	return entry, nil
+}
+
+// safeFileName replaces characters that are not safe in file names.
+func safeFileName(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '_':
+			return r
+		default:
+			return '_'
+		}
+	}, s)
+}
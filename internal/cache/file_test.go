@@ -0,0 +1,203 @@
+package cache_test
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gi8lino/tiledash/internal/cache"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileCache(t *testing.T) {
+	t.Parallel()
+
+	t.Run("requires a directory", func(t *testing.T) {
+		t.Parallel()
+		_, err := cache.NewFileCache(" ")
+		require.Error(t, err)
+	})
+
+	t.Run("Set then Get round-trips values with number precision", func(t *testing.T) {
+		t.Parallel()
+		c, err := cache.NewFileCache(t.TempDir())
+		require.NoError(t, err)
+
+		c.Set("k", cache.Entry{Value: map[string]any{"n": json.Number("9007199254740993"), "s": "x"}}, time.Minute)
+
+		got, ok := c.Get("k")
+		require.True(t, ok)
+		assert.Equal(t, json.Number("9007199254740993"), got.Value["n"])
+		assert.Equal(t, "x", got.Value["s"])
+	})
+
+	t.Run("validators and freshness are persisted", func(t *testing.T) {
+		t.Parallel()
+		c, err := cache.NewFileCache(t.TempDir())
+		require.NoError(t, err)
+
+		fresh := time.Now().Add(time.Minute).Truncate(time.Second)
+		c.Set("k", cache.Entry{Value: map[string]any{"v": 1}, ETag: `"abc"`, LastModified: "Mon, 02 Jan 2006 15:04:05 GMT", FreshUntil: fresh}, time.Hour)
+
+		got, ok := c.Get("k")
+		require.True(t, ok)
+		assert.Equal(t, `"abc"`, got.ETag)
+		assert.Equal(t, "Mon, 02 Jan 2006 15:04:05 GMT", got.LastModified)
+		assert.True(t, fresh.Equal(got.FreshUntil))
+		assert.True(t, got.HasValidators())
+	})
+
+	t.Run("entries survive a new instance on the same directory", func(t *testing.T) {
+		t.Parallel()
+		dir := t.TempDir()
+
+		c1, err := cache.NewFileCache(dir)
+		require.NoError(t, err)
+		c1.Set("k", cache.Entry{Value: map[string]any{"v": "warm"}}, time.Minute)
+		c1.Close()
+
+		c2, err := cache.NewFileCache(dir)
+		require.NoError(t, err)
+		got, ok := c2.Get("k")
+		require.True(t, ok)
+		assert.Equal(t, "warm", got.Value["v"])
+	})
+
+	t.Run("expired entries are dropped on read", func(t *testing.T) {
+		t.Parallel()
+		dir := t.TempDir()
+		c, err := cache.NewFileCache(dir)
+		require.NoError(t, err)
+
+		c.Set("old", cache.Entry{Value: map[string]any{"v": 1}}, -time.Second)
+		_, ok := c.Get("old")
+		assert.False(t, ok)
+
+		_, statErr := os.Stat(filepath.Join(dir, "old.json"))
+		assert.True(t, os.IsNotExist(statErr))
+	})
+
+	t.Run("Delete, Purge and Stats", func(t *testing.T) {
+		t.Parallel()
+		c, err := cache.NewFileCache(t.TempDir())
+		require.NoError(t, err)
+
+		c.Set("a", cache.Entry{Value: map[string]any{"v": 1}}, time.Minute)
+		c.Set("b", cache.Entry{Value: map[string]any{"v": 2}}, time.Minute)
+		c.Set("c", cache.Entry{Value: map[string]any{"v": 3}}, time.Minute)
+		_, _ = c.Get("a")
+		_, _ = c.Get("missing")
+
+		assert.True(t, c.Delete("a"))
+		assert.False(t, c.Delete("a"))
+		st := c.Stats()
+		assert.Equal(t, 2, st.Entries)
+		assert.Positive(t, st.Bytes)
+		assert.Equal(t, uint64(1), st.Hits)
+		assert.Equal(t, uint64(1), st.Misses)
+
+		n, err := c.Purge()
+		require.NoError(t, err)
+		assert.Equal(t, 2, n)
+		assert.Equal(t, 0, c.Stats().Entries)
+	})
+
+	t.Run("limits evict the least recently written entries", func(t *testing.T) {
+		t.Parallel()
+		c, err := cache.NewFileCache(t.TempDir(), cache.WithMaxEntries(2))
+		require.NoError(t, err)
+
+		c.Set("a", cache.Entry{Value: map[string]any{"v": 1}}, time.Minute)
+		c.Set("b", cache.Entry{Value: map[string]any{"v": 2}}, time.Minute)
+		c.Set("a", cache.Entry{Value: map[string]any{"v": 3}}, time.Minute) // rewriting makes a the newest
+		c.Set("c", cache.Entry{Value: map[string]any{"v": 4}}, time.Minute)
+
+		_, ok := c.Get("b")
+		assert.False(t, ok)
+		_, ok = c.Get("a")
+		assert.True(t, ok)
+		st := c.Stats()
+		assert.Equal(t, 2, st.Entries)
+		assert.Equal(t, uint64(1), st.Evictions)
+	})
+
+	t.Run("existing files are indexed at startup by modification time", func(t *testing.T) {
+		t.Parallel()
+		dir := t.TempDir()
+		c1, err := cache.NewFileCache(dir)
+		require.NoError(t, err)
+
+		// Backdate the writes so their order doesn't depend on the file system's timestamp resolution.
+		for i, key := range []string{"b", "a", "c"} {
+			c1.Set(key, cache.Entry{Value: map[string]any{"v": i}}, time.Minute)
+			at := time.Now().Add(time.Duration(i-10) * time.Minute)
+			require.NoError(t, os.Chtimes(filepath.Join(dir, key+".json"), at, at))
+		}
+		bytes := c1.Stats().Bytes
+
+		// Lower limits than the previous run apply right away.
+		c2, err := cache.NewFileCache(dir, cache.WithMaxEntries(2))
+		require.NoError(t, err)
+		st := c2.Stats()
+		assert.Equal(t, 2, st.Entries)
+		assert.Less(t, st.Bytes, bytes)
+		_, ok := c2.Get("b")
+		assert.False(t, ok)
+
+		c2.Set("d", cache.Entry{Value: map[string]any{"v": 4}}, time.Minute)
+		_, ok = c2.Get("a")
+		assert.False(t, ok)
+		_, ok = c2.Get("c")
+		assert.True(t, ok)
+	})
+
+	t.Run("max bytes", func(t *testing.T) {
+		t.Parallel()
+		c, err := cache.NewFileCache(t.TempDir(), cache.WithMaxBytes(1))
+		require.NoError(t, err)
+
+		c.Set("a", cache.Entry{Value: map[string]any{"v": 1}}, time.Minute)
+		assert.Equal(t, 0, c.Stats().Entries)
+	})
+
+	t.Run("Sweep removes expired and corrupt entries", func(t *testing.T) {
+		t.Parallel()
+		dir := t.TempDir()
+		c, err := cache.NewFileCache(dir)
+		require.NoError(t, err)
+
+		c.Set("old", cache.Entry{Value: map[string]any{"v": 1}}, -time.Second)
+		c.Set("new", cache.Entry{Value: map[string]any{"v": 2}}, time.Minute)
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "corrupt.json"), []byte("{"), 0o644))
+
+		assert.Equal(t, 2, c.Sweep())
+		assert.Equal(t, 1, c.Stats().Entries)
+	})
+
+	t.Run("janitor sweeps in the background", func(t *testing.T) {
+		t.Parallel()
+		c, err := cache.NewFileCache(t.TempDir(), cache.WithSweepInterval(10*time.Millisecond))
+		require.NoError(t, err)
+		t.Cleanup(c.Close)
+
+		c.Set("soon", cache.Entry{Value: map[string]any{"v": 1}}, 5*time.Millisecond)
+		assert.Eventually(t, func() bool { return c.Stats().Entries == 0 }, time.Second, 10*time.Millisecond)
+	})
+
+	t.Run("unsafe keys stay inside the directory", func(t *testing.T) {
+		t.Parallel()
+		dir := t.TempDir()
+		c, err := cache.NewFileCache(dir)
+		require.NoError(t, err)
+
+		c.Set("../escape", cache.Entry{Value: map[string]any{"v": 1}}, time.Minute)
+		_, ok := c.Get("../escape")
+		assert.True(t, ok)
+
+		_, statErr := os.Stat(filepath.Join(filepath.Dir(dir), "escape.json"))
+		assert.True(t, os.IsNotExist(statErr))
+	})
+}
@@ -0,0 +1,141 @@
+package cache
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// redisOpTimeout bounds every Redis round-trip so a slow server degrades to cache misses.
+const redisOpTimeout = 2 * time.Second
+
+// RedisCache stores entries in a Redis-protocol server so several replicas share one cache.
+//
+// Keys are namespaced as "<prefix><key>", where key is the request hash computed by the
+// fetcher; expiry is delegated to the server via the TTL passed to Set. Errors are treated
+// as cache misses (the cache is best-effort); only Purge reports them. Size bounds are left
+// to the server's maxmemory policy. The client is owned by the caller.
+type RedisCache struct {
+	client *redis.Client
+	prefix string
+
+	hits   atomic.Uint64
+	misses atomic.Uint64
+}
+
+// NewRedisCache returns a cache using client with all keys prefixed by prefix.
+func NewRedisCache(client *redis.Client, prefix string) *RedisCache {
+	return &RedisCache{client: client, prefix: prefix}
+}
+
+// Get retrieves a cached entry if present (the server drops expired keys).
+func (c *RedisCache) Get(key string) (Entry, bool) {
+	ctx, cancel := context.WithTimeout(context.Background(), redisOpTimeout)
+	defer cancel()
+
+	raw, err := c.client.Get(ctx, c.prefix+key).Bytes()
+	if err != nil {
+		c.misses.Add(1)
+		return Entry{}, false
+	}
+
+	var out Entry
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	if err := dec.Decode(&out); err != nil || out.Value == nil {
+		c.misses.Add(1)
+		return Entry{}, false
+	}
+	c.hits.Add(1)
+	return out, true
+}
+
+// Set stores an entry with TTL. Non-positive TTLs remove the key instead.
+func (c *RedisCache) Set(key string, e Entry, ttl time.Duration) {
+	if ttl <= 0 {
+		c.Delete(key)
+		return
+	}
+	raw, err := json.Marshal(e)
+	if err != nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), redisOpTimeout)
+	defer cancel()
+	_ = c.client.Set(ctx, c.prefix+key, raw, ttl).Err()
+}
+
+// Delete removes a single key from the cache.
+func (c *RedisCache) Delete(key string) bool {
+	ctx, cancel := context.WithTimeout(context.Background(), redisOpTimeout)
+	defer cancel()
+	n, err := c.client.Del(ctx, c.prefix+key).Result()
+	return err == nil && n > 0
+}
+
+// Purge removes all keys under the prefix and returns how many were dropped.
+// It stops at the first failing batch and returns the keys dropped so far with the error.
+func (c *RedisCache) Purge() (int, error) {
+	n := 0
+	err := c.scan(func(ctx context.Context, keys []string) error {
+		removed, err := c.client.Del(ctx, keys...).Result()
+		n += int(removed)
+		return err
+	})
+	if err != nil {
+		return n, fmt.Errorf("redis cache: purge: %w", err)
+	}
+	return n, nil
+}
+
+// Stats returns local hit/miss counters and the number of keys under the prefix.
+func (c *RedisCache) Stats() Stats {
+	st := Stats{
+		Hits:   c.hits.Load(),
+		Misses: c.misses.Load(),
+	}
+	_ = c.scan(func(_ context.Context, keys []string) error {
+		st.Entries += len(keys)
+		return nil
+	})
+	return st
+}
+
+// Close is a no-op; the shared client is closed by its owner.
+func (c *RedisCache) Close() {}
+
+// scan walks all keys under the prefix in batches until fn fails.
+func (c *RedisCache) scan(fn func(ctx context.Context, keys []string) error) error {
+	var cursor uint64
+	for {
+		next, err := c.scanBatch(cursor, fn)
+		if err != nil || next == 0 {
+			return err
+		}
+		cursor = next
+	}
+}
+
+// scanBatch reads one batch of keys at cursor and passes it to fn; both share one
+// redisOpTimeout, so a large keyspace isn't cut short by a single deadline.
+func (c *RedisCache) scanBatch(cursor uint64, fn func(ctx context.Context, keys []string) error) (uint64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), redisOpTimeout)
+	defer cancel()
+
+	keys, next, err := c.client.Scan(ctx, cursor, c.prefix+"*", 500).Result()
+	if err != nil {
+		return 0, err
+	}
+	if len(keys) > 0 {
+		if err := fn(ctx, keys); err != nil {
+			return 0, err
+		}
+	}
+	return next, nil
+}
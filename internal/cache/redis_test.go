@@ -0,0 +1,132 @@
+package cache_test
+
+import (
+	"encoding/json"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gi8lino/tiledash/internal/cache"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRedisCache(t *testing.T) {
+	t.Parallel()
+
+	// setup starts a dedicated in-process redis server per subtest.
+	setup := func(t *testing.T) (*miniredis.Miniredis, *redis.Client) {
+		t.Helper()
+		srv := miniredis.RunT(t)
+		client := redis.NewClient(&redis.Options{Addr: srv.Addr()})
+		t.Cleanup(func() { _ = client.Close() })
+		return srv, client
+	}
+
+	t.Run("Set then Get round-trips values with number precision", func(t *testing.T) {
+		t.Parallel()
+		srv, client := setup(t)
+		c := cache.NewRedisCache(client, "td:jira:")
+
+		c.Set("abc", cache.Entry{Value: map[string]any{"n": json.Number("9007199254740993")}}, time.Minute)
+
+		got, ok := c.Get("abc")
+		require.True(t, ok)
+		assert.Equal(t, json.Number("9007199254740993"), got.Value["n"])
+
+		assert.True(t, srv.Exists("td:jira:abc"))
+		assert.Equal(t, time.Minute, srv.TTL("td:jira:abc"))
+	})
+
+	t.Run("entries are shared between instances", func(t *testing.T) {
+		t.Parallel()
+		_, client := setup(t)
+		replicaA := cache.NewRedisCache(client, "td:jira:")
+		replicaB := cache.NewRedisCache(client, "td:jira:")
+
+		replicaA.Set("k", cache.Entry{Value: map[string]any{"v": "shared"}}, time.Minute)
+		got, ok := replicaB.Get("k")
+		require.True(t, ok)
+		assert.Equal(t, "shared", got.Value["v"])
+	})
+
+	t.Run("server-side expiry", func(t *testing.T) {
+		t.Parallel()
+		srv, client := setup(t)
+		c := cache.NewRedisCache(client, "td:jira:")
+
+		c.Set("k", cache.Entry{Value: map[string]any{"v": 1}}, time.Second)
+		srv.FastForward(2 * time.Second)
+
+		_, ok := c.Get("k")
+		assert.False(t, ok)
+	})
+
+	t.Run("non-positive TTL removes the key", func(t *testing.T) {
+		t.Parallel()
+		srv, client := setup(t)
+		c := cache.NewRedisCache(client, "td:jira:")
+
+		c.Set("k", cache.Entry{Value: map[string]any{"v": 1}}, time.Minute)
+		c.Set("k", cache.Entry{Value: map[string]any{"v": 2}}, 0)
+		assert.False(t, srv.Exists("td:jira:k"))
+	})
+
+	t.Run("Delete, Purge and Stats stay within the prefix", func(t *testing.T) {
+		t.Parallel()
+		srv, client := setup(t)
+		jira := cache.NewRedisCache(client, "td:jira:")
+		other := cache.NewRedisCache(client, "td:other:")
+
+		jira.Set("a", cache.Entry{Value: map[string]any{"v": 1}}, time.Minute)
+		jira.Set("b", cache.Entry{Value: map[string]any{"v": 2}}, time.Minute)
+		jira.Set("c", cache.Entry{Value: map[string]any{"v": 3}}, time.Minute)
+		other.Set("a", cache.Entry{Value: map[string]any{"v": 1}}, time.Minute)
+
+		_, _ = jira.Get("a")
+		_, _ = jira.Get("missing")
+		assert.True(t, jira.Delete("a"))
+		assert.False(t, jira.Delete("a"))
+
+		st := jira.Stats()
+		assert.Equal(t, 2, st.Entries)
+		assert.Equal(t, uint64(1), st.Hits)
+		assert.Equal(t, uint64(1), st.Misses)
+
+		n, err := jira.Purge()
+		require.NoError(t, err)
+		assert.Equal(t, 2, n)
+		assert.Equal(t, 0, jira.Stats().Entries)
+		assert.True(t, srv.Exists("td:other:a"), "purge must not touch other providers")
+	})
+
+	t.Run("unreachable server degrades to misses", func(t *testing.T) {
+		t.Parallel()
+		srv, client := setup(t)
+		c := cache.NewRedisCache(client, "td:jira:")
+		srv.Close()
+
+		c.Set("k", cache.Entry{Value: map[string]any{"v": 1}}, time.Minute)
+		_, ok := c.Get("k")
+		assert.False(t, ok)
+
+		_, err := c.Purge()
+		require.Error(t, err, "a failed purge must not look successful")
+	})
+
+	t.Run("Purge walks every batch", func(t *testing.T) {
+		t.Parallel()
+		_, client := setup(t)
+		c := cache.NewRedisCache(client, "td:jira:")
+		for i := range 1200 { // more than one SCAN batch
+			c.Set(strconv.Itoa(i), cache.Entry{Value: map[string]any{"v": i}}, time.Minute)
+		}
+
+		n, err := c.Purge()
+		require.NoError(t, err)
+		assert.Equal(t, 1200, n)
+	})
+}
@@ -1,16 +1,20 @@
 package config
 
 import (
-	"bytes"
+	"cmp"
 	"fmt"
 	"html/template"
+	"maps"
 	"os"
+	"regexp"
+	"slices"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/containeroo/resolver"
+	"github.com/gi8lino/tiledash/internal/timeutil"
 	"github.com/gi8lino/tiledash/internal/utils"
-	"gopkg.in/yaml.v3"
 )
 
 // Defaults for CSS customization. Values are safe and non-breaking if omitted.
@@ -31,11 +35,17 @@ const (
 	defaultFontSize           template.CSS = "16px"
 )
 
+// dashboardNameRe restricts dashboard names to URL-path-safe characters.
+var dashboardNameRe = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
 // illegalCSSChars are disallowed to reduce the risk of injecting invalid or unsafe CSS.
 var illegalCSSChars = []rune{'<', '>', '{', '}', '"', '\'', '`'}
 
-// LoadConfig reads and unmarshals the dashboard YAML at path into a DashboardConfig.
-// Unknown YAML keys are rejected (yaml.Decoder.KnownFields(true)).
+// LoadConfig reads and unmarshals the dashboard YAML at path into a DashboardConfig,
+// merging in any files listed under include and expanding tiles that extend a tile
+// template. Unknown YAML keys are rejected (yaml.Decoder.KnownFields(true)). Tiles and
+// providers remember the file and line they were defined at, so validation errors
+// point at the file to fix.
 func LoadConfig(path string) (DashboardConfig, error) {
 	var cfg DashboardConfig
 
@@ -44,13 +54,22 @@ func LoadConfig(path string) (DashboardConfig, error) {
 		return cfg, fmt.Errorf("read config: %w", err)
 	}
 
-	r := bytes.NewReader(data)
-	dec := yaml.NewDecoder(r)
-	dec.KnownFields(true) // fail on unknown YAML keys
-
-	if err := dec.Decode(&cfg); err != nil {
+	root, err := decodeFile(data, &cfg)
+	if err != nil {
 		return cfg, fmt.Errorf("parse config: %w", err)
 	}
+	annotate(root, path, fragment{
+		Providers:     cfg.Providers,
+		Tiles:         cfg.Tiles,
+		Pages:         cfg.Pages,
+		Dashboards:    cfg.Dashboards,
+		TileTemplates: cfg.TileTemplates,
+	})
+
+	if err := cfg.loadIncludes(path); err != nil {
+		return cfg, err
+	}
+	cfg.expandTileTemplates()
 	return cfg, nil
 }
 
@@ -60,19 +79,35 @@ func LoadConfig(path string) (DashboardConfig, error) {
 // This method does NOT resolve environment variables/placeholders in provider auth.
 // Call ResolveProvidersAuth() after Validate() to resolve secrets.
 //
+// Tile transforms are compiled with check (usually transform.Check); a nil check skips them.
+//
 // If any problems are found, a single aggregated error is returned.
-func (cfg *DashboardConfig) Validate(tmpl *template.Template) error {
-	var errs []string
+func (cfg *DashboardConfig) Validate(tmpl *template.Template, check TransformCheck) error {
+	errs := slices.Clone(cfg.loadErrs)
+
+	if len(cfg.Dashboards) > 0 {
+		// Named dashboards: each one is validated on its own after inheriting root settings.
+		errs = append(errs, validateDashboards(cfg, tmpl, check)...)
+	} else {
+		if cfg.Name != "" {
+			errs = append(errs, "name is only valid for entries of dashboards")
+		}
+		applyTileVars(cfg)
+		errs = append(errs, validateParams(cfg)...)
+		errs = append(errs, validateTimeSettings(cfg)...)
 
-	// Grid/tiles/template/request shape
-	errs = append(errs, validateGridAndTiles(cfg, tmpl)...)
+		// Grid/tiles/template/request shape
+		errs = append(errs, validateGridAndTiles(cfg, tmpl, check)...)
 
-	// Ensure customization exists + apply CSS defaults, then validate CSS values
-	if cfg.Customization == nil {
-		cfg.Customization = &Customization{}
+		// Ensure customization exists + apply CSS defaults, then validate CSS values
+		if cfg.Customization == nil {
+			cfg.Customization = &Customization{}
+		}
+		setStyleDefaults(cfg.Customization)
+		errs = append(errs, validateCSSs(cfg.Customization)...)
 	}
-	setStyleDefaults(cfg.Customization)
-	errs = append(errs, validateCSSs(cfg.Customization)...)
+
+	errs = append(errs, validatePlaylists(cfg)...)
 
 	// Provider-side structural checks and defaulting (no secret resolution here)
 	errs = append(errs, validateProvidersAuth(cfg)...)
@@ -135,11 +170,27 @@ func (cfg *DashboardConfig) ResolveProvidersAuth() error {
 	return nil
 }
 
-// SortCellsByPosition orders tiles top-to-bottom then left-to-right (1-based positions).
+// SortCellsByPosition orders tiles top-to-bottom then left-to-right (1-based positions),
+// for the config itself and every named dashboard. Tiles of pages are sorted per page.
 func (c *DashboardConfig) SortCellsByPosition() {
-	sort.SliceStable(c.Tiles, func(i, j int) bool {
-		pi := c.Tiles[i].Position
-		pj := c.Tiles[j].Position
+	for i := range c.Dashboards {
+		c.Dashboards[i].SortCellsByPosition()
+	}
+	if len(c.Pages) > 0 {
+		for i := range c.Pages {
+			sortTiles(c.Pages[i].Tiles)
+		}
+		c.flattenPages()
+		return
+	}
+	sortTiles(c.Tiles)
+}
+
+// sortTiles orders tiles by row, then column.
+func sortTiles(tiles []Tile) {
+	sort.SliceStable(tiles, func(i, j int) bool {
+		pi := tiles[i].Position
+		pj := tiles[j].Position
 		if pi.Row != pj.Row {
 			return pi.Row < pj.Row
 		}
@@ -147,40 +198,315 @@ func (c *DashboardConfig) SortCellsByPosition() {
 	})
 }
 
+// validateDashboards checks named dashboards. Each dashboard inherits the root providers
+// and any unset title, refreshInterval, timezone, locale, grid and customization before being validated.
+func validateDashboards(cfg *DashboardConfig, tmpl *template.Template, check TransformCheck) []string {
+	var errs []string
+
+	if len(cfg.Tiles) > 0 {
+		errs = append(errs, "tiles and dashboards are mutually exclusive; move tiles into a dashboard")
+	}
+	if len(cfg.Pages) > 0 {
+		errs = append(errs, "pages and dashboards are mutually exclusive; move pages into a dashboard")
+	}
+
+	seen := make(map[string]int, len(cfg.Dashboards))
+	for i := range cfg.Dashboards {
+		d := &cfg.Dashboards[i]
+		label := fmt.Sprintf("dashboards[%d]", i)
+
+		switch name := strings.TrimSpace(d.Name); {
+		case name == "":
+			errs = append(errs, fmt.Sprintf("%s: name is required", label))
+		case !dashboardNameRe.MatchString(name):
+			errs = append(errs, fmt.Sprintf("%s: name %q may only contain letters, digits, '-' and '_'", label, d.Name))
+		default:
+			label = fmt.Sprintf("dashboard %q", name)
+			key := strings.ToLower(name)
+			if first, dup := seen[key]; dup {
+				errs = append(errs, fmt.Sprintf("%s: duplicate name (also used by dashboards[%d])", label, first))
+			}
+			seen[key] = i
+		}
+		if len(d.Providers) > 0 {
+			errs = append(errs, fmt.Sprintf("%s: providers must be defined at the top level", label))
+		}
+		if len(d.Dashboards) > 0 {
+			errs = append(errs, fmt.Sprintf("%s: dashboards cannot be nested", label))
+		}
+		if len(d.Playlists) > 0 {
+			errs = append(errs, fmt.Sprintf("%s: playlists must be defined at the top level", label))
+		}
+		if len(d.Include) > 0 {
+			errs = append(errs, fmt.Sprintf("%s: include must be defined at the top level", label))
+		}
+		if len(d.TileTemplates) > 0 {
+			errs = append(errs, fmt.Sprintf("%s: tileTemplates must be defined at the top level", label))
+		}
+
+		inheritDashboardDefaults(d, cfg)
+		applyTileVars(d)
+		for _, e := range validateParams(d) {
+			errs = append(errs, fmt.Sprintf("%s: %s", label, e))
+		}
+		for _, e := range validateTimeSettings(d) {
+			errs = append(errs, fmt.Sprintf("%s: %s", label, e))
+		}
+
+		for _, e := range validateGridAndTiles(d, tmpl, check) {
+			errs = append(errs, fmt.Sprintf("%s: %s", label, e))
+		}
+		setStyleDefaults(d.Customization)
+		for _, e := range validateCSSs(d.Customization) {
+			errs = append(errs, fmt.Sprintf("%s: %s", label, e))
+		}
+	}
+	return errs
+}
+
+// validatePlaylists checks that playlists are uniquely named, have a dwell time for every
+// entry and only reference existing named dashboards.
+func validatePlaylists(cfg *DashboardConfig) []string {
+	var errs []string
+
+	if len(cfg.Playlists) > 0 && len(cfg.Dashboards) == 0 {
+		return append(errs, "playlists require named dashboards")
+	}
+
+	boards := make(map[string]*DashboardConfig, len(cfg.Dashboards))
+	for i := range cfg.Dashboards {
+		boards[cfg.Dashboards[i].Name] = &cfg.Dashboards[i]
+	}
+
+	seen := make(map[string]int, len(cfg.Playlists))
+	for i, p := range cfg.Playlists {
+		label := fmt.Sprintf("playlists[%d]", i)
+
+		switch name := strings.TrimSpace(p.Name); {
+		case name == "":
+			errs = append(errs, fmt.Sprintf("%s: name is required", label))
+		case !dashboardNameRe.MatchString(name):
+			errs = append(errs, fmt.Sprintf("%s: name %q may only contain letters, digits, '-' and '_'", label, p.Name))
+		default:
+			label = fmt.Sprintf("playlist %q", name)
+			key := strings.ToLower(name)
+			if first, dup := seen[key]; dup {
+				errs = append(errs, fmt.Sprintf("%s: duplicate name (also used by playlists[%d])", label, first))
+			}
+			seen[key] = i
+		}
+
+		if p.Interval < 0 {
+			errs = append(errs, fmt.Sprintf("%s: interval must be >= 0", label))
+		}
+		if len(p.Entries) == 0 {
+			errs = append(errs, fmt.Sprintf("%s: entries must not be empty", label))
+		}
+		for j, e := range p.Entries {
+			switch {
+			case strings.TrimSpace(e.Dashboard) == "":
+				errs = append(errs, fmt.Sprintf("%s: entries[%d]: dashboard is required", label, j))
+			case boards[e.Dashboard] == nil:
+				errs = append(errs, fmt.Sprintf("%s: entries[%d]: unknown dashboard %q", label, j, e.Dashboard))
+			case e.Page != "" && !slices.ContainsFunc(boards[e.Dashboard].Pages, func(p Page) bool { return p.Name == e.Page }):
+				errs = append(errs, fmt.Sprintf("%s: entries[%d]: dashboard %q has no page %q", label, j, e.Dashboard, e.Page))
+			}
+			if e.Duration < 0 {
+				errs = append(errs, fmt.Sprintf("%s: entries[%d]: duration must be >= 0", label, j))
+			} else if p.Dwell(e) <= 0 {
+				errs = append(errs, fmt.Sprintf("%s: entries[%d]: duration is required when the playlist has no interval", label, j))
+			}
+		}
+	}
+	return errs
+}
+
+// applyTileVars merges the dashboard vars into the vars of every tile (tile values win),
+// including tiles of pages.
+func applyTileVars(d *DashboardConfig) {
+	for i := range d.Tiles {
+		d.Tiles[i].Vars = mergeMaps(d.Vars, d.Tiles[i].Vars)
+	}
+	for i := range d.Pages {
+		for j := range d.Pages[i].Tiles {
+			d.Pages[i].Tiles[j].Vars = mergeMaps(d.Vars, d.Pages[i].Tiles[j].Vars)
+		}
+	}
+}
+
+// mergeMaps returns a copy of base overlaid with override (override wins); nil when both are empty.
+func mergeMaps[V any](base, override map[string]V) map[string]V {
+	if len(base) == 0 && len(override) == 0 {
+		return nil
+	}
+	out := make(map[string]V, len(base)+len(override))
+	maps.Copy(out, base)
+	maps.Copy(out, override)
+	return out
+}
+
+// inheritDashboardDefaults fills unset dashboard settings from the root config.
+func inheritDashboardDefaults(d *DashboardConfig, root *DashboardConfig) {
+	d.Providers = root.Providers // shared; resolved auth is visible to every dashboard
+	d.Vars = mergeMaps(root.Vars, d.Vars)
+	if len(d.Params) == 0 {
+		d.Params = slices.Clone(root.Params)
+	}
+	if strings.TrimSpace(d.Title) == "" {
+		d.Title = cmp.Or(root.Title, d.Name)
+	}
+	if d.RefreshInterval == 0 {
+		d.RefreshInterval = root.RefreshInterval
+	}
+	d.Timezone = cmp.Or(d.Timezone, root.Timezone)
+	d.Locale = cmp.Or(d.Locale, root.Locale)
+	if d.Grid == nil && root.Grid != nil {
+		g := *root.Grid
+		d.Grid = &g
+	}
+	if d.Customization == nil {
+		d.Customization = &Customization{}
+		if root.Customization != nil {
+			*d.Customization = *root.Customization
+		}
+	}
+}
+
+// validateTimeSettings checks the time zone and locale used by the time helpers.
+func validateTimeSettings(cfg *DashboardConfig) []string {
+	var errs []string
+	if cfg.Timezone != "" {
+		if _, err := time.LoadLocation(cfg.Timezone); err != nil {
+			errs = append(errs, fmt.Sprintf("timezone %q: unknown time zone", cfg.Timezone))
+		}
+	}
+	if cfg.Locale != "" {
+		if _, ok := timeutil.LookupLocale(cfg.Locale); !ok {
+			errs = append(errs, fmt.Sprintf("locale %q: unsupported (supported: %s)", cfg.Locale, strings.Join(timeutil.Locales(), ", ")))
+		}
+	}
+	return errs
+}
+
 // validateGridAndTiles performs structural checks for grid/tiles/template/request/pagination.
-func validateGridAndTiles(cfg *DashboardConfig, tmpl *template.Template) []string {
+// Dashboards with pages are validated page by page, each against its own grid.
+func validateGridAndTiles(cfg *DashboardConfig, tmpl *template.Template, check TransformCheck) []string {
 	var errs []string
 
-	// Basic grid & refresh constraints
-	if cfg.Grid.Columns <= 0 {
-		errs = append(errs, "grid.columns must be > 0")
+	if len(cfg.Pages) > 0 {
+		errs = append(errs, validatePages(cfg, tmpl, check)...)
+		if cfg.RefreshInterval <= 0 {
+			errs = append(errs, "refreshInterval must be > 0")
+		}
+		return errs
 	}
-	if cfg.Grid.Rows <= 0 {
-		errs = append(errs, "grid.rows must be > 0")
+
+	if cfg.Grid == nil {
+		return append(errs, "grid is required")
 	}
+
+	// Basic grid & refresh constraints
+	errs = append(errs, validateGrid(cfg.Grid)...)
 	if cfg.RefreshInterval <= 0 {
 		errs = append(errs, "refreshInterval must be > 0")
 	}
 
+	return append(errs, validateTiles(cfg.Grid, cfg.Providers, cfg.Tiles, tmpl, check)...)
+}
+
+// validatePages checks page names and validates each page's tiles against its grid,
+// which defaults to the dashboard grid. Valid or not, the pages are flattened into Tiles.
+func validatePages(cfg *DashboardConfig, tmpl *template.Template, check TransformCheck) []string {
+	var errs []string
+
+	for _, t := range cfg.Tiles {
+		if t.Page == "" {
+			errs = append(errs, "tiles and pages are mutually exclusive; move tiles into a page")
+			break
+		}
+	}
+
+	seen := make(map[string]int, len(cfg.Pages))
+	for i := range cfg.Pages {
+		p := &cfg.Pages[i]
+		label := fmt.Sprintf("pages[%d]", i)
+
+		switch name := strings.TrimSpace(p.Name); {
+		case name == "":
+			errs = append(errs, fmt.Sprintf("%s: name is required", label))
+		case !dashboardNameRe.MatchString(name):
+			errs = append(errs, fmt.Sprintf("%s: name %q may only contain letters, digits, '-' and '_'", label, p.Name))
+		default:
+			label = fmt.Sprintf("page %q", name)
+			key := strings.ToLower(name)
+			if first, dup := seen[key]; dup {
+				errs = append(errs, fmt.Sprintf("%s: duplicate name (also used by pages[%d])", label, first))
+			}
+			seen[key] = i
+		}
+
+		if strings.TrimSpace(p.Title) == "" {
+			p.Title = p.Name
+		}
+		if p.Grid == nil && cfg.Grid != nil {
+			g := *cfg.Grid
+			p.Grid = &g
+		}
+		if p.Grid == nil {
+			errs = append(errs, fmt.Sprintf("%s: grid is required", label))
+			continue
+		}
+
+		pageErrs := validateGrid(p.Grid)
+		pageErrs = append(pageErrs, validateTiles(p.Grid, cfg.Providers, p.Tiles, tmpl, check)...)
+		for _, e := range pageErrs {
+			errs = append(errs, fmt.Sprintf("%s: %s", label, e))
+		}
+	}
+
+	cfg.flattenPages()
+	return errs
+}
+
+// validateGrid checks the grid dimensions.
+func validateGrid(grid *GridConfig) []string {
+	var errs []string
+	if grid.Columns <= 0 {
+		errs = append(errs, "grid.columns must be > 0")
+	}
+	if grid.Rows <= 0 {
+		errs = append(errs, "grid.rows must be > 0")
+	}
+	return errs
+}
+
+// validateTiles checks tiles (title, template, request, pagination, position) against a grid.
+func validateTiles(grid *GridConfig, providers map[string]Provider, tiles []Tile, tmpl *template.Template, check TransformCheck) []string {
+	var errs []string
+
 	// At least one provider is required to serve tiles
-	if len(cfg.Tiles) > 0 && len(cfg.Providers) == 0 {
+	if len(tiles) > 0 && len(providers) == 0 {
 		errs = append(errs, "providers must not be empty when tiles are defined")
 	}
 
 	// Build a case-insensitive index of providers for request validation
-	provIndex := make(map[string]Provider, len(cfg.Providers))
-	for name, p := range cfg.Providers {
+	provIndex := make(map[string]Provider, len(providers))
+	for name, p := range providers {
 		key := strings.ToLower(strings.TrimSpace(name))
 		provIndex[key] = p
 	}
 
 	occupied := make(map[[2]int]string) // detect overlaps
 
-	for i, tile := range cfg.Tiles {
+	for i, tile := range tiles {
 		label := fmt.Sprintf("tile[%d]", i)
 		if t := strings.TrimSpace(tile.Title); t != "" {
 			label += fmt.Sprintf(" (%s)", t)
 		}
+		label += at(tile.origin)
+		if tile.Extends != "" {
+			label += fmt.Sprintf(" (extends %q%s)", tile.Extends, at(tile.baseOrigin))
+		}
 
 		// Title
 		if strings.TrimSpace(tile.Title) == "" {
@@ -223,8 +549,14 @@ func validateGridAndTiles(cfg *DashboardConfig, tmpl *template.Template) []strin
 			errs = append(errs, fmt.Sprintf("%s: request.ttl must be >= 0", label))
 		}
 
+		if check != nil {
+			if err := check(req.Transform.JQ, req.Transform.JMESPath); err != nil {
+				errs = append(errs, fmt.Sprintf("%s: request.transform: %v", label, err))
+			}
+		}
+
 		// Pagination wiring (only when enabled)
-		if req.Paginate {
+		if req.Paginated() {
 			loc := strings.ToUpper(strings.TrimSpace(req.Page.Location))
 			if loc != "" && loc != "QUERY" && loc != "BODY" {
 				errs = append(errs, fmt.Sprintf("%s: page.location must be 'query' or 'body'", label))
@@ -249,35 +581,44 @@ func validateGridAndTiles(cfg *DashboardConfig, tmpl *template.Template) []strin
 		if colSpan <= 0 {
 			colSpan = 1
 		}
+		rowSpan := tile.Position.RowSpan
+		if rowSpan <= 0 {
+			rowSpan = 1
+		}
 
-		maxRow := cfg.Grid.Rows - 1
-		maxCol := cfg.Grid.Columns - 1
+		maxRow := grid.Rows - 1
+		maxCol := grid.Columns - 1
 
 		if tile.Position.Row < 1 {
 			errs = append(errs, fmt.Sprintf("%s: row %d out of bounds (min 1)", label, tile.Position.Row))
 		} else if row > maxRow {
-			errs = append(errs, fmt.Sprintf("%s: row %d out of bounds (max %d)", label, tile.Position.Row, cfg.Grid.Rows))
+			errs = append(errs, fmt.Sprintf("%s: row %d out of bounds (max %d)", label, tile.Position.Row, grid.Rows))
 		}
 		if tile.Position.Col < 1 {
 			errs = append(errs, fmt.Sprintf("%s: col %d out of bounds (min 1)", label, tile.Position.Col))
 		} else if col > maxCol {
-			errs = append(errs, fmt.Sprintf("%s: col %d out of bounds (max %d)", label, tile.Position.Col, cfg.Grid.Columns))
+			errs = append(errs, fmt.Sprintf("%s: col %d out of bounds (max %d)", label, tile.Position.Col, grid.Columns))
 		}
 		if col < 0 {
 			errs = append(errs, fmt.Sprintf("%s: colSpan %d out of bounds (min 1)", label, colSpan))
-		} else if col+colSpan > cfg.Grid.Columns {
-			errs = append(errs, fmt.Sprintf("%s: colSpan %d overflows grid width %d", label, colSpan, cfg.Grid.Columns))
+		} else if col+colSpan > grid.Columns {
+			errs = append(errs, fmt.Sprintf("%s: colSpan %d overflows grid width %d", label, colSpan, grid.Columns))
+		}
+		if row >= 0 && row <= maxRow && row+rowSpan > grid.Rows {
+			errs = append(errs, fmt.Sprintf("%s: rowSpan %d overflows grid height %d", label, rowSpan, grid.Rows))
 		}
 
-		// Overlap detection across occupied cells
-		if row >= 0 && col >= 0 && col+colSpan <= cfg.Grid.Columns {
-			for c := col; c < col+colSpan; c++ {
-				key := [2]int{row, c}
-				if other, ok := occupied[key]; ok {
-					errs = append(errs, fmt.Sprintf(
-						"%s: overlaps tile (%d,%d) used by %q", label, tile.Position.Row, tile.Position.Col, other))
+		// Overlap detection across all occupied cells (rows x columns spanned)
+		if row >= 0 && col >= 0 && col+colSpan <= grid.Columns && row+rowSpan <= grid.Rows {
+			for r := row; r < row+rowSpan; r++ {
+				for c := col; c < col+colSpan; c++ {
+					key := [2]int{r, c}
+					if other, ok := occupied[key]; ok {
+						errs = append(errs, fmt.Sprintf(
+							"%s: overlaps tile (%d,%d) used by %q", label, r+1, c+1, other))
+					}
+					occupied[key] = tile.Title
 				}
-				occupied[key] = tile.Title
 			}
 		}
 	}
@@ -300,12 +641,13 @@ func validateProvidersAuth(cfg *DashboardConfig) []string {
 	var errs []string
 
 	for name, p := range cfg.Providers {
+		label := fmt.Sprintf("provider %q%s", name, at(p.origin))
 		hasBasic := p.Auth.Basic != nil
 		hasBearer := p.Auth.Bearer != nil
 
 		if hasBasic && hasBearer {
 			errs = append(errs, fmt.Sprintf(
-				`provider %q: choose exactly one auth method ("basic" or "bearer"), not both`, name))
+				`%s: choose exactly one auth method ("basic" or "bearer"), not both`, label))
 			continue
 		}
 
@@ -314,7 +656,7 @@ func validateProvidersAuth(cfg *DashboardConfig) []string {
 			pw := strings.TrimSpace(p.Auth.Basic.Password)
 			if u == "" || pw == "" {
 				errs = append(errs, fmt.Sprintf(
-					`provider %q: basic auth requires non-empty "username" and "password"`, name))
+					`%s: basic auth requires non-empty "username" and "password"`, label))
 			}
 		}
 
@@ -322,7 +664,7 @@ func validateProvidersAuth(cfg *DashboardConfig) []string {
 			t := strings.TrimSpace(p.Auth.Bearer.Token)
 			if t == "" {
 				errs = append(errs, fmt.Sprintf(
-					`provider %q: bearer auth requires non-empty "token"`, name))
+					`%s: bearer auth requires non-empty "token"`, label))
 			}
 		}
 	}
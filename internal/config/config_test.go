@@ -7,6 +7,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/gi8lino/tiledash/internal/transform"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -58,6 +59,43 @@ tiles:
 		assert.Error(t, err)
 	})
 
+	t.Run("loads named dashboards", func(t *testing.T) {
+		t.Parallel()
+
+		yaml := `
+grid:
+  rows: 2
+  columns: 2
+refreshInterval: 30s
+dashboards:
+  - name: team-a
+    tiles:
+      - title: Sample
+        template: box.gohtml
+        position: { row: 1, col: 1 }
+        request:
+          provider: p
+          path: /x
+  - name: team-b
+    title: Team B
+`
+		tmp, err := os.CreateTemp("", "test-config-*.yaml")
+		require.NoError(t, err)
+		defer os.Remove(tmp.Name()) // nolint:errcheck
+
+		_, err = tmp.WriteString(yaml)
+		require.NoError(t, err)
+		require.NoError(t, tmp.Close())
+
+		cfg, err := LoadConfig(tmp.Name())
+		require.NoError(t, err)
+
+		require.Len(t, cfg.Dashboards, 2)
+		assert.Equal(t, "team-a", cfg.Dashboards[0].Name)
+		require.Len(t, cfg.Dashboards[0].Tiles, 1)
+		assert.Equal(t, "Team B", cfg.Dashboards[1].Title)
+	})
+
 	t.Run("rejects unknown keys (KnownFields)", func(t *testing.T) {
 		t.Parallel()
 
@@ -106,7 +144,7 @@ func TestValidate(t *testing.T) {
 
 		tmpl := tmplWith(t, "card.gohtml")
 
-		err := cfg.Validate(tmpl)
+		err := cfg.Validate(tmpl, nil)
 		assert.NoError(t, err)
 	})
 
@@ -141,7 +179,7 @@ func TestValidate(t *testing.T) {
 
 		tmpl := tmplWith(t, "almost.gohtml")
 
-		err := cfg.Validate(tmpl)
+		err := cfg.Validate(tmpl, nil)
 		require.Error(t, err)
 
 		// Note: for empty provider names we expect "request.provider is required"
@@ -186,7 +224,7 @@ func TestValidate(t *testing.T) {
 
 		tmpl := tmplWith(t, "only-existing")
 
-		err := cfg.Validate(tmpl)
+		err := cfg.Validate(tmpl, nil)
 		require.Error(t, err)
 
 		expected := []string{
@@ -225,7 +263,7 @@ func TestValidate(t *testing.T) {
 
 		tmpl := tmplWith(t, "valid.gohtml", "overlapping.gohtml")
 
-		err := cfg.Validate(tmpl)
+		err := cfg.Validate(tmpl, nil)
 		require.Error(t, err)
 
 		expected := []string{
@@ -257,15 +295,44 @@ func TestValidate(t *testing.T) {
 			},
 		}
 		tmpl := tmplWith(t, "bad.gohtml")
-		err := cfg.Validate(tmpl)
+		err := cfg.Validate(tmpl, nil)
 		require.Error(t, err)
 		assert.Contains(t, err.Error(), `request.method "NONSENSE" is not a valid HTTP verb`)
 		assert.Contains(t, err.Error(), "request.ttl must be >= 0")
 	})
 
+	t.Run("rejects invalid transforms", func(t *testing.T) {
+		t.Parallel()
+
+		tile := func(title string, tr Transform) Tile {
+			return Tile{
+				Title:    title,
+				Template: "t.gohtml",
+				Position: Position{Row: 1, Col: 1},
+				Request:  Request{Provider: "p", Path: "/x", Transform: tr},
+			}
+		}
+		cfg := DashboardConfig{
+			Grid:            &GridConfig{Rows: 1, Columns: 1},
+			RefreshInterval: 5 * time.Second,
+			Providers:       map[string]Provider{"p": {}},
+			Tiles: []Tile{
+				tile("jq", Transform{JQ: ".issues["}),
+				tile("jmespath", Transform{JMESPath: "issues[?"}),
+				tile("both", Transform{JQ: ".", JMESPath: "@"}),
+			},
+		}
+		err := cfg.Validate(tmplWith(t, "t.gohtml"), transform.Check)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "tile[0] (jq): request.transform: jq: ")
+		assert.Contains(t, err.Error(), "tile[1] (jmespath): request.transform: jmespath: ")
+		assert.Contains(t, err.Error(), "tile[2] (both): request.transform: set either jq or jmespath, not both")
+	})
+
 	t.Run("pagination requires request/response markers", func(t *testing.T) {
 		t.Parallel()
 
+		paginate := true
 		cfg := DashboardConfig{
 			Grid:            &GridConfig{Rows: 1, Columns: 1},
 			RefreshInterval: 10 * time.Second,
@@ -278,7 +345,7 @@ func TestValidate(t *testing.T) {
 					Request: Request{
 						Provider: "p",
 						Path:     "/x",
-						Paginate: true,
+						Paginate: &paginate,
 						Page: PageParams{
 							Location:   "query",
 							ReqStart:   "", // missing
@@ -291,7 +358,7 @@ func TestValidate(t *testing.T) {
 			},
 		}
 		tmpl := tmplWith(t, "p.gohtml")
-		err := cfg.Validate(tmpl)
+		err := cfg.Validate(tmpl, nil)
 		require.Error(t, err)
 		assert.Contains(t, err.Error(), "page.reqStart and page.reqLimit must be set when paginate is true")
 		assert.Contains(t, err.Error(), "page.startField or page.totalField should be set")
@@ -314,7 +381,7 @@ func TestValidate(t *testing.T) {
 			},
 		}
 		tmpl := tmplWith(t, "ok.gohtml")
-		err := cfg.Validate(tmpl)
+		err := cfg.Validate(tmpl, nil)
 		require.NoError(t, err)
 	})
 
@@ -336,7 +403,7 @@ func TestValidate(t *testing.T) {
 		}
 
 		tmpl := tmplWith(t, "template-without-extension.gohtml") // registered version
-		err := cfg.Validate(tmpl)
+		err := cfg.Validate(tmpl, nil)
 		require.Error(t, err)
 
 		expected := []string{
@@ -364,7 +431,7 @@ func TestValidate(t *testing.T) {
 		}
 
 		tmpl := tmplWith(t, "default.gohtml")
-		err := cfg.Validate(tmpl)
+		err := cfg.Validate(tmpl, nil)
 		require.NoError(t, err)
 	})
 
@@ -386,12 +453,78 @@ func TestValidate(t *testing.T) {
 		}
 
 		tmpl := tmplWith(t, "wide.gohtml")
-		err := cfg.Validate(tmpl)
+		err := cfg.Validate(tmpl, nil)
 		require.Error(t, err)
 
 		assert.Contains(t, err.Error(), "colSpan 2 overflows grid width 2")
 	})
 
+	t.Run("rejects rowSpan that exceeds grid height", func(t *testing.T) {
+		t.Parallel()
+
+		cfg := DashboardConfig{
+			Grid:            &GridConfig{Rows: 2, Columns: 1},
+			RefreshInterval: 10 * time.Second,
+			Providers:       map[string]Provider{"p": {}},
+			Tiles: []Tile{
+				{
+					Title:    "tall",
+					Template: "tall.gohtml",
+					Position: Position{Row: 2, Col: 1, RowSpan: 2},
+					Request:  Request{Provider: "p", Path: "/x"},
+				},
+			},
+		}
+
+		tmpl := tmplWith(t, "tall.gohtml")
+		err := cfg.Validate(tmpl, nil)
+		require.Error(t, err)
+
+		expected := []string{
+			"  - tile[0] (tall): rowSpan 2 overflows grid height 2",
+		}
+		assert.EqualError(t, err, "config has errors:\n"+strings.Join(expected, "\n"))
+	})
+
+	t.Run("detects overlaps across spanned rows", func(t *testing.T) {
+		t.Parallel()
+
+		cfg := DashboardConfig{
+			Grid:            &GridConfig{Rows: 3, Columns: 2},
+			RefreshInterval: 10 * time.Second,
+			Providers:       map[string]Provider{"p": {}},
+			Tiles: []Tile{
+				{
+					Title:    "tall",
+					Template: "tall.gohtml",
+					Position: Position{Row: 1, Col: 1, RowSpan: 3},
+					Request:  Request{Provider: "p", Path: "/a"},
+				},
+				{
+					Title:    "side",
+					Template: "side.gohtml",
+					Position: Position{Row: 3, Col: 2},
+					Request:  Request{Provider: "p", Path: "/b"},
+				},
+				{
+					Title:    "clash",
+					Template: "clash.gohtml",
+					Position: Position{Row: 2, Col: 1, ColSpan: 2},
+					Request:  Request{Provider: "p", Path: "/c"},
+				},
+			},
+		}
+
+		tmpl := tmplWith(t, "tall.gohtml", "side.gohtml", "clash.gohtml")
+		err := cfg.Validate(tmpl, nil)
+		require.Error(t, err)
+
+		expected := []string{
+			`  - tile[2] (clash): overlaps tile (2,1) used by "tall"`,
+		}
+		assert.EqualError(t, err, "config has errors:\n"+strings.Join(expected, "\n"))
+	})
+
 	t.Run("rejects row and col less than 1", func(t *testing.T) {
 		t.Parallel()
 
@@ -410,7 +543,7 @@ func TestValidate(t *testing.T) {
 		}
 
 		tmpl := tmplWith(t, "tile.gohtml")
-		err := cfg.Validate(tmpl)
+		err := cfg.Validate(tmpl, nil)
 		require.Error(t, err)
 
 		assert.Contains(t, err.Error(), ": row 0 out of bounds")
@@ -436,7 +569,7 @@ func TestValidate(t *testing.T) {
 		}
 
 		tmpl := tmplWith(t, "default.gohtml")
-		err := cfg.Validate(tmpl)
+		err := cfg.Validate(tmpl, nil)
 		require.NoError(t, err)
 
 		require.NotNil(t, cfg.Customization)
@@ -470,7 +603,7 @@ func TestValidate(t *testing.T) {
 		}
 
 		tmpl := tmplWith(t, "t.gohtml")
-		err := cfg.Validate(tmpl)
+		err := cfg.Validate(tmpl, nil)
 		require.NoError(t, err)
 
 		// Set fields remain
@@ -485,6 +618,337 @@ func TestValidate(t *testing.T) {
 	})
 }
 
+func TestValidateDashboards(t *testing.T) {
+	t.Parallel()
+
+	tile := func(title, tmpl string, row int) Tile {
+		return Tile{
+			Title:    title,
+			Template: tmpl,
+			Position: Position{Row: row, Col: 1},
+			Request:  Request{Provider: "p", Path: "/" + title},
+		}
+	}
+
+	t.Run("inherits root settings and shares providers", func(t *testing.T) {
+		t.Parallel()
+
+		cfg := DashboardConfig{
+			Title:           "Root",
+			Grid:            &GridConfig{Rows: 2, Columns: 1},
+			RefreshInterval: 30 * time.Second,
+			Timezone:        "Europe/Zurich",
+			Locale:          "de",
+			Customization:   &Customization{Font: CustomFont{Family: "Fira Code"}},
+			Providers:       map[string]Provider{"p": {BaseURL: "http://example.com"}},
+			Dashboards: []DashboardConfig{
+				{Name: "team-a", Tiles: []Tile{tile("a", "a.gohtml", 1)}},
+				{
+					Name:            "team-b",
+					Title:           "Team B",
+					Grid:            &GridConfig{Rows: 1, Columns: 1},
+					RefreshInterval: 5 * time.Second,
+					Locale:          "fr",
+					Tiles:           []Tile{tile("b", "b.gohtml", 1)},
+				},
+			},
+		}
+
+		require.NoError(t, cfg.Validate(tmplWith(t, "a.gohtml", "b.gohtml"), nil))
+
+		boards := cfg.Boards()
+		require.Len(t, boards, 2)
+
+		a := boards[0]
+		assert.Equal(t, "Root", a.Title)
+		assert.Equal(t, 30*time.Second, a.RefreshInterval)
+		assert.Equal(t, "Europe/Zurich", a.Timezone)
+		assert.Equal(t, "de", a.Locale)
+		assert.Equal(t, 2, a.Grid.Rows)
+		assertCSS(t, "Fira Code", a.Customization.Font.Family)
+		assert.Equal(t, defaultFontSize, a.Customization.Font.Size)
+		assert.Contains(t, a.Providers, "p")
+
+		b := boards[1]
+		assert.Equal(t, "Team B", b.Title)
+		assert.Equal(t, 5*time.Second, b.RefreshInterval)
+		assert.Equal(t, "Europe/Zurich", b.Timezone)
+		assert.Equal(t, "fr", b.Locale)
+		assert.Equal(t, 1, b.Grid.Rows)
+	})
+
+	t.Run("single dashboard config is its own board", func(t *testing.T) {
+		t.Parallel()
+
+		cfg := DashboardConfig{Title: "Only"}
+		boards := cfg.Boards()
+		require.Len(t, boards, 1)
+		assert.Equal(t, "Only", boards[0].Title)
+		assert.Empty(t, boards[0].Name)
+	})
+
+	t.Run("reports invalid dashboards", func(t *testing.T) {
+		t.Parallel()
+
+		cfg := DashboardConfig{
+			RefreshInterval: 30 * time.Second,
+			Providers:       map[string]Provider{"p": {}},
+			Tiles:           []Tile{tile("root", "a.gohtml", 1)},
+			Dashboards: []DashboardConfig{
+				{Name: "ok", Grid: &GridConfig{Rows: 1, Columns: 1}, Tiles: []Tile{tile("a", "a.gohtml", 1)}},
+				{Name: "OK", Grid: &GridConfig{Rows: 1, Columns: 1}},
+				{Name: "", Grid: &GridConfig{Rows: 1, Columns: 1}},
+				{Name: "has space", Grid: &GridConfig{Rows: 1, Columns: 1}},
+				{Name: "nogrid", Providers: map[string]Provider{"x": {}}},
+				{Name: "tall", Grid: &GridConfig{Rows: 1, Columns: 1}, Tiles: []Tile{tile("t", "a.gohtml", 2)}},
+				{Name: "zoned", Grid: &GridConfig{Rows: 1, Columns: 1}, Timezone: "Mars/Olympus", Locale: "xx"},
+			},
+		}
+
+		err := cfg.Validate(tmplWith(t, "a.gohtml"), nil)
+		require.Error(t, err)
+
+		expected := []string{
+			"  - tiles and dashboards are mutually exclusive; move tiles into a dashboard",
+			`  - dashboard "OK": duplicate name (also used by dashboards[0])`,
+			"  - dashboards[2]: name is required",
+			`  - dashboards[3]: name "has space" may only contain letters, digits, '-' and '_'`,
+			`  - dashboard "nogrid": providers must be defined at the top level`,
+			`  - dashboard "nogrid": grid is required`,
+			`  - dashboard "tall": tile[0] (t): row 2 out of bounds (max 1)`,
+			`  - dashboard "zoned": timezone "Mars/Olympus": unknown time zone`,
+			`  - dashboard "zoned": locale "xx": unsupported (supported: de, en, fr, it)`,
+		}
+		assert.EqualError(t, err, "config has errors:\n"+strings.Join(expected, "\n"))
+	})
+
+	t.Run("rejects a root name without dashboards", func(t *testing.T) {
+		t.Parallel()
+
+		cfg := DashboardConfig{
+			Name:            "solo",
+			Grid:            &GridConfig{Rows: 1, Columns: 1},
+			RefreshInterval: 30 * time.Second,
+		}
+		err := cfg.Validate(tmplWith(t), nil)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "name is only valid for entries of dashboards")
+	})
+}
+
+func TestValidateVars(t *testing.T) {
+	t.Parallel()
+
+	tile := func(title string, row int, vars map[string]any) Tile {
+		return Tile{
+			Title:    title,
+			Template: "a.gohtml",
+			Position: Position{Row: row, Col: 1},
+			Request:  Request{Provider: "p", Path: "/{{ .Vars.project }}"},
+			Vars:     vars,
+		}
+	}
+
+	t.Run("tiles inherit dashboard vars", func(t *testing.T) {
+		t.Parallel()
+
+		cfg := DashboardConfig{
+			Grid:            &GridConfig{Rows: 2, Columns: 1},
+			RefreshInterval: 30 * time.Second,
+			Providers:       map[string]Provider{"p": {}},
+			Vars:            map[string]any{"project": "ABC", "filter": 1},
+			Tiles: []Tile{
+				tile("a", 1, nil),
+				tile("b", 2, map[string]any{"filter": 2}),
+			},
+		}
+		require.NoError(t, cfg.Validate(tmplWith(t, "a.gohtml"), nil))
+
+		assert.Equal(t, map[string]any{"project": "ABC", "filter": 1}, cfg.Tiles[0].Vars)
+		assert.Equal(t, map[string]any{"project": "ABC", "filter": 2}, cfg.Tiles[1].Vars)
+	})
+
+	t.Run("named dashboards and pages layer vars", func(t *testing.T) {
+		t.Parallel()
+
+		cfg := DashboardConfig{
+			Grid:            &GridConfig{Rows: 1, Columns: 1},
+			RefreshInterval: 30 * time.Second,
+			Providers:       map[string]Provider{"p": {}},
+			Vars:            map[string]any{"project": "ROOT", "env": "prod"},
+			Dashboards: []DashboardConfig{{
+				Name:  "team-a",
+				Vars:  map[string]any{"project": "A"},
+				Pages: []Page{{Name: "main", Tiles: []Tile{tile("a", 1, map[string]any{"env": "dev"})}}},
+			}},
+		}
+		require.NoError(t, cfg.Validate(tmplWith(t, "a.gohtml"), nil))
+		cfg.SortCellsByPosition()
+
+		got := cfg.Dashboards[0].Tiles[0].Vars
+		assert.Equal(t, map[string]any{"project": "A", "env": "dev"}, got)
+		assert.Equal(t, map[string]any{"project": "ROOT", "env": "prod"}, cfg.Vars, "root vars stay untouched")
+	})
+}
+
+func TestValidatePages(t *testing.T) {
+	t.Parallel()
+
+	tile := func(title string, row, col int) Tile {
+		return Tile{
+			Title:    title,
+			Template: "a.gohtml",
+			Position: Position{Row: row, Col: col},
+			Request:  Request{Provider: "p", Path: "/" + title},
+		}
+	}
+
+	t.Run("flattens pages with their own grids", func(t *testing.T) {
+		t.Parallel()
+
+		cfg := DashboardConfig{
+			Grid:            &GridConfig{Rows: 1, Columns: 1},
+			RefreshInterval: 30 * time.Second,
+			Providers:       map[string]Provider{"p": {}},
+			Pages: []Page{
+				{Name: "overview", Tiles: []Tile{tile("a", 1, 1)}},
+				{Name: "drill", Title: "Drill down", Grid: &GridConfig{Rows: 2, Columns: 2}, Tiles: []Tile{tile("c", 2, 2), tile("b", 1, 2)}},
+			},
+		}
+		require.NoError(t, cfg.Validate(tmplWith(t, "a.gohtml"), nil))
+		cfg.SortCellsByPosition()
+
+		assert.Equal(t, "overview", cfg.Pages[0].Title)
+		assert.Equal(t, 1, cfg.Pages[0].Grid.Columns)
+		assert.Equal(t, 2, cfg.Pages[1].Grid.Columns)
+
+		require.Len(t, cfg.Tiles, 3)
+		for i, want := range []struct{ title, page string }{{"a", "overview"}, {"b", "drill"}, {"c", "drill"}} {
+			assert.Equal(t, want.title, cfg.Tiles[i].Title)
+			assert.Equal(t, want.page, cfg.Tiles[i].Page)
+		}
+		assert.Equal(t, []int{1, 2}, cfg.PageTiles("drill"))
+
+		// Validating again keeps the flattened tiles valid.
+		require.NoError(t, cfg.Validate(tmplWith(t, "a.gohtml"), nil))
+		assert.Len(t, cfg.Tiles, 3)
+	})
+
+	t.Run("reports invalid pages", func(t *testing.T) {
+		t.Parallel()
+
+		cfg := DashboardConfig{
+			RefreshInterval: 30 * time.Second,
+			Providers:       map[string]Provider{"p": {}},
+			Tiles:           []Tile{tile("root", 1, 1)},
+			Pages: []Page{
+				{Name: "a", Grid: &GridConfig{Rows: 1, Columns: 1}, Tiles: []Tile{tile("x", 1, 2)}},
+				{Name: "A", Grid: &GridConfig{Rows: 1, Columns: 1}},
+				{Name: "", Grid: &GridConfig{Rows: 1, Columns: 1}},
+				{Name: "nogrid"},
+			},
+		}
+
+		err := cfg.Validate(tmplWith(t, "a.gohtml"), nil)
+		require.Error(t, err)
+
+		expected := []string{
+			"  - tiles and pages are mutually exclusive; move tiles into a page",
+			`  - page "a": tile[0] (x): col 2 out of bounds (max 1)`,
+			`  - page "a": tile[0] (x): colSpan 1 overflows grid width 1`,
+			`  - page "A": duplicate name (also used by pages[0])`,
+			"  - pages[2]: name is required",
+			`  - page "nogrid": grid is required`,
+		}
+		assert.EqualError(t, err, "config has errors:\n"+strings.Join(expected, "\n"))
+	})
+}
+
+func TestValidatePlaylists(t *testing.T) {
+	t.Parallel()
+
+	boards := func() []DashboardConfig {
+		return []DashboardConfig{{Name: "team-a"}, {Name: "team-b"}}
+	}
+	base := func(playlists ...Playlist) DashboardConfig {
+		return DashboardConfig{
+			Grid:            &GridConfig{Rows: 1, Columns: 1},
+			RefreshInterval: 30 * time.Second,
+			Dashboards:      boards(),
+			Playlists:       playlists,
+		}
+	}
+
+	t.Run("accepts valid playlists", func(t *testing.T) {
+		t.Parallel()
+
+		cfg := base(Playlist{
+			Name:     "office",
+			Interval: 30 * time.Second,
+			Entries:  []PlaylistEntry{{Dashboard: "team-a"}, {Dashboard: "team-b", Duration: time.Minute}},
+		})
+		require.NoError(t, cfg.Validate(tmplWith(t), nil))
+
+		p := cfg.Playlists[0]
+		assert.Equal(t, 30*time.Second, p.Dwell(p.Entries[0]))
+		assert.Equal(t, time.Minute, p.Dwell(p.Entries[1]))
+	})
+
+	t.Run("reports invalid playlists", func(t *testing.T) {
+		t.Parallel()
+
+		cfg := base(
+			Playlist{Name: "office", Interval: time.Second, Entries: []PlaylistEntry{{Dashboard: "team-a"}}},
+			Playlist{Name: "Office", Interval: time.Second, Entries: []PlaylistEntry{{Dashboard: "team-a"}}},
+			Playlist{Name: "", Entries: []PlaylistEntry{{Dashboard: "team-a", Duration: time.Second}}},
+			Playlist{Name: "no/slash", Interval: time.Second, Entries: []PlaylistEntry{{Dashboard: "team-a"}}},
+			Playlist{Name: "empty", Interval: time.Second},
+			Playlist{Name: "bad", Entries: []PlaylistEntry{{Dashboard: "nope"}, {}, {Dashboard: "team-b", Duration: -time.Second}}},
+		)
+
+		err := cfg.Validate(tmplWith(t), nil)
+		require.Error(t, err)
+
+		expected := []string{
+			`  - playlist "Office": duplicate name (also used by playlists[0])`,
+			"  - playlists[2]: name is required",
+			`  - playlists[3]: name "no/slash" may only contain letters, digits, '-' and '_'`,
+			`  - playlist "empty": entries must not be empty`,
+			`  - playlist "bad": entries[0]: unknown dashboard "nope"`,
+			`  - playlist "bad": entries[0]: duration is required when the playlist has no interval`,
+			`  - playlist "bad": entries[1]: dashboard is required`,
+			`  - playlist "bad": entries[1]: duration is required when the playlist has no interval`,
+			`  - playlist "bad": entries[2]: duration must be >= 0`,
+		}
+		assert.EqualError(t, err, "config has errors:\n"+strings.Join(expected, "\n"))
+	})
+
+	t.Run("requires named dashboards", func(t *testing.T) {
+		t.Parallel()
+
+		cfg := base(Playlist{Name: "office", Interval: time.Second, Entries: []PlaylistEntry{{Dashboard: "team-a"}}})
+		cfg.Dashboards = nil
+		err := cfg.Validate(tmplWith(t), nil)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "playlists require named dashboards")
+	})
+
+	t.Run("checks entry pages", func(t *testing.T) {
+		t.Parallel()
+
+		cfg := base(Playlist{
+			Name:     "office",
+			Interval: time.Second,
+			Entries:  []PlaylistEntry{{Dashboard: "team-a", Page: "ops"}, {Dashboard: "team-a", Page: "nope"}},
+		})
+		cfg.Dashboards[0].Pages = []Page{{Name: "ops"}}
+		err := cfg.Validate(tmplWith(t), nil)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `playlist "office": entries[1]: dashboard "team-a" has no page "nope"`)
+		assert.NotContains(t, err.Error(), "entries[0]")
+	})
+}
+
 func TestResolveProvidersAuth(t *testing.T) {
 	//	t.Parallel()  // Paralell inpossible due GetEnv
 
@@ -557,7 +1021,7 @@ func TestResolveProvidersAuth(t *testing.T) {
 			},
 		}
 		tmpl := tmplWith(t, "ok.gohtml")
-		err := cfg.Validate(tmpl)
+		err := cfg.Validate(tmpl, nil)
 		require.NoError(t, err)
 		// still unresolved here
 		assert.Equal(t, "env:TOK", cfg.Providers["p"].Auth.Bearer.Token)
@@ -0,0 +1,94 @@
+package config
+
+import "fmt"
+
+// expandTileTemplates replaces every tile that extends a tile template with the template
+// overlaid by the tile's own settings. Unknown templates and templates that extend
+// another template are recorded for Validate to report.
+func (cfg *DashboardConfig) expandTileTemplates() {
+	for _, name := range sortedKeys(cfg.TileTemplates) {
+		if base := cfg.TileTemplates[name]; base.Extends != "" {
+			cfg.loadErrs = append(cfg.loadErrs, fmt.Sprintf(
+				"tile template %q%s: tile templates cannot extend other templates", name, at(base.origin)))
+		}
+	}
+
+	expand := func(tiles []Tile) {
+		for i, t := range tiles {
+			if t.Extends == "" {
+				continue
+			}
+			base, ok := cfg.TileTemplates[t.Extends]
+			if !ok {
+				cfg.loadErrs = append(cfg.loadErrs, fmt.Sprintf(
+					"tile %q%s: extends unknown tile template %q", t.Title, at(t.origin), t.Extends))
+				continue
+			}
+			tiles[i] = extendTile(base, t)
+		}
+	}
+
+	expand(cfg.Tiles)
+	for i := range cfg.Pages {
+		expand(cfg.Pages[i].Tiles)
+	}
+	for i := range cfg.Dashboards {
+		d := &cfg.Dashboards[i]
+		expand(d.Tiles)
+		for j := range d.Pages {
+			expand(d.Pages[j].Tiles)
+		}
+	}
+}
+
+// extendTile overlays the non-zero settings of t onto base. Maps (vars, query, headers,
+// bodyJSON) are merged key by key; base is never modified.
+func extendTile(base, t Tile) Tile {
+	out := base
+	out.Extends = t.Extends
+	out.origin = t.origin
+	out.baseOrigin = base.origin
+
+	out.Title = overlay(base.Title, t.Title)
+	out.Template = overlay(base.Template, t.Template)
+	out.Position = Position{
+		Row:     overlay(base.Position.Row, t.Position.Row),
+		Col:     overlay(base.Position.Col, t.Position.Col),
+		ColSpan: overlay(base.Position.ColSpan, t.Position.ColSpan),
+		RowSpan: overlay(base.Position.RowSpan, t.Position.RowSpan),
+	}
+	out.Vars = mergeMaps(base.Vars, t.Vars)
+
+	b, r := base.Request, t.Request
+	out.Request = Request{
+		Provider: overlay(b.Provider, r.Provider),
+		Method:   overlay(b.Method, r.Method),
+		Path:     overlay(b.Path, r.Path),
+		TTL:      overlay(b.TTL, r.TTL),
+		Query:    mergeMaps(b.Query, r.Query),
+		Headers:  mergeMaps(b.Headers, r.Headers),
+		Body:     overlay(b.Body, r.Body),
+		BodyJSON: mergeMaps(b.BodyJSON, r.BodyJSON),
+		Paginate: overlay(b.Paginate, r.Paginate),
+		Page: PageParams{
+			Location:   overlay(b.Page.Location, r.Page.Location),
+			StartField: overlay(b.Page.StartField, r.Page.StartField),
+			LimitField: overlay(b.Page.LimitField, r.Page.LimitField),
+			TotalField: overlay(b.Page.TotalField, r.Page.TotalField),
+			ReqStart:   overlay(b.Page.ReqStart, r.Page.ReqStart),
+			ReqLimit:   overlay(b.Page.ReqLimit, r.Page.ReqLimit),
+			LimitPages: overlay(b.Page.LimitPages, r.Page.LimitPages),
+		},
+		Transform: overlay(b.Transform, r.Transform),
+	}
+	return out
+}
+
+// overlay returns override unless it is the zero value (for pointers: unless it is nil).
+func overlay[T comparable](base, override T) T {
+	var zero T
+	if override != zero {
+		return override
+	}
+	return base
+}
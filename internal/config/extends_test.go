@@ -0,0 +1,162 @@
+package config
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gi8lino/tiledash/internal/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExtendTile(t *testing.T) {
+	t.Parallel()
+
+	base := Tile{
+		Title:    "Filter",
+		Template: "issues.gohtml",
+		Position: Position{ColSpan: 2},
+		Vars:     map[string]any{"filter": 1, "project": "ABC"},
+		Request: Request{
+			Provider:  "jira",
+			Path:      "/rest/api/2/search",
+			TTL:       time.Minute,
+			Query:     map[string]string{"jql": "filter={{ .Vars.filter }}", "maxResults": "50"},
+			Transform: Transform{JQ: ".issues | length"},
+		},
+		origin: "base.yaml:2",
+	}
+	tile := Tile{
+		Extends:  "jira-filter",
+		Title:    "Team A bugs",
+		Position: Position{Row: 2, Col: 1},
+		Vars:     map[string]any{"filter": 42},
+		Request:  Request{Query: map[string]string{"maxResults": "10"}},
+		origin:   "team.yaml:7",
+	}
+
+	got := extendTile(base, tile)
+
+	assert.Equal(t, "Team A bugs", got.Title)
+	assert.Equal(t, "issues.gohtml", got.Template)
+	assert.Equal(t, Position{Row: 2, Col: 1, ColSpan: 2}, got.Position)
+	assert.Equal(t, map[string]any{"filter": 42, "project": "ABC"}, got.Vars)
+	assert.Equal(t, "jira", got.Request.Provider)
+	assert.Equal(t, time.Minute, got.Request.TTL)
+	assert.Equal(t, Transform{JQ: ".issues | length"}, got.Request.Transform)
+	assert.Equal(t, map[string]string{"jql": "filter={{ .Vars.filter }}", "maxResults": "10"}, got.Request.Query)
+	assert.Equal(t, "team.yaml:7", got.origin)
+	assert.Equal(t, "base.yaml:2", got.baseOrigin)
+
+	// The base is shared by many tiles and must stay untouched.
+	assert.Equal(t, 1, base.Vars["filter"])
+	assert.Equal(t, "50", base.Request.Query["maxResults"])
+}
+
+func TestExtendTilePaginate(t *testing.T) {
+	t.Parallel()
+
+	on, off := true, false
+	base := Tile{Request: Request{Provider: "jira", Paginate: &on}}
+
+	t.Run("inherited when unset", func(t *testing.T) {
+		t.Parallel()
+		got := extendTile(base, Tile{Extends: "jira"})
+		assert.True(t, got.Request.Paginated())
+	})
+
+	t.Run("tile can turn it off", func(t *testing.T) {
+		t.Parallel()
+		got := extendTile(base, Tile{Extends: "jira", Request: Request{Paginate: &off}})
+		assert.False(t, got.Request.Paginated())
+		assert.True(t, base.Request.Paginated())
+	})
+}
+
+func TestLoadConfigTileTemplates(t *testing.T) {
+	t.Parallel()
+
+	t.Run("expands tiles before validation", func(t *testing.T) {
+		t.Parallel()
+
+		dir := t.TempDir()
+		main := filepath.Join(dir, "config.yaml")
+		testutils.MustWriteFile(t, main, `
+grid: { rows: 2, columns: 2 }
+refreshInterval: 30s
+providers:
+  jira: { baseURL: "https://jira.example.com" }
+tileTemplates:
+  jira-filter:
+    template: a.gohtml
+    vars: { filter: 1 }
+    request:
+      provider: jira
+      path: /rest/api/2/search
+      query: { jql: "filter={{ .Vars.filter }}" }
+tiles:
+  - extends: jira-filter
+    title: Team A
+    position: { row: 1, col: 1 }
+    vars: { filter: 10 }
+  - extends: jira-filter
+    title: Team B
+    position: { row: 1, col: 2 }
+`)
+
+		cfg, err := LoadConfig(main)
+		require.NoError(t, err)
+		require.NoError(t, cfg.Validate(tmplWith(t, "a.gohtml"), nil))
+
+		require.Len(t, cfg.Tiles, 2)
+		assert.Equal(t, "a.gohtml", cfg.Tiles[0].Template)
+		assert.Equal(t, map[string]any{"filter": 10}, cfg.Tiles[0].Vars)
+		assert.Equal(t, map[string]any{"filter": 1}, cfg.Tiles[1].Vars)
+		assert.Equal(t, "/rest/api/2/search", cfg.Tiles[1].Request.Path)
+	})
+
+	t.Run("reports errors referencing tile and base", func(t *testing.T) {
+		t.Parallel()
+
+		dir := t.TempDir()
+		main := filepath.Join(dir, "config.yaml")
+		frag := filepath.Join(dir, "more.yaml")
+		testutils.MustWriteFile(t, main, `
+grid: { rows: 1, columns: 1 }
+refreshInterval: 30s
+include: [more.yaml]
+providers:
+  jira: { baseURL: "https://jira.example.com" }
+tileTemplates:
+  jira-filter:
+    template: missing.gohtml
+    request: { provider: jira, path: /x }
+  chained:
+    extends: jira-filter
+tiles:
+  - extends: jira-filter
+    title: Team A
+    position: { row: 1, col: 1 }
+  - extends: nope
+    title: Team B
+`)
+		testutils.MustWriteFile(t, frag, `
+tileTemplates:
+  jira-filter:
+    template: a.gohtml
+`)
+
+		cfg, err := LoadConfig(main)
+		require.NoError(t, err)
+
+		err = cfg.Validate(tmplWith(t, "a.gohtml"), nil)
+		require.Error(t, err)
+		msg := err.Error()
+		assert.Contains(t, msg, `tile template "jira-filter": defined more than once (`+main+`:8 and `+frag+`:3)`)
+		assert.Contains(t, msg, `tile template "chained" at `+main+`:11: tile templates cannot extend other templates`)
+		assert.Contains(t, msg, `tile "Team B" at `+main+`:17: extends unknown tile template "nope"`)
+		assert.Contains(t, msg,
+			`tile[0] (Team A) at `+main+`:14 (extends "jira-filter" at `+main+`:8): template "missing.gohtml" not found`)
+	})
+}
@@ -0,0 +1,220 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// fragment is the part of a config an included file may define.
+type fragment struct {
+	Providers  map[string]Provider `yaml:"providers"`
+	Tiles      []Tile              `yaml:"tiles"`
+	Pages      []Page              `yaml:"pages"`
+	Dashboards []DashboardConfig   `yaml:"dashboards"`
+	Playlists  []Playlist          `yaml:"playlists"`
+
+	TileTemplates map[string]Tile `yaml:"tileTemplates"`
+}
+
+// decodeFile strictly decodes YAML data into out and returns the
+// document node, which carries the line numbers used for provenance.
+func decodeFile(data []byte, out any) (*yaml.Node, error) {
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true) // fail on unknown YAML keys
+	if err := dec.Decode(out); err != nil {
+		return nil, err
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	if len(doc.Content) == 0 {
+		return nil, nil // empty document
+	}
+	return doc.Content[0], nil
+}
+
+// includeFiles expands the include patterns relative to dir. Patterns without glob
+// characters must match a file; duplicate matches and the main config itself are skipped.
+func includeFiles(dir, mainPath string, patterns []string) ([]string, error) {
+	self, _ := filepath.Abs(mainPath)
+	seen := map[string]bool{self: true}
+
+	var files []string
+	for _, pattern := range patterns {
+		if !filepath.IsAbs(pattern) {
+			pattern = filepath.Join(dir, pattern)
+		}
+		matches, err := filepath.Glob(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("include %q: %w", pattern, err)
+		}
+		if len(matches) == 0 && !strings.ContainsAny(pattern, "*?[") {
+			return nil, fmt.Errorf("include %q: no such file", pattern)
+		}
+		slices.Sort(matches)
+		for _, m := range matches {
+			abs, _ := filepath.Abs(m)
+			if seen[abs] {
+				continue
+			}
+			seen[abs] = true
+			files = append(files, m)
+		}
+	}
+	return files, nil
+}
+
+// loadIncludes merges the files listed under include into cfg. Tiles, pages, dashboards
+// and playlists are appended in file order; providers are merged and duplicate names
+// are recorded for Validate to report.
+func (cfg *DashboardConfig) loadIncludes(mainPath string) error {
+	files, err := includeFiles(filepath.Dir(mainPath), mainPath, cfg.Include)
+	if err != nil {
+		return err
+	}
+
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return fmt.Errorf("read include: %w", err)
+		}
+		var frag fragment
+		root, err := decodeFile(data, &frag)
+		if err != nil {
+			return fmt.Errorf("parse include %s: %w", file, err)
+		}
+		annotate(root, file, frag)
+
+		if cfg.Providers == nil && len(frag.Providers) > 0 {
+			cfg.Providers = make(map[string]Provider, len(frag.Providers))
+		}
+		for _, name := range sortedKeys(frag.Providers) {
+			p := frag.Providers[name]
+			if other, ok := lookupProvider(cfg.Providers, name); ok {
+				cfg.loadErrs = append(cfg.loadErrs, fmt.Sprintf(
+					"provider %q: defined more than once (%s and %s)", name, other.origin, p.origin))
+				continue
+			}
+			cfg.Providers[name] = p
+		}
+		if cfg.TileTemplates == nil && len(frag.TileTemplates) > 0 {
+			cfg.TileTemplates = make(map[string]Tile, len(frag.TileTemplates))
+		}
+		for _, name := range sortedKeys(frag.TileTemplates) {
+			t := frag.TileTemplates[name]
+			if other, ok := cfg.TileTemplates[name]; ok {
+				cfg.loadErrs = append(cfg.loadErrs, fmt.Sprintf(
+					"tile template %q: defined more than once (%s and %s)", name, other.origin, t.origin))
+				continue
+			}
+			cfg.TileTemplates[name] = t
+		}
+		cfg.Tiles = append(cfg.Tiles, frag.Tiles...)
+		cfg.Pages = append(cfg.Pages, frag.Pages...)
+		cfg.Dashboards = append(cfg.Dashboards, frag.Dashboards...)
+		cfg.Playlists = append(cfg.Playlists, frag.Playlists...)
+	}
+	return nil
+}
+
+// lookupProvider finds a provider by case-insensitive name.
+func lookupProvider(providers map[string]Provider, name string) (Provider, bool) {
+	for n, p := range providers {
+		if strings.EqualFold(n, name) {
+			return p, true
+		}
+	}
+	return Provider{}, false
+}
+
+// sortedKeys returns the map keys in sorted order for deterministic merging.
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+// annotate records "file:line" provenance on the providers, tile templates and tiles
+// (including tiles of pages and dashboards) of f, decoded from the mapping node root.
+func annotate(root *yaml.Node, file string, f fragment) {
+	if root == nil {
+		return
+	}
+	origin := func(n *yaml.Node) string { return fmt.Sprintf("%s:%d", file, n.Line) }
+
+	for name, n := range mappingKeys(root, "providers") {
+		if p, ok := f.Providers[name]; ok {
+			p.origin = origin(n)
+			f.Providers[name] = p
+		}
+	}
+	for name, n := range mappingKeys(root, "tileTemplates") {
+		if t, ok := f.TileTemplates[name]; ok {
+			t.origin = origin(n)
+			f.TileTemplates[name] = t
+		}
+	}
+	for i, n := range sequenceItems(root, "tiles", len(f.Tiles)) {
+		f.Tiles[i].origin = origin(n)
+	}
+	for i, n := range sequenceItems(root, "pages", len(f.Pages)) {
+		annotate(n, file, fragment{Tiles: f.Pages[i].Tiles})
+	}
+	for i, n := range sequenceItems(root, "dashboards", len(f.Dashboards)) {
+		annotate(n, file, fragment{Tiles: f.Dashboards[i].Tiles, Pages: f.Dashboards[i].Pages})
+	}
+}
+
+// mappingKeys returns the key nodes of the mapping under key, by key value.
+func mappingKeys(n *yaml.Node, key string) map[string]*yaml.Node {
+	m := mappingValue(n, key)
+	if m == nil || m.Kind != yaml.MappingNode {
+		return nil
+	}
+	out := make(map[string]*yaml.Node, len(m.Content)/2)
+	for i := 0; i+1 < len(m.Content); i += 2 {
+		out[m.Content[i].Value] = m.Content[i]
+	}
+	return out
+}
+
+// mappingValue returns the value node for key in a mapping node, or nil.
+func mappingValue(n *yaml.Node, key string) *yaml.Node {
+	if n == nil || n.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		if n.Content[i].Value == key {
+			return n.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// sequenceItems returns the items of the sequence under key, if it has exactly want items.
+func sequenceItems(n *yaml.Node, key string, want int) []*yaml.Node {
+	seq := mappingValue(n, key)
+	if seq == nil || seq.Kind != yaml.SequenceNode || len(seq.Content) != want {
+		return nil
+	}
+	return seq.Content
+}
+
+// at formats an origin for use in validation messages; empty when unknown.
+func at(origin string) string {
+	if origin == "" {
+		return ""
+	}
+	return " at " + origin
+}
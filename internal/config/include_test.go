@@ -0,0 +1,140 @@
+package config
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gi8lino/tiledash/internal/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadConfigIncludes(t *testing.T) {
+	t.Parallel()
+
+	t.Run("merges included files in order", func(t *testing.T) {
+		t.Parallel()
+
+		dir := t.TempDir()
+		testutils.MustWriteFile(t, filepath.Join(dir, "config.yaml"), `
+grid: { rows: 2, columns: 1 }
+refreshInterval: 30s
+include:
+  - providers.yaml
+  - conf.d/*.yaml
+tiles:
+  - title: main
+    template: a.gohtml
+    position: { row: 1, col: 1 }
+    request: { provider: jira, path: /main }
+`)
+		testutils.MustWriteFile(t, filepath.Join(dir, "providers.yaml"), `
+providers:
+  jira: { baseURL: "https://jira.example.com" }
+`)
+		testutils.MustWriteFile(t, filepath.Join(dir, "conf.d", "b.yaml"), `
+tiles:
+  - title: b
+    template: a.gohtml
+    position: { row: 2, col: 1 }
+    request: { provider: jira, path: /b }
+`)
+		testutils.MustWriteFile(t, filepath.Join(dir, "conf.d", "a.yaml"), `
+providers:
+  bitbucket: { baseURL: "https://bitbucket.example.com" }
+`)
+
+		cfg, err := LoadConfig(filepath.Join(dir, "config.yaml"))
+		require.NoError(t, err)
+
+		assert.Len(t, cfg.Providers, 2)
+		require.Len(t, cfg.Tiles, 2)
+		assert.Equal(t, "main", cfg.Tiles[0].Title)
+		assert.Equal(t, "b", cfg.Tiles[1].Title)
+		assert.Equal(t, filepath.Join(dir, "config.yaml")+":8", cfg.Tiles[0].origin)
+		assert.Equal(t, filepath.Join(dir, "conf.d", "b.yaml")+":3", cfg.Tiles[1].origin)
+		assert.Equal(t, filepath.Join(dir, "providers.yaml")+":3", cfg.Providers["jira"].origin)
+
+		require.NoError(t, cfg.Validate(tmplWith(t, "a.gohtml"), nil))
+		assert.Equal(t, 30*time.Second, cfg.RefreshInterval)
+	})
+
+	t.Run("reports duplicates and problems with provenance", func(t *testing.T) {
+		t.Parallel()
+
+		dir := t.TempDir()
+		main := filepath.Join(dir, "config.yaml")
+		frag := filepath.Join(dir, "team.yaml")
+		testutils.MustWriteFile(t, main, `
+grid: { rows: 1, columns: 1 }
+refreshInterval: 30s
+include: [team.yaml]
+providers:
+  jira: { baseURL: "https://jira.example.com" }
+`)
+		testutils.MustWriteFile(t, frag, `
+providers:
+  JIRA:
+    baseURL: "https://other.example.com"
+  svc:
+    auth:
+      bearer: { token: "" }
+tiles:
+  - title: off-grid
+    template: a.gohtml
+    position: { row: 1, col: 2 }
+    request: { provider: jira, path: /x }
+`)
+
+		cfg, err := LoadConfig(main)
+		require.NoError(t, err)
+
+		err = cfg.Validate(tmplWith(t, "a.gohtml"), nil)
+		require.Error(t, err)
+		msg := err.Error()
+		assert.True(t, strings.HasPrefix(msg,
+			"config has errors:\n  - provider \"JIRA\": defined more than once ("+main+":6 and "+frag+":3)"), msg)
+		assert.Contains(t, msg, "tile[0] (off-grid) at "+frag+":9: col 2 out of bounds (max 1)")
+		assert.Contains(t, msg, `provider "svc" at `+frag+`:5: bearer auth requires non-empty "token"`)
+	})
+
+	t.Run("fails on missing files and unknown keys", func(t *testing.T) {
+		t.Parallel()
+
+		dir := t.TempDir()
+		main := filepath.Join(dir, "config.yaml")
+		testutils.MustWriteFile(t, main, "include: [missing.yaml, none/*.yaml]\n")
+
+		_, err := LoadConfig(main)
+		assert.EqualError(t, err, `include "`+filepath.Join(dir, "missing.yaml")+`": no such file`)
+
+		testutils.MustWriteFile(t, main, "include: [frag.yaml]\n")
+		testutils.MustWriteFile(t, filepath.Join(dir, "frag.yaml"), "title: not allowed here\n")
+
+		_, err = LoadConfig(main)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "parse include "+filepath.Join(dir, "frag.yaml")+": ")
+		assert.Contains(t, err.Error(), "field title not found")
+	})
+
+	t.Run("annotates tiles of dashboards and pages", func(t *testing.T) {
+		t.Parallel()
+
+		dir := t.TempDir()
+		main := filepath.Join(dir, "config.yaml")
+		testutils.MustWriteFile(t, main, `
+dashboards:
+  - name: a
+    pages:
+      - name: p
+        tiles:
+          - title: x
+`)
+
+		cfg, err := LoadConfig(main)
+		require.NoError(t, err)
+		assert.Equal(t, main+":7", cfg.Dashboards[0].Pages[0].Tiles[0].origin)
+	})
+}
@@ -0,0 +1,135 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode/utf8"
+)
+
+// paramNameRe restricts parameter names to identifiers usable as .Params.<name> in templates.
+var paramNameRe = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// freeValueRe restricts the values of parameters without allowed values. Values end up in
+// upstream paths, queries, headers and bodies, so separators, quotes and control characters are refused.
+var freeValueRe = regexp.MustCompile(`^[\p{L}\p{N} _.,:@+-]*$`)
+
+// maxFreeValueLen bounds the length of values of parameters without allowed values.
+const maxFreeValueLen = 128
+
+// reservedParams are query parameters the dashboard page already uses.
+var reservedParams = []string{"page"}
+
+// Params holds resolved dashboard parameter values by name.
+type Params map[string]string
+
+// Encode returns the params as a URL query string, sorted by name.
+func (p Params) Encode() string {
+	q := url.Values{}
+	for k, v := range p {
+		q.Set(k, v)
+	}
+	return q.Encode()
+}
+
+// paramsKey is the context key for resolved dashboard parameters.
+type paramsKey struct{}
+
+// WithParams returns a copy of ctx carrying the resolved dashboard parameters.
+func WithParams(ctx context.Context, p Params) context.Context {
+	return context.WithValue(ctx, paramsKey{}, p)
+}
+
+// ParamsFromContext returns the dashboard parameters carried by ctx (nil if none).
+func ParamsFromContext(ctx context.Context) Params {
+	p, _ := ctx.Value(paramsKey{}).(Params)
+	return p
+}
+
+// DefaultParams returns every declared parameter set to its default value.
+func (d DashboardConfig) DefaultParams() Params {
+	p, _ := d.ResolveParams(nil)
+	return p
+}
+
+// ResolveParams picks the declared parameters from a URL query, falling back to defaults.
+// Values outside a parameter's allowed values are rejected, as are values of parameters
+// without allowed values that fail checkFreeValue; undeclared keys are ignored.
+func (d DashboardConfig) ResolveParams(q url.Values) (Params, error) {
+	if len(d.Params) == 0 {
+		return nil, nil
+	}
+	out := make(Params, len(d.Params))
+	for _, p := range d.Params {
+		v := q.Get(p.Name)
+		if v == "" {
+			v = p.Default
+		}
+		if len(p.Values) > 0 && !slices.Contains(p.Values, v) {
+			return nil, fmt.Errorf("param %q: value %q is not allowed (allowed: %s)", p.Name, v, strings.Join(p.Values, ", "))
+		}
+		if len(p.Values) == 0 {
+			if err := checkFreeValue(v); err != nil {
+				return nil, fmt.Errorf("param %q: %w", p.Name, err)
+			}
+		}
+		out[p.Name] = v
+	}
+	return out, nil
+}
+
+// validateParams checks parameter declarations and defaults the value of parameters
+// with allowed values but no default to the first allowed value.
+func validateParams(d *DashboardConfig) []string {
+	var errs []string
+
+	seen := make(map[string]int, len(d.Params))
+	for i := range d.Params {
+		p := &d.Params[i]
+		label := fmt.Sprintf("params[%d]", i)
+
+		switch name := p.Name; {
+		case strings.TrimSpace(name) == "":
+			errs = append(errs, fmt.Sprintf("%s: name is required", label))
+		case !paramNameRe.MatchString(name):
+			errs = append(errs, fmt.Sprintf("%s: name %q may only contain letters, digits and '_' and must not start with a digit", label, name))
+		case slices.Contains(reservedParams, name):
+			errs = append(errs, fmt.Sprintf("%s: name %q is reserved", label, name))
+		default:
+			label = fmt.Sprintf("param %q", name)
+			if first, dup := seen[name]; dup {
+				errs = append(errs, fmt.Sprintf("%s: duplicate name (also used by params[%d])", label, first))
+			}
+			seen[name] = i
+		}
+
+		if len(p.Values) == 0 {
+			if err := checkFreeValue(p.Default); err != nil {
+				errs = append(errs, fmt.Sprintf("%s: default: %v", label, err))
+			}
+			continue
+		}
+		if p.Default == "" {
+			p.Default = p.Values[0]
+		}
+		if !slices.Contains(p.Values, p.Default) {
+			errs = append(errs, fmt.Sprintf("%s: default %q is not one of the allowed values", label, p.Default))
+		}
+	}
+	return errs
+}
+
+// checkFreeValue reports whether v is acceptable for a parameter without allowed values:
+// at most maxFreeValueLen characters of letters, digits, spaces and "_.,:@+-", without "..".
+func checkFreeValue(v string) error {
+	switch {
+	case utf8.RuneCountInString(v) > maxFreeValueLen:
+		return fmt.Errorf("value is longer than %d characters", maxFreeValueLen)
+	case !freeValueRe.MatchString(v), strings.Contains(v, ".."):
+		return fmt.Errorf("value %q may only contain letters, digits, spaces and \"_.,:@+-\"", v)
+	}
+	return nil
+}
@@ -0,0 +1,118 @@
+package config
+
+import (
+	"context"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveParams(t *testing.T) {
+	t.Parallel()
+
+	cfg := DashboardConfig{Params: []Param{
+		{Name: "team", Default: "payments", Values: []string{"payments", "billing"}},
+		{Name: "env", Default: "prod"},
+	}}
+
+	t.Run("defaults", func(t *testing.T) {
+		t.Parallel()
+
+		p, err := cfg.ResolveParams(url.Values{"other": {"x"}})
+		require.NoError(t, err)
+		assert.Equal(t, Params{"team": "payments", "env": "prod"}, p)
+		assert.Equal(t, p, cfg.DefaultParams())
+	})
+
+	t.Run("query overrides defaults", func(t *testing.T) {
+		t.Parallel()
+
+		p, err := cfg.ResolveParams(url.Values{"team": {"billing"}, "env": {"staging"}})
+		require.NoError(t, err)
+		assert.Equal(t, Params{"team": "billing", "env": "staging"}, p)
+		assert.Equal(t, "env=staging&team=billing", p.Encode())
+	})
+
+	t.Run("rejects values not allowed", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := cfg.ResolveParams(url.Values{"team": {"ops"}})
+		require.Error(t, err)
+		assert.EqualError(t, err, `param "team": value "ops" is not allowed (allowed: payments, billing)`)
+	})
+
+	t.Run("rejects hostile free-form values", func(t *testing.T) {
+		t.Parallel()
+
+		for _, v := range []string{"../admin", "a/b", "a&b=c", "x#y", "x?y", "a%2Fb", "a\r\nX-Evil: 1", `"}`, "..", strings.Repeat("a", maxFreeValueLen+1)} {
+			_, err := cfg.ResolveParams(url.Values{"env": {v}})
+			assert.Error(t, err, v)
+		}
+	})
+
+	t.Run("accepts plain free-form values", func(t *testing.T) {
+		t.Parallel()
+
+		for _, v := range []string{"staging", "Zürich 2", "v1.2.3", "a_b-c", "user@example.com", "2025-03-10T08:00:00+01:00"} {
+			p, err := cfg.ResolveParams(url.Values{"env": {v}})
+			require.NoError(t, err, v)
+			assert.Equal(t, v, p["env"])
+		}
+		_, err := cfg.ResolveParams(url.Values{"env": {"../admin"}})
+		assert.EqualError(t, err, `param "env": value "../admin" may only contain letters, digits, spaces and "_.,:@+-"`)
+	})
+
+	t.Run("no params", func(t *testing.T) {
+		t.Parallel()
+
+		p, err := DashboardConfig{}.ResolveParams(url.Values{"team": {"x"}})
+		require.NoError(t, err)
+		assert.Nil(t, p)
+		assert.Equal(t, "", p.Encode())
+	})
+
+	t.Run("context round trip", func(t *testing.T) {
+		t.Parallel()
+
+		assert.Nil(t, ParamsFromContext(context.Background()))
+		ctx := WithParams(context.Background(), Params{"team": "billing"})
+		assert.Equal(t, Params{"team": "billing"}, ParamsFromContext(ctx))
+	})
+}
+
+func TestValidateParams(t *testing.T) {
+	t.Parallel()
+
+	t.Run("defaults to first allowed value", func(t *testing.T) {
+		t.Parallel()
+
+		cfg := DashboardConfig{Params: []Param{{Name: "team", Values: []string{"payments", "billing"}}}}
+		assert.Empty(t, validateParams(&cfg))
+		assert.Equal(t, "payments", cfg.Params[0].Default)
+	})
+
+	t.Run("reports invalid declarations", func(t *testing.T) {
+		t.Parallel()
+
+		cfg := DashboardConfig{Params: []Param{
+			{Name: ""},
+			{Name: "1team"},
+			{Name: "page"},
+			{Name: "env"},
+			{Name: "env"},
+			{Name: "team", Default: "ops", Values: []string{"payments"}},
+			{Name: "path", Default: "../etc"},
+		}}
+		assert.Equal(t, []string{
+			"params[0]: name is required",
+			`params[1]: name "1team" may only contain letters, digits and '_' and must not start with a digit`,
+			`params[2]: name "page" is reserved`,
+			`param "env": duplicate name (also used by params[3])`,
+			`param "team": default "ops" is not one of the allowed values`,
+			`param "path": default: value "../etc" may only contain letters, digits, spaces and "_.,:@+-"`,
+		}, validateParams(&cfg))
+	})
+}
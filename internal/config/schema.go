@@ -0,0 +1,114 @@
+package config
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// SchemaID is the identifier of the generated config JSON Schema.
+const SchemaID = "https://github.com/gi8lino/tiledash/config.schema.json"
+
+// durationPattern matches the Go duration strings accepted by time.ParseDuration.
+const durationPattern = `^-?(0|([0-9]+(\.[0-9]*)?(ns|us|µs|ms|s|m|h))+)$`
+
+// Schema returns a JSON Schema (draft 2020-12) describing config.yaml.
+// It is generated from DashboardConfig via the yaml struct tags, so it never drifts
+// from what LoadConfig accepts. Unknown keys are rejected, as they are when loading.
+func Schema() map[string]any {
+	g := schemaGen{defs: map[string]any{}}
+	root := g.typeSchema(reflect.TypeFor[DashboardConfig]())
+	return map[string]any{
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"$id":     SchemaID,
+		"title":   "tiledash config",
+		"$ref":    root["$ref"],
+		"$defs":   g.defs,
+	}
+}
+
+// SchemaJSON returns Schema as indented JSON with a trailing newline.
+func SchemaJSON() ([]byte, error) {
+	b, err := json.MarshalIndent(Schema(), "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(b, '\n'), nil
+}
+
+// schemaGen collects the definitions of named struct types while walking the config types.
+type schemaGen struct {
+	defs map[string]any
+}
+
+// typeSchema returns the schema for t. Named structs are emitted once under $defs and
+// referenced, which also covers recursive types such as dashboards.
+func (g *schemaGen) typeSchema(t reflect.Type) map[string]any {
+	if t == reflect.TypeFor[time.Duration]() {
+		return map[string]any{"type": "string", "pattern": durationPattern}
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		return g.typeSchema(t.Elem())
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]any{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Slice, reflect.Array:
+		return map[string]any{"type": "array", "items": g.typeSchema(t.Elem())}
+	case reflect.Map:
+		return map[string]any{"type": "object", "additionalProperties": g.typeSchema(t.Elem())}
+	case reflect.Struct:
+		return g.structSchema(t)
+	default: // interfaces (e.g. vars values) accept anything
+		return map[string]any{}
+	}
+}
+
+// structSchema registers t under $defs and returns a reference to it.
+func (g *schemaGen) structSchema(t reflect.Type) map[string]any {
+	ref := map[string]any{"$ref": "#/$defs/" + t.Name()}
+	if _, done := g.defs[t.Name()]; done {
+		return ref
+	}
+	g.defs[t.Name()] = nil // placeholder so recursive fields resolve to the reference
+
+	props := map[string]any{}
+	for i := range t.NumField() {
+		f := t.Field(i)
+		name, ok := yamlName(f)
+		if !ok {
+			continue
+		}
+		props[name] = g.typeSchema(f.Type)
+	}
+
+	g.defs[t.Name()] = map[string]any{
+		"type":                 "object",
+		"properties":           props,
+		"additionalProperties": false,
+	}
+	return ref
+}
+
+// yamlName returns the key a struct field is decoded from, or false if it isn't decoded.
+func yamlName(f reflect.StructField) (string, bool) {
+	if !f.IsExported() {
+		return "", false
+	}
+	tag := f.Tag.Get("yaml")
+	if tag == "-" {
+		return "", false
+	}
+	if name, _, _ := strings.Cut(tag, ","); name != "" {
+		return name, true
+	}
+	return strings.ToLower(f.Name), true
+}
@@ -0,0 +1,56 @@
+package config
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSchema(t *testing.T) {
+	t.Parallel()
+
+	schema := Schema()
+	defs := schema["$defs"].(map[string]any)
+	assert.Equal(t, "#/$defs/DashboardConfig", schema["$ref"])
+
+	t.Run("follows yaml tags", func(t *testing.T) {
+		t.Parallel()
+
+		tile := defs["Tile"].(map[string]any)
+		props := tile["properties"].(map[string]any)
+		assert.Contains(t, props, "extends")
+		assert.Contains(t, props, "vars")
+		assert.NotContains(t, props, "page", "yaml:\"-\" fields are not part of the config")
+		assert.NotContains(t, props, "hash")
+		assert.NotContains(t, props, "origin")
+		assert.Equal(t, false, tile["additionalProperties"])
+	})
+
+	t.Run("references recursive types", func(t *testing.T) {
+		t.Parallel()
+
+		props := defs["DashboardConfig"].(map[string]any)["properties"].(map[string]any)
+		assert.Equal(t, map[string]any{
+			"type":  "array",
+			"items": map[string]any{"$ref": "#/$defs/DashboardConfig"},
+		}, props["dashboards"])
+		assert.Equal(t, map[string]any{
+			"type":                 "object",
+			"additionalProperties": map[string]any{"$ref": "#/$defs/Tile"},
+		}, props["tileTemplates"])
+		assert.Equal(t, map[string]any{"type": "string", "pattern": durationPattern}, props["refreshInterval"])
+		assert.Equal(t, map[string]any{"type": "object", "additionalProperties": map[string]any{}}, props["vars"])
+	})
+
+	t.Run("checked-in schema is up to date", func(t *testing.T) {
+		t.Parallel()
+
+		want, err := SchemaJSON()
+		require.NoError(t, err)
+		got, err := os.ReadFile("../../config.schema.json")
+		require.NoError(t, err)
+		assert.Equal(t, string(want), string(got), "run `make schema` to regenerate config.schema.json")
+	})
+}
@@ -7,13 +7,60 @@ import (
 )
 
 // DashboardConfig is the top-level configuration for the dashboard.
+//
+// A config either defines tiles directly (a single dashboard) or a list of named
+// dashboards that share the root providers and inherit unset root settings.
+// Instead of tiles, a dashboard may group its tiles into pages with their own grids.
+// Providers, tiles, pages, dashboards and playlists may be split across included files.
 type DashboardConfig struct {
+	Include         []string            `yaml:"include"` // files or globs, relative to the config file
+	Name            string              `yaml:"name"`    // URL-safe name; set for entries of Dashboards
 	Title           string              `yaml:"title"`
+	Vars            map[string]any      `yaml:"vars"`   // values for templated requests; inherited by tiles
+	Params          []Param             `yaml:"params"` // URL parameters (?name=value) available as .Params
 	RefreshInterval time.Duration       `yaml:"refreshInterval"`
+	Timezone        string              `yaml:"timezone"` // IANA zone for time helpers; default: the server's
+	Locale          string              `yaml:"locale"`   // language of time helpers: en (default), de, fr, it
 	Grid            *GridConfig         `yaml:"grid"`
 	Customization   *Customization      `yaml:"customization"`
 	Providers       map[string]Provider `yaml:"providers"`
 	Tiles           []Tile              `yaml:"tiles"`
+	Pages           []Page              `yaml:"pages"`
+	Dashboards      []DashboardConfig   `yaml:"dashboards"`
+	Playlists       []Playlist          `yaml:"playlists"`
+	TileTemplates   map[string]Tile     `yaml:"tileTemplates"` // reusable tile bases for extends
+
+	loadErrs []string // problems found while loading (includes, tile templates), reported by Validate
+}
+
+// Param declares a dashboard URL parameter. Its value is taken from the page URL,
+// forwarded to the tile API and available to requests and templates as .Params.<name>.
+type Param struct {
+	Name    string   `yaml:"name"`
+	Default string   `yaml:"default"`          // used when the URL doesn't set the parameter
+	Values  []string `yaml:"values,omitempty"` // allowed values; any value when empty
+}
+
+// Page groups tiles under a named tab with its own grid.
+type Page struct {
+	Name  string      `yaml:"name"`  // URL-safe name; selected with ?page={name}
+	Title string      `yaml:"title"` // tab label; defaults to Name
+	Grid  *GridConfig `yaml:"grid"`  // defaults to the dashboard grid
+	Tiles []Tile      `yaml:"tiles"`
+}
+
+// Playlist rotates a kiosk display through named dashboards.
+type Playlist struct {
+	Name     string          `yaml:"name"`     // URL-safe name; served at /p/{name}
+	Interval time.Duration   `yaml:"interval"` // default dwell time per entry
+	Entries  []PlaylistEntry `yaml:"entries"`
+}
+
+// PlaylistEntry is a single stop of a playlist.
+type PlaylistEntry struct {
+	Dashboard string        `yaml:"dashboard"`          // name of a dashboard under dashboards
+	Page      string        `yaml:"page,omitempty"`     // optional page of that dashboard
+	Duration  time.Duration `yaml:"duration,omitempty"` // overrides the playlist interval
 }
 
 // GridConfig controls the grid dimensions.
@@ -70,6 +117,8 @@ type Provider struct {
 	BaseURL       string     `yaml:"baseURL"`
 	SkipTLSVerify *bool      `yaml:"skipTLSVerify"`
 	Auth          AuthConfig `yaml:"auth"`
+
+	origin string // "file:line" the provider was loaded from
 }
 
 // AuthConfig infers the scheme from which subfield is present.
@@ -90,12 +139,19 @@ type BearerAuth struct {
 }
 
 // Tile is a single dashboard unit with layout + request.
+// A tile may extend a named entry of tileTemplates and override only what differs.
 type Tile struct {
-	Title    string   `yaml:"title"`
-	Template string   `yaml:"template"`
-	Position Position `yaml:"position"`
-	Request  Request  `yaml:"request"`
-	Hash     string   `yaml:"-" json:"-"` // computed hash
+	Extends  string         `yaml:"extends,omitempty"` // name under top-level tileTemplates
+	Title    string         `yaml:"title"`
+	Template string         `yaml:"template"`
+	Position Position       `yaml:"position"`
+	Request  Request        `yaml:"request"`
+	Vars     map[string]any `yaml:"vars,omitempty"` // overrides dashboard vars for this tile
+	Page     string         `yaml:"-"`              // owning page; set when pages are flattened into tiles
+	Hash     string         `yaml:"-" json:"-"`     // computed hash
+
+	origin     string // "file:line" the tile was loaded from
+	baseOrigin string // "file:line" of the tile template it extends
 }
 
 // Position places a tile in the grid.
@@ -107,19 +163,43 @@ type Position struct {
 }
 
 // Request describes the HTTP request for a tile, bound to a provider.
+// Path, query, headers, body and bodyJSON strings may be Go templates rendered with
+// the tile's vars (as .Vars) on every fetch.
 type Request struct {
-	Provider string            `yaml:"provider"`           // name under top-level providers
-	Method   string            `yaml:"method,omitempty"`   // default GET
-	Path     string            `yaml:"path"`               // relative to provider's BaseURL
-	TTL      time.Duration     `yaml:"ttl,omitempty"`      // cache TTL
-	Query    map[string]string `yaml:"query,omitempty"`    // query params
-	Headers  map[string]string `yaml:"headers,omitempty"`  // extra headers
-	Body     string            `yaml:"body,omitempty"`     // raw body
-	BodyJSON map[string]any    `yaml:"bodyJSON,omitempty"` // JSON body (preferred)
-	Paginate bool              `yaml:"paginate,omitempty"` // enable pagination
-	Page     PageParams        `yaml:"page,omitempty"`     // pagination config
+	Provider  string            `yaml:"provider"`            // name under top-level providers
+	Method    string            `yaml:"method,omitempty"`    // default GET
+	Path      string            `yaml:"path"`                // relative to provider's BaseURL
+	TTL       time.Duration     `yaml:"ttl,omitempty"`       // cache TTL
+	Query     map[string]string `yaml:"query,omitempty"`     // query params
+	Headers   map[string]string `yaml:"headers,omitempty"`   // extra headers
+	Body      string            `yaml:"body,omitempty"`      // raw body
+	BodyJSON  map[string]any    `yaml:"bodyJSON,omitempty"`  // JSON body (preferred)
+	Paginate  *bool             `yaml:"paginate,omitempty"`  // enable pagination; false turns off pagination inherited from a tile template
+	Page      PageParams        `yaml:"page,omitempty"`      // pagination config
+	Transform Transform         `yaml:"transform,omitempty"` // reshapes the response for templates
+}
+
+// Paginated reports whether pagination is enabled for the request.
+func (r Request) Paginated() bool {
+	return r.Paginate != nil && *r.Paginate
+}
+
+// Transform reshapes the merged response before rendering; set one of the expressions.
+// The result is exposed to tile templates as .Data while .Acc keeps the original.
+type Transform struct {
+	JQ       string `yaml:"jq,omitempty"`       // jq program
+	JMESPath string `yaml:"jmespath,omitempty"` // JMESPath expression
 }
 
+// IsZero reports whether no transform is configured.
+func (t Transform) IsZero() bool {
+	return t == Transform{}
+}
+
+// TransformCheck reports whether a tile's jq program or JMESPath expression compiles.
+// It is passed to Validate so config does not depend on the transform engines.
+type TransformCheck func(jq, jmespath string) error
+
 // PageParams configures offset/limit style pagination.
 type PageParams struct {
 	Location   string `yaml:"location,omitempty"` // "query" | "body"
@@ -131,6 +211,50 @@ type PageParams struct {
 	LimitPages int    `yaml:"limitPages,omitempty"`
 }
 
+// Boards returns the dashboards to serve: the named dashboards if any are configured,
+// otherwise the config itself as a single unnamed dashboard.
+func (d DashboardConfig) Boards() []DashboardConfig {
+	if len(d.Dashboards) == 0 {
+		return []DashboardConfig{d}
+	}
+	return d.Dashboards
+}
+
+// Dwell returns how long the entry stays on screen, falling back to the playlist interval.
+func (p Playlist) Dwell(e PlaylistEntry) time.Duration {
+	if e.Duration > 0 {
+		return e.Duration
+	}
+	return p.Interval
+}
+
+// PageTiles returns the indexes into Tiles of the tiles on the named page.
+func (d DashboardConfig) PageTiles(page string) []int {
+	var idx []int
+	for i, t := range d.Tiles {
+		if t.Page == page {
+			idx = append(idx, i)
+		}
+	}
+	return idx
+}
+
+// flattenPages rebuilds Tiles from Pages in page order, tagging each tile with its page,
+// so tile indexes stay global across pages.
+func (d *DashboardConfig) flattenPages() {
+	if len(d.Pages) == 0 {
+		return
+	}
+	var tiles []Tile
+	for _, p := range d.Pages {
+		for _, t := range p.Tiles {
+			t.Page = p.Name
+			tiles = append(tiles, t)
+		}
+	}
+	d.Tiles = tiles
+}
+
 // GetCellByIndex returns a tile by index.
 func (d DashboardConfig) GetCellByIndex(i int) (Tile, error) {
 	if i < 0 || i >= len(d.Tiles) {
@@ -0,0 +1,200 @@
+package flag
+
+import (
+	"errors"
+	"path/filepath"
+	"time"
+
+	"github.com/containeroo/tinyflags"
+)
+
+// Subcommands run by tiledash instead of the server when given as the first argument.
+const (
+	CommandValidate = "validate" // check the config and templates, then exit
+	CommandSchema   = "schema"   // print the config JSON Schema
+	CommandRender   = "render"   // render a tile from fixture files
+	CommandFetch    = "fetch"    // run a tile's upstream request and print the response
+	CommandExport   = "export"   // write a static HTML snapshot of a dashboard
+)
+
+// ValidateConfig holds the flags of the validate subcommand.
+type ValidateConfig struct {
+	Config      string // Path to config file
+	TemplateDir string // Path to template directory
+	ResolveAuth bool   // Also resolve provider auth placeholders (env:/file:)
+}
+
+// ParseValidateArgs parses the arguments following "validate".
+func ParseValidateArgs(args []string, version string) (ValidateConfig, error) {
+	var cfg ValidateConfig
+	tf := tinyflags.NewFlagSet("tiledash validate", tinyflags.ContinueOnError)
+	tf.Version(version)
+	tf.EnvPrefix("TILEDASH")
+	tf.Description("Load and validate the config and templates, then exit non-zero on errors.")
+
+	tf.StringVar(&cfg.Config, "config", "config.yaml", "Path to config file").Value()
+	tf.StringVar(&cfg.TemplateDir, "template-dir", "./templates", "Path to template directory").Value()
+	tf.BoolVar(&cfg.ResolveAuth, "resolve-auth", false, "Also resolve provider auth placeholders (env:/file:)").Value()
+
+	if err := tf.Parse(args); err != nil {
+		return ValidateConfig{}, err
+	}
+	cfg.TemplateDir = absPath(cfg.TemplateDir)
+	return cfg, nil
+}
+
+// RenderConfig holds the flags of the render subcommand.
+type RenderConfig struct {
+	Config      string   // Path to config file
+	TemplateDir string   // Path to template directory
+	Dashboard   string   // Named dashboard the tile belongs to (empty for single-dashboard configs)
+	Tile        int      // Tile index, as used by /api/v1/tile/{id}
+	Fixtures    []string // JSON response files, one page each (or an array of pages)
+	Params      []string // Dashboard parameters as name=value
+	Out         string   // Output file; empty writes to stdout
+}
+
+// ParseRenderArgs parses the arguments following "render".
+func ParseRenderArgs(args []string, version string) (RenderConfig, error) {
+	var cfg RenderConfig
+	tf := tinyflags.NewFlagSet("tiledash render", tinyflags.ContinueOnError)
+	tf.Version(version)
+	tf.EnvPrefix("TILEDASH")
+	tf.Description("Render a tile from saved JSON responses instead of calling its provider.")
+
+	tf.StringVar(&cfg.Config, "config", "config.yaml", "Path to config file").Value()
+	tf.StringVar(&cfg.TemplateDir, "template-dir", "./templates", "Path to template directory").Value()
+	tf.StringVar(&cfg.Dashboard, "dashboard", "", "Named dashboard the tile belongs to").
+		Placeholder("NAME").
+		Value()
+	tf.IntVar(&cfg.Tile, "tile", 0, "Tile index, as used by /api/v1/tile/{id}").
+		Placeholder("ID").
+		Required().
+		Value()
+	tf.StringSliceVar(&cfg.Fixtures, "fixture", nil, "JSON response file; repeat for paginated responses").
+		Placeholder("FILE").
+		Required().
+		Value()
+	tf.StringSliceVar(&cfg.Params, "param", nil, "Dashboard parameter as name=value; may be repeated").
+		Placeholder("NAME=VALUE").
+		Value()
+	tf.StringVar(&cfg.Out, "out", "", "Write the HTML to this file instead of stdout").
+		Placeholder("FILE").
+		Value()
+
+	if err := tf.Parse(args); err != nil {
+		return RenderConfig{}, err
+	}
+	cfg.TemplateDir = absPath(cfg.TemplateDir)
+	return cfg, nil
+}
+
+// FetchConfig holds the flags of the fetch subcommand.
+type FetchConfig struct {
+	Config      string   // Path to config file
+	TemplateDir string   // Path to template directory
+	Dashboard   string   // Named dashboard the tile belongs to (empty for single-dashboard configs)
+	Tile        int      // Tile index, as used by /api/v1/tile/{id}
+	Params      []string // Dashboard parameters as name=value
+	SaveDir     string   // Directory to save each response page to; empty disables saving
+	LogFormat   string   // Trace log format (text or json)
+}
+
+// ParseFetchArgs parses the arguments following "fetch".
+func ParseFetchArgs(args []string, version string) (FetchConfig, error) {
+	var cfg FetchConfig
+	tf := tinyflags.NewFlagSet("tiledash fetch", tinyflags.ContinueOnError)
+	tf.Version(version)
+	tf.EnvPrefix("TILEDASH")
+	tf.Description("Run a tile's upstream request, trace every page to stderr and print the accumulated response.")
+
+	tf.StringVar(&cfg.Config, "config", "config.yaml", "Path to config file").Value()
+	tf.StringVar(&cfg.TemplateDir, "template-dir", "./templates", "Path to template directory").Value()
+	tf.StringVar(&cfg.Dashboard, "dashboard", "", "Named dashboard the tile belongs to").
+		Placeholder("NAME").
+		Value()
+	tf.IntVar(&cfg.Tile, "tile", 0, "Tile index, as used by /api/v1/tile/{id}").
+		Placeholder("ID").
+		Required().
+		Value()
+	tf.StringSliceVar(&cfg.Params, "param", nil, "Dashboard parameter as name=value; may be repeated").
+		Placeholder("NAME=VALUE").
+		Value()
+	tf.StringVar(&cfg.SaveDir, "save-pages", "", "Save each response page as page-N.json in this directory (usable as render fixtures)").
+		Placeholder("DIR").
+		Value()
+	logFormat := tf.String("log-format", "text", "Trace log format").Choices("text", "json").Short("l").Value()
+
+	if err := tf.Parse(args); err != nil {
+		return FetchConfig{}, err
+	}
+	cfg.LogFormat = *logFormat
+	cfg.TemplateDir = absPath(cfg.TemplateDir)
+	return cfg, nil
+}
+
+// ExportConfig holds the flags of the export subcommand.
+type ExportConfig struct {
+	Config      string        // Path to config file
+	TemplateDir string        // Path to template directory
+	Dashboard   string        // Named dashboard to export; all dashboards when exporting to Dir
+	Params      []string      // Dashboard parameters as name=value
+	Out         string        // Output file; empty writes to stdout
+	Dir         string        // Directory for timestamped snapshots
+	Every       time.Duration // Export to Dir on this interval until interrupted (0 = once)
+}
+
+// ParseExportArgs parses the arguments following "export".
+func ParseExportArgs(args []string, version string) (ExportConfig, error) {
+	var cfg ExportConfig
+	tf := tinyflags.NewFlagSet("tiledash export", tinyflags.ContinueOnError)
+	tf.Version(version)
+	tf.EnvPrefix("TILEDASH")
+	tf.Description("Render a dashboard into a self-contained HTML snapshot, once or on a schedule.")
+
+	tf.StringVar(&cfg.Config, "config", "config.yaml", "Path to config file").Value()
+	tf.StringVar(&cfg.TemplateDir, "template-dir", "./templates", "Path to template directory").Value()
+	tf.StringVar(&cfg.Dashboard, "dashboard", "", "Named dashboard to export (default with --dir: all)").
+		Placeholder("NAME").
+		Value()
+	tf.StringSliceVar(&cfg.Params, "param", nil, "Dashboard parameter as name=value; may be repeated").
+		Placeholder("NAME=VALUE").
+		Value()
+	tf.StringVar(&cfg.Out, "out", "", "Write the snapshot to this file instead of stdout").
+		Placeholder("FILE").
+		Value()
+	tf.StringVar(&cfg.Dir, "dir", "", "Write timestamped snapshots into this directory").
+		Placeholder("DIR").
+		Value()
+	tf.DurationVar(&cfg.Every, "every", 0, "Export into --dir on this interval until interrupted (0 = once)").
+		Value()
+
+	if err := tf.Parse(args); err != nil {
+		return ExportConfig{}, err
+	}
+	if cfg.Out != "" && cfg.Dir != "" {
+		return ExportConfig{}, errors.New("--out and --dir are mutually exclusive")
+	}
+	if cfg.Every < 0 || (cfg.Every > 0 && cfg.Dir == "") {
+		return ExportConfig{}, errors.New("--every requires --dir and a positive interval")
+	}
+	cfg.TemplateDir = absPath(cfg.TemplateDir)
+	return cfg, nil
+}
+
+// ParseSchemaArgs parses the arguments following "schema".
+func ParseSchemaArgs(args []string, version string) error {
+	tf := tinyflags.NewFlagSet("tiledash schema", tinyflags.ContinueOnError)
+	tf.Version(version)
+	tf.Description("Print the JSON Schema of config.yaml for editor completion and validation.")
+	return tf.Parse(args)
+}
+
+// absPath makes a relative path absolute against the working directory.
+func absPath(s string) string {
+	if filepath.IsAbs(s) {
+		return s
+	}
+	path, _ := filepath.Abs(s)
+	return path
+}
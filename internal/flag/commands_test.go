@@ -0,0 +1,110 @@
+package flag_test
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gi8lino/tiledash/internal/flag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseValidateArgs(t *testing.T) {
+	t.Parallel()
+
+	t.Run("defaults", func(t *testing.T) {
+		t.Parallel()
+
+		cfg, err := flag.ParseValidateArgs(nil, "dev")
+		require.NoError(t, err)
+		assert.Equal(t, "config.yaml", cfg.Config)
+		assert.False(t, cfg.ResolveAuth)
+
+		abs, _ := filepath.Abs("templates")
+		assert.Equal(t, abs, cfg.TemplateDir)
+	})
+
+	t.Run("custom values", func(t *testing.T) {
+		t.Parallel()
+
+		cfg, err := flag.ParseValidateArgs([]string{"--config=ci.yaml", "--template-dir=/srv/templates", "--resolve-auth"}, "dev")
+		require.NoError(t, err)
+		assert.Equal(t, "ci.yaml", cfg.Config)
+		assert.Equal(t, "/srv/templates", cfg.TemplateDir)
+		assert.True(t, cfg.ResolveAuth)
+	})
+
+	t.Run("rejects server flags", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := flag.ParseValidateArgs([]string{"--listen-address=:8080"}, "dev")
+		require.Error(t, err)
+	})
+}
+
+func TestParseSchemaArgs(t *testing.T) {
+	t.Parallel()
+
+	require.NoError(t, flag.ParseSchemaArgs(nil, "dev"))
+	require.Error(t, flag.ParseSchemaArgs([]string{"--config=x.yaml"}, "dev"))
+}
+
+func TestParseRenderArgs(t *testing.T) {
+	t.Parallel()
+
+	t.Run("parses fixtures and params", func(t *testing.T) {
+		t.Parallel()
+
+		cfg, err := flag.ParseRenderArgs([]string{
+			"--tile=3", "--fixture=p1.json", "--fixture=p2.json", "--param=team=billing", "--dashboard=ops", "--out=tile.html",
+		}, "dev")
+		require.NoError(t, err)
+		assert.Equal(t, 3, cfg.Tile)
+		assert.Equal(t, []string{"p1.json", "p2.json"}, cfg.Fixtures)
+		assert.Equal(t, []string{"team=billing"}, cfg.Params)
+		assert.Equal(t, "ops", cfg.Dashboard)
+		assert.Equal(t, "tile.html", cfg.Out)
+		assert.Equal(t, "config.yaml", cfg.Config)
+		assert.True(t, filepath.IsAbs(cfg.TemplateDir))
+	})
+
+	t.Run("requires tile and fixture", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := flag.ParseRenderArgs([]string{"--tile=0"}, "dev")
+		require.Error(t, err)
+		_, err = flag.ParseRenderArgs([]string{"--fixture=p.json"}, "dev")
+		require.Error(t, err)
+	})
+}
+
+func TestParseFetchArgs(t *testing.T) {
+	t.Parallel()
+
+	cfg, err := flag.ParseFetchArgs([]string{"--tile=2", "--save-pages=fixtures", "--param=team=billing", "-l", "json"}, "dev")
+	require.NoError(t, err)
+	assert.Equal(t, 2, cfg.Tile)
+	assert.Equal(t, "fixtures", cfg.SaveDir)
+	assert.Equal(t, []string{"team=billing"}, cfg.Params)
+	assert.Equal(t, "json", cfg.LogFormat)
+
+	_, err = flag.ParseFetchArgs(nil, "dev")
+	require.Error(t, err, "--tile is required")
+}
+
+func TestParseExportArgs(t *testing.T) {
+	t.Parallel()
+
+	cfg, err := flag.ParseExportArgs([]string{"--dir=snapshots", "--every=15m", "--param=team=billing"}, "dev")
+	require.NoError(t, err)
+	assert.Equal(t, "snapshots", cfg.Dir)
+	assert.Equal(t, 15*time.Minute, cfg.Every)
+	assert.Equal(t, []string{"team=billing"}, cfg.Params)
+
+	_, err = flag.ParseExportArgs([]string{"--every=1m"}, "dev")
+	require.EqualError(t, err, "--every requires --dir and a positive interval")
+
+	_, err = flag.ParseExportArgs([]string{"--out=a.html", "--dir=snapshots"}, "dev")
+	require.EqualError(t, err, "--out and --dir are mutually exclusive")
+}
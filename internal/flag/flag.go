@@ -1,8 +1,10 @@
 package flag
 
 import (
+	"fmt"
 	"net"
 	"path/filepath"
+	"time"
 
 	"github.com/containeroo/httpprefix"
 	"github.com/containeroo/tinyflags"
@@ -16,6 +18,18 @@ type Config struct { // Config aggregates CLI flags after parsing.
 	Config      string // Path to config file
 	TemplateDir string // Path to template directory
 	RoutePrefix string // Canonical path prefix ("" or "/tiledash")
+	AdminToken  string // Bearer token for admin endpoints; empty disables them
+
+	CacheBackend       string        // Response cache backend ("memory", "file" or "redis")
+	CacheDir           string        // Directory for the file cache backend
+	CacheRedisURL      string        // Connection URL for the redis cache backend
+	CacheRedisPrefix   string        // Key prefix for the redis cache backend
+	CacheMaxEntries    int           // Max cached responses per provider (0 = unbounded)
+	CacheMaxBytes      int64         // Max approximate cached bytes per provider (0 = unbounded)
+	CacheSweepInterval time.Duration // How often expired cache entries are purged (0 = only on read)
+
+	PrewarmWorkers int           // Concurrent tile renders at startup (0 = no prewarm)
+	PrewarmTimeout time.Duration // Deadline for the startup prewarm
 }
 
 // ParseArgs parses CLI arguments into Config, handling version/help flags.
@@ -24,6 +38,7 @@ func ParseArgs(args []string, version string) (Config, error) { // ParseArgs par
 	tf := tinyflags.NewFlagSet("tiledash", tinyflags.ContinueOnError)
 	tf.Version(version)
 	tf.EnvPrefix("TILEDASH")
+	tf.Note("Commands: " + CommandValidate + " (check config and templates), " + CommandSchema + " (print the config JSON Schema), " + CommandRender + " (render a tile from saved responses), " + CommandFetch + " (debug a tile's upstream request), " + CommandExport + " (static HTML snapshot). Run tiledash <command> --help for details.")
 
 	// Server
 	tf.StringVar(&cfg.Config, "config", "config.yaml", "Path to config file").Value()
@@ -50,6 +65,40 @@ func ParseArgs(args []string, version string) (Config, error) { // ParseArgs par
 		Placeholder("ADDR:PORT").
 		Value()
 
+	// Admin
+	tf.StringVar(&cfg.AdminToken, "admin-token", "", "Bearer token protecting admin endpoints (cache purge). Empty = disabled.").
+		Placeholder("TOKEN").
+		Value()
+
+	// Cache
+	cacheBackend := tf.String("cache-backend", "memory", "Response cache backend").
+		Choices("memory", "file", "redis").
+		Value()
+	tf.StringVar(&cfg.CacheDir, "cache-dir", ".tiledash-cache", "Directory for the file cache backend (one subdirectory per provider)").
+		Placeholder("PATH").
+		Value()
+	tf.StringVar(&cfg.CacheRedisURL, "cache-redis-url", "redis://localhost:6379/0", "Connection URL for the redis cache backend").
+		Placeholder("URL").
+		Value()
+	tf.StringVar(&cfg.CacheRedisPrefix, "cache-redis-prefix", "tiledash", "Key prefix for the redis cache backend").
+		Placeholder("PREFIX").
+		Value()
+	tf.IntVar(&cfg.CacheMaxEntries, "cache-max-entries", 10000, "Max cached responses per provider (0 = unbounded)").
+		Placeholder("N").
+		Value()
+	tf.Int64Var(&cfg.CacheMaxBytes, "cache-max-bytes", 0, "Max approximate cached bytes per provider (0 = unbounded)").
+		Placeholder("BYTES").
+		Value()
+	tf.DurationVar(&cfg.CacheSweepInterval, "cache-sweep-interval", time.Minute, "Interval for purging expired cache entries (0 = only on read)").
+		Value()
+
+	// Prewarm
+	tf.IntVar(&cfg.PrewarmWorkers, "prewarm-workers", 4, "Concurrent tile renders when warming caches at startup (0 = disabled)").
+		Placeholder("N").
+		Value()
+	tf.DurationVar(&cfg.PrewarmTimeout, "prewarm-timeout", 30*time.Second, "Deadline for warming caches at startup").
+		Value()
+
 	// Logging
 	tf.BoolVar(&cfg.Debug, "debug", false, "Enable debug logging").Value()
 	logFormat := tf.String("log-format", "text", "Log format").Choices("text", "json").Short("l").Value()
@@ -61,8 +110,18 @@ func ParseArgs(args []string, version string) (Config, error) { // ParseArgs par
 
 	// Post-parse
 	cfg.LogFormat = *logFormat
+	cfg.CacheBackend = *cacheBackend
 	cfg.ListenAddr = (*listenAddr).String()
 
+	// Redis bounds its keyspace with the server's maxmemory policy, not per provider.
+	if cfg.CacheBackend == "redis" {
+		for _, name := range []string{"cache-max-entries", "cache-max-bytes"} {
+			if tf.LookupFlag(name).IsChanged() {
+				return Config{}, fmt.Errorf("--%s is not supported by the redis cache backend; use the server's maxmemory policy", name)
+			}
+		}
+	}
+
 	if cfg.TemplateDir == "./templates" {
 		base := filepath.Dir(".")
 		cfg.TemplateDir = filepath.Join(base, cfg.TemplateDir)
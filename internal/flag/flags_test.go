@@ -4,6 +4,7 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/gi8lino/tiledash/internal/flag"
 	"github.com/stretchr/testify/assert"
@@ -136,6 +137,87 @@ func TestParseArgs(t *testing.T) {
 		assert.Equal(t, "/path/to/my-config.yaml", cfg.Config)
 	})
 
+	t.Run("admin token", func(t *testing.T) {
+		t.Parallel()
+
+		cfg, err := flag.ParseArgs(nil, "dev")
+		require.NoError(t, err)
+		assert.Empty(t, cfg.AdminToken)
+
+		cfg, err = flag.ParseArgs([]string{"--admin-token=s3cr3t"}, "dev")
+		require.NoError(t, err)
+		assert.Equal(t, "s3cr3t", cfg.AdminToken)
+	})
+
+	t.Run("cache bounds", func(t *testing.T) {
+		t.Parallel()
+
+		cfg, err := flag.ParseArgs(nil, "dev")
+		require.NoError(t, err)
+		assert.Equal(t, 10000, cfg.CacheMaxEntries)
+		assert.Equal(t, int64(0), cfg.CacheMaxBytes)
+		assert.Equal(t, time.Minute, cfg.CacheSweepInterval)
+
+		args := []string{"--cache-max-entries=50", "--cache-max-bytes=1048576", "--cache-sweep-interval=30s"}
+		cfg, err = flag.ParseArgs(args, "dev")
+		require.NoError(t, err)
+		assert.Equal(t, 50, cfg.CacheMaxEntries)
+		assert.Equal(t, int64(1048576), cfg.CacheMaxBytes)
+		assert.Equal(t, 30*time.Second, cfg.CacheSweepInterval)
+	})
+
+	t.Run("prewarm", func(t *testing.T) {
+		t.Parallel()
+
+		cfg, err := flag.ParseArgs(nil, "dev")
+		require.NoError(t, err)
+		assert.Equal(t, 4, cfg.PrewarmWorkers)
+		assert.Equal(t, 30*time.Second, cfg.PrewarmTimeout)
+
+		cfg, err = flag.ParseArgs([]string{"--prewarm-workers=0", "--prewarm-timeout=5s"}, "dev")
+		require.NoError(t, err)
+		assert.Equal(t, 0, cfg.PrewarmWorkers)
+		assert.Equal(t, 5*time.Second, cfg.PrewarmTimeout)
+	})
+
+	t.Run("cache backend", func(t *testing.T) {
+		t.Parallel()
+
+		cfg, err := flag.ParseArgs(nil, "dev")
+		require.NoError(t, err)
+		assert.Equal(t, "memory", cfg.CacheBackend)
+		assert.Equal(t, ".tiledash-cache", cfg.CacheDir)
+
+		cfg, err = flag.ParseArgs([]string{"--cache-backend=file", "--cache-dir=/var/cache/tiledash"}, "dev")
+		require.NoError(t, err)
+		assert.Equal(t, "file", cfg.CacheBackend)
+		assert.Equal(t, "/var/cache/tiledash", cfg.CacheDir)
+
+		_, err = flag.ParseArgs([]string{"--cache-backend=nope"}, "dev")
+		require.Error(t, err)
+	})
+
+	t.Run("redis cache backend", func(t *testing.T) {
+		t.Parallel()
+
+		cfg, err := flag.ParseArgs(nil, "dev")
+		require.NoError(t, err)
+		assert.Equal(t, "redis://localhost:6379/0", cfg.CacheRedisURL)
+		assert.Equal(t, "tiledash", cfg.CacheRedisPrefix)
+
+		args := []string{"--cache-backend=redis", "--cache-redis-url=redis://redis:6379/2", "--cache-redis-prefix=board"}
+		cfg, err = flag.ParseArgs(args, "dev")
+		require.NoError(t, err)
+		assert.Equal(t, "redis", cfg.CacheBackend)
+		assert.Equal(t, "redis://redis:6379/2", cfg.CacheRedisURL)
+		assert.Equal(t, "board", cfg.CacheRedisPrefix)
+
+		_, err = flag.ParseArgs([]string{"--cache-backend=redis", "--cache-max-entries=5"}, "dev")
+		require.EqualError(t, err, "--cache-max-entries is not supported by the redis cache backend; use the server's maxmemory policy")
+		_, err = flag.ParseArgs([]string{"--cache-backend=file", "--cache-max-bytes=1024"}, "dev")
+		require.NoError(t, err)
+	})
+
 	t.Run("from env variables", func(t *testing.T) {
 		t.Setenv("TILEDASH_LOG_FORMAT", "json")
 		t.Setenv("TILEDASH_DEBUG", "true")
@@ -2,9 +2,11 @@ package handlers
 
 import (
 	"context"
+	"fmt"
 	"io/fs"
 	"log/slog"
 	"net/http"
+	"net/url"
 
 	"github.com/gi8lino/tiledash/internal/config"
 	"github.com/gi8lino/tiledash/internal/hash"
@@ -12,7 +14,32 @@ import (
 	"github.com/gi8lino/tiledash/internal/templates"
 )
 
-// BaseHandler returns a handler function that renders a dashboard.
+// cellView is a tile placeholder together with its tile index.
+type cellView struct {
+	ID int
+	config.Tile
+}
+
+// pageView is a tab of the dashboard with its own grid.
+type pageView struct {
+	Name   string
+	Title  string
+	Grid   *config.GridConfig
+	Cells  []cellView
+	Active bool
+}
+
+// paramView is a dashboard parameter with its resolved value.
+type paramView struct {
+	Name   string
+	Value  string
+	Values []string
+}
+
+// BaseHandler returns a handler function that renders the dashboard shell.
+// It never renders tiles itself; the placeholders are filled asynchronously by the client.
+// Dashboards with pages render one tab per page; ?page={name} selects the active tab.
+// Declared dashboard parameters are read from the query and forwarded to the tile API.
 func BaseHandler(
 	webFS fs.FS,
 	routePrefix string,
@@ -24,18 +51,46 @@ func BaseHandler(
 	funcMap := templates.TemplateFuncMap()
 	baseTmpl := templates.ParseBaseTemplates(webFS, funcMap)
 
+	// Named dashboards serve their tile API under /api/v1/d/{name}.
+	apiBase := routePrefix + "/api/v1"
+	if cfg.Name != "" {
+		apiBase += "/d/" + url.PathEscape(cfg.Name)
+	}
+
 	return func(w http.ResponseWriter, r *http.Request) {
+		params, err := cfg.ResolveParams(r.URL.Query())
+		if err != nil {
+			renderErrorPage(w, http.StatusBadRequest, baseTmpl, "Bad Request", "Invalid dashboard parameter.", err)
+			return
+		}
+
 		cfgHash, _ := hash.Any(cfg)
-		computeRenderedHashes(r.Context(), renderer, &cfg, logger)
+		tiles := warmTileHashes(config.WithParams(r.Context(), params), renderer, cfg.Tiles)
+
+		cells := make([]cellView, len(tiles))
+		for i, t := range tiles {
+			cells[i] = cellView{ID: i, Tile: t}
+		}
+
+		pages, err := pageViews(cfg, cells, r.URL.Query().Get("page"))
+		if err != nil {
+			renderErrorPage(w, http.StatusBadRequest, baseTmpl, "Bad Request", "Unknown dashboard page.", err)
+			return
+		}
 
 		if err := baseTmpl.ExecuteTemplate(w, "base", map[string]any{
 			"Version":         version,
 			"Grid":            cfg.Grid,
 			"Title":           cfg.Title,
 			"RoutePrefix":     routePrefix,
+			"APIBase":         apiBase,
+			"DashboardName":   cfg.Name,
 			"RefreshInterval": int(cfg.RefreshInterval.Seconds()),
 			"Customization":   &cfg.Customization,
-			"Cells":           cfg.Tiles, // pass tiles directly for async placeholder generation
+			"Cells":           cells, // placeholders for async tile loading
+			"Pages":           pages,
+			"Params":          paramViews(cfg, params),
+			"ParamsQuery":     params.Encode(),
 			"ConfigHash":      cfgHash,
 		}); err != nil {
 			renderErrorPage(w, http.StatusInternalServerError, baseTmpl, "Error", "Failed to render dashboard tiles.", err)
@@ -43,18 +98,51 @@ func BaseHandler(
 	}
 }
 
-// computeRenderedHashes populates cfg.Tiles[*].Hash using the rendered HTML of each tile.
-func computeRenderedHashes(ctx context.Context, renderer *render.TileRenderer, cfg *config.DashboardConfig, logger *slog.Logger) {
+// warmTileHashes returns a copy of tiles with Hash set from the renderer's warm cache.
+// Cold tiles keep an empty hash; the client learns it from the tile response's ETag.
+// The hashes are looked up for the parameters carried by ctx.
+func warmTileHashes(ctx context.Context, renderer *render.TileRenderer, tiles []config.Tile) []config.Tile {
+	out := make([]config.Tile, len(tiles))
+	copy(out, tiles)
 	if renderer == nil {
-		return
+		return out
+	}
+	for i := range out {
+		out[i].Hash, _ = renderer.CachedHash(ctx, i)
 	}
+	return out
+}
 
-	for i := range cfg.Tiles {
-		result, _, err := renderer.RenderTile(ctx, i)
-		if err != nil {
-			logger.Error("hash computation failed", "id", i, "error", err)
-			continue
+// pageViews groups cells by page and marks the selected page active (the first when empty).
+func pageViews(cfg config.DashboardConfig, cells []cellView, selected string) ([]pageView, error) {
+	if len(cfg.Pages) == 0 {
+		return nil, nil
+	}
+	if selected == "" {
+		selected = cfg.Pages[0].Name
+	}
+
+	pages := make([]pageView, 0, len(cfg.Pages))
+	found := false
+	for _, p := range cfg.Pages {
+		pv := pageView{Name: p.Name, Title: p.Title, Grid: p.Grid, Active: p.Name == selected}
+		for _, i := range cfg.PageTiles(p.Name) {
+			pv.Cells = append(pv.Cells, cells[i])
 		}
-		cfg.Tiles[i].Hash = result.Hash
+		found = found || pv.Active
+		pages = append(pages, pv)
+	}
+	if !found {
+		return nil, fmt.Errorf("no page %q", selected)
+	}
+	return pages, nil
+}
+
+// paramViews returns the declared parameters with their resolved values, in declaration order.
+func paramViews(cfg config.DashboardConfig, params config.Params) []paramView {
+	out := make([]paramView, 0, len(cfg.Params))
+	for _, p := range cfg.Params {
+		out = append(out, paramView{Name: p.Name, Value: params[p.Name], Values: p.Values})
 	}
+	return out
 }
@@ -8,6 +8,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"path/filepath"
+	"sync/atomic"
 	"testing"
 	"testing/fstest"
 	"time"
@@ -69,6 +70,99 @@ func TestDashboard(t *testing.T) {
 		assert.Contains(t, body, "My Dashboard v1.0.0")
 	})
 
+	t.Run("groups tiles by page", func(t *testing.T) {
+		t.Parallel()
+
+		webFS := fstest.MapFS{
+			"web/templates/base.gohtml": &fstest.MapFile{Data: []byte(
+				`{{define "base"}}{{range .Pages}}[{{.Name}}{{if .Active}}*{{end}}:{{range .Cells}}{{.ID}}={{.Title}} {{end}}]{{end}}{{end}}`,
+			)},
+			"web/templates/css/page.gohtml":    &fstest.MapFile{Data: []byte(`{{define "css_page"}}css_generic{{end}}`)},
+			"web/templates/css/debug.gohtml":   &fstest.MapFile{Data: []byte(`{{define "css_debug"}}css_debug{{end}}`)},
+			"web/templates/footer.gohtml":      &fstest.MapFile{Data: []byte(`{{define "footer"}}footer{{end}}`)},
+			"web/templates/errors/page.gohtml": &fstest.MapFile{Data: []byte(`{{define "page_error"}}Error: {{.Error}}{{end}}`)},
+		}
+		cfg := config.DashboardConfig{
+			Pages: []config.Page{{Name: "overview"}, {Name: "drill"}},
+			Tiles: []config.Tile{
+				{Title: "a", Page: "overview"},
+				{Title: "b", Page: "drill"},
+				{Title: "c", Page: "drill"},
+			},
+		}
+		handler := BaseHandler(webFS, "", "1.0.0", cfg, nil, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "[overview*:0=a ][drill:1=b 2=c ]", w.Body.String())
+
+		w = httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/?page=drill", nil))
+		assert.Equal(t, "[overview:0=a ][drill*:1=b 2=c ]", w.Body.String())
+
+		w = httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/?page=nope", nil))
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Equal(t, "Error: no page &#34;nope&#34;", w.Body.String())
+	})
+
+	t.Run("resolves dashboard params", func(t *testing.T) {
+		t.Parallel()
+
+		webFS := fstest.MapFS{
+			"web/templates/base.gohtml": &fstest.MapFile{Data: []byte(
+				`{{define "base"}}{{.ParamsQuery}}|{{range .Params}}{{.Name}}={{.Value}}{{.Values}} {{end}}{{end}}`,
+			)},
+			"web/templates/css/page.gohtml":    &fstest.MapFile{Data: []byte(`{{define "css_page"}}css_generic{{end}}`)},
+			"web/templates/css/debug.gohtml":   &fstest.MapFile{Data: []byte(`{{define "css_debug"}}css_debug{{end}}`)},
+			"web/templates/footer.gohtml":      &fstest.MapFile{Data: []byte(`{{define "footer"}}footer{{end}}`)},
+			"web/templates/errors/page.gohtml": &fstest.MapFile{Data: []byte(`{{define "page_error"}}Error: {{.Error}}{{end}}`)},
+		}
+		cfg := config.DashboardConfig{Params: []config.Param{
+			{Name: "team", Default: "payments", Values: []string{"payments", "billing"}},
+			{Name: "env", Default: "prod"},
+		}}
+		handler := BaseHandler(webFS, "", "1.0.0", cfg, nil, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "env=prod&amp;team=payments|team=payments[payments billing] env=prod[] ", w.Body.String())
+
+		w = httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/?team=billing&env=dev", nil))
+		assert.Equal(t, "env=dev&amp;team=billing|team=billing[payments billing] env=dev[] ", w.Body.String())
+
+		w = httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/?team=ops", nil))
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "is not allowed")
+	})
+
+	t.Run("named dashboards use their own API base", func(t *testing.T) {
+		t.Parallel()
+
+		webFS := fstest.MapFS{
+			"web/templates/base.gohtml":        &fstest.MapFile{Data: []byte(`{{define "base"}}{{.APIBase}}|{{.DashboardName}}{{end}}`)},
+			"web/templates/css/page.gohtml":    &fstest.MapFile{Data: []byte(`{{define "css_page"}}css_generic{{end}}`)},
+			"web/templates/css/debug.gohtml":   &fstest.MapFile{Data: []byte(`{{define "css_debug"}}css_debug{{end}}`)},
+			"web/templates/footer.gohtml":      &fstest.MapFile{Data: []byte(`{{define "footer"}}footer{{end}}`)},
+			"web/templates/errors/page.gohtml": &fstest.MapFile{Data: []byte(`{{define "page_error"}}Error: {{.Message}}{{end}}`)},
+		}
+		logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+		w := httptest.NewRecorder()
+		BaseHandler(webFS, "/tiledash", "dev", config.DashboardConfig{}, nil, logger).
+			ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+		assert.Equal(t, "/tiledash/api/v1|", w.Body.String())
+
+		w = httptest.NewRecorder()
+		BaseHandler(webFS, "/tiledash", "dev", config.DashboardConfig{Name: "team-a"}, nil, logger).
+			ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/d/team-a", nil))
+		assert.Equal(t, "/tiledash/api/v1/d/team-a|team-a", w.Body.String())
+	})
+
 	t.Run("renders page_error when base template execution fails", func(t *testing.T) {
 		t.Parallel()
 
@@ -116,10 +210,10 @@ func TestDashboard(t *testing.T) {
 	})
 }
 
-func TestComputeRenderedHashes(t *testing.T) {
+func TestWarmTileHashes(t *testing.T) {
 	t.Parallel()
 
-	t.Run("uses rendered HTML hash per tile", func(t *testing.T) {
+	t.Run("uses only warm rendered hashes", func(t *testing.T) {
 		t.Parallel()
 
 		tmpDir := t.TempDir()
@@ -134,6 +228,7 @@ func TestComputeRenderedHashes(t *testing.T) {
 					Title:    "First",
 					Template: "tile.gohtml",
 					Position: config.Position{Row: 1, Col: 1},
+					Request:  config.Request{TTL: time.Minute},
 				},
 			},
 		}
@@ -141,22 +236,30 @@ func TestComputeRenderedHashes(t *testing.T) {
 		tmpl, err := templates.ParseCellTemplates(tmpDir, templates.TemplateFuncMap())
 		require.NoError(t, err)
 
+		var calls atomic.Int32
 		runners := []providers.Runner{
-			fakeRunner{
-				acc:    providers.Accumulator(map[string]any{"foo": "bar"}),
-				pages:  1,
-				status: http.StatusOK,
-			},
+			mockRunner{fn: func(ctx context.Context) (providers.Accumulator, int, int, error) {
+				calls.Add(1)
+				return providers.Accumulator{"foo": "bar"}, 1, http.StatusOK, nil
+			}},
 		}
 
 		logger := slog.New(slog.NewTextHandler(io.Discard, nil))
 		renderer := render.NewTileRenderer(cfg, runners, tmpl, logger)
 
-		computeRenderedHashes(context.Background(), renderer, &cfg, logger)
+		// Cold: no hash and no render.
+		tiles := warmTileHashes(context.Background(), renderer, cfg.Tiles)
+		assert.Empty(t, tiles[0].Hash)
+		assert.Equal(t, int32(0), calls.Load())
 
-		require.NotEmpty(t, cfg.Tiles[0].Hash)
+		// Warm: hash comes from the renderer cache.
+		_, _, rerr := renderer.RenderTile(context.Background(), 0)
+		require.Nil(t, rerr)
+		tiles = warmTileHashes(context.Background(), renderer, cfg.Tiles)
 		expected, err := hash.Any("<div>First</div>")
 		require.NoError(t, err)
-		assert.Equal(t, expected, cfg.Tiles[0].Hash)
+		assert.Equal(t, expected, tiles[0].Hash)
+		assert.Equal(t, int32(1), calls.Load())
+		assert.Empty(t, cfg.Tiles[0].Hash, "config tiles must not be mutated")
 	})
 }
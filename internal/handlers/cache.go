@@ -0,0 +1,202 @@
+package handlers
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gi8lino/tiledash/internal/providers"
+)
+
+// purgeResult is the JSON body returned by CachePurgeHandler.
+type purgeResult struct {
+	Scope     string `json:"scope"`
+	Dashboard string `json:"dashboard,omitempty"`
+	Target    string `json:"target,omitempty"`
+	Responses int    `json:"responses"`       // upstream responses dropped from provider caches
+	Renders   int    `json:"renders"`         // rendered tiles dropped from the renderer cache
+	Error     string `json:"error,omitempty"` // set when a provider cache could not be purged completely
+}
+
+// CachePurgeHandler drops cached upstream responses and rendered tiles on demand.
+//
+// The scope is selected via query parameters:
+//   - ?tile={id}         purge a single tile (its runner's responses + its render)
+//   - ?provider={name}   purge a provider cache and the renders of all tiles using it
+//   - ?dashboard={name}  purge the responses and renders of one dashboard's tiles
+//   - no parameter       purge everything
+//
+// With several dashboards, ?tile must be combined with ?dashboard.
+// Requests must carry "Authorization: Bearer <token>" matching adminToken.
+func CachePurgeHandler(
+	adminToken string,
+	dashboards []Dashboard,
+	reg providers.Registry,
+	logger *slog.Logger,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !authorized(r, adminToken) {
+			writeUnauthorized(w)
+			return
+		}
+
+		q := r.URL.Query()
+		tileID := strings.TrimSpace(q.Get("tile"))
+		provName := strings.TrimSpace(q.Get("provider"))
+		dashName := strings.TrimSpace(q.Get("dashboard"))
+
+		// Resolve the dashboard for tile/dashboard scopes.
+		var dash *Dashboard
+		switch {
+		case dashName != "":
+			for i := range dashboards {
+				if dashboards[i].Config.Name == dashName {
+					dash = &dashboards[i]
+				}
+			}
+			if dash == nil {
+				http.Error(w, "unknown dashboard", http.StatusNotFound)
+				return
+			}
+		case len(dashboards) == 1:
+			dash = &dashboards[0]
+		}
+
+		var res purgeResult
+		switch {
+		case tileID != "" && provName != "":
+			http.Error(w, "use either tile or provider, not both", http.StatusBadRequest)
+			return
+
+		case provName != "" && dashName != "":
+			http.Error(w, "use either dashboard or provider, not both", http.StatusBadRequest)
+			return
+
+		case tileID != "":
+			if dash == nil {
+				http.Error(w, "dashboard is required to purge a tile", http.StatusBadRequest)
+				return
+			}
+			idx, err := strconv.Atoi(tileID)
+			if err != nil || idx < 0 || idx >= len(dash.Runners) {
+				http.Error(w, "invalid tile id", http.StatusNotFound)
+				return
+			}
+			res = purgeResult{Scope: "tile", Dashboard: dashName, Target: tileID}
+			res.Responses = purgeRunner(dash.Runners[idx])
+			if dash.Renderer != nil && dash.Renderer.Invalidate(idx) {
+				res.Renders = 1
+			}
+
+		case provName != "":
+			prov, ok := reg.Lookup(provName)
+			if !ok {
+				http.Error(w, "unknown provider", http.StatusNotFound)
+				return
+			}
+			res = purgeResult{Scope: "provider", Target: prov.Name}
+			n, err := prov.PurgeCache()
+			res.Responses = n
+			if err != nil {
+				res.Error = err.Error()
+			}
+			for _, d := range dashboards {
+				for i, tile := range d.Config.Tiles {
+					if !strings.EqualFold(strings.TrimSpace(tile.Request.Provider), prov.Name) {
+						continue
+					}
+					if d.Renderer != nil && d.Renderer.Invalidate(i) {
+						res.Renders++
+					}
+				}
+			}
+
+		case dashName != "":
+			res = purgeResult{Scope: "dashboard", Dashboard: dashName}
+			for _, runner := range dash.Runners {
+				res.Responses += purgeRunner(runner)
+			}
+			if dash.Renderer != nil {
+				res.Renders = dash.Renderer.InvalidateAll()
+			}
+
+		default:
+			res = purgeResult{Scope: "all"}
+			var errs []error
+			for _, prov := range reg {
+				n, err := prov.PurgeCache()
+				res.Responses += n
+				if err != nil {
+					errs = append(errs, fmt.Errorf("provider %q: %w", prov.Name, err))
+				}
+			}
+			if err := errors.Join(errs...); err != nil {
+				res.Error = err.Error()
+			}
+			for _, d := range dashboards {
+				if d.Renderer != nil {
+					res.Renders += d.Renderer.InvalidateAll()
+				}
+			}
+		}
+
+		status := http.StatusOK
+		if res.Error != "" {
+			// Report the partial counts with the failure instead of pretending the purge succeeded.
+			status = http.StatusInternalServerError
+			logger.Error("cache purge incomplete", "scope", res.Scope, "target", res.Target, "responses", res.Responses, "renders", res.Renders, "error", res.Error)
+		} else {
+			logger.Info("cache purged", "scope", res.Scope, "dashboard", res.Dashboard, "target", res.Target, "responses", res.Responses, "renders", res.Renders)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		_ = json.NewEncoder(w).Encode(res)
+	}
+}
+
+// purgeRunner drops a runner's cached responses if it supports targeted purges.
+func purgeRunner(r providers.Runner) int {
+	if p, ok := r.(providers.CachePurger); ok {
+		return p.PurgeCache()
+	}
+	return 0
+}
+
+// CacheStatsHandler reports per-provider response cache statistics as JSON.
+// Requests must carry "Authorization: Bearer <token>" matching adminToken.
+func CacheStatsHandler(adminToken string, reg providers.Registry) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !authorized(r, adminToken) {
+			writeUnauthorized(w)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		_ = json.NewEncoder(w).Encode(reg.CacheStats())
+	}
+}
+
+// authorized reports whether the request carries the expected bearer token (constant-time compare).
+func authorized(r *http.Request, token string) bool {
+	if token == "" {
+		return false
+	}
+	got, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(strings.TrimSpace(got)), []byte(token)) == 1
+}
+
+// writeUnauthorized responds with 401 and a bearer challenge.
+func writeUnauthorized(w http.ResponseWriter) {
+	w.Header().Set("WWW-Authenticate", `Bearer realm="tiledash"`)
+	http.Error(w, "unauthorized", http.StatusUnauthorized)
+}
@@ -0,0 +1,254 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gi8lino/tiledash/internal/cache"
+	"github.com/gi8lino/tiledash/internal/config"
+	"github.com/gi8lino/tiledash/internal/providers"
+	"github.com/gi8lino/tiledash/internal/render"
+	"github.com/gi8lino/tiledash/internal/templates"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCachePurgeHandler(t *testing.T) {
+	t.Parallel()
+
+	// setup wires one provider backed by a counting upstream and two tiles using it.
+	setup := func(t *testing.T) (http.HandlerFunc, *render.TileRenderer, *int32) {
+		t.Helper()
+
+		var hits int32
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&hits, 1)
+			_, _ = w.Write([]byte(`{"v":"` + r.URL.Path + `"}`))
+		}))
+		t.Cleanup(ts.Close)
+
+		tmpDir := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "tile.gohtml"), []byte(`{{define "tile.gohtml"}}{{index .Data "v"}}{{end}}`), 0o644))
+		cellTmpl, err := templates.ParseCellTemplates(tmpDir, templates.TemplateFuncMap())
+		require.NoError(t, err)
+
+		cfg := config.DashboardConfig{
+			Providers: map[string]config.Provider{"jira": {BaseURL: ts.URL}},
+			Tiles: []config.Tile{
+				{Title: "A", Template: "tile.gohtml", Request: config.Request{Provider: "jira", Path: "/a", TTL: time.Minute}},
+				{Title: "B", Template: "tile.gohtml", Request: config.Request{Provider: "jira", Path: "/b", TTL: time.Minute}},
+			},
+		}
+		reg, err := providers.BuildRegistry(cfg.Providers, nil)
+		require.NoError(t, err)
+		runners, err := providers.BuildRunners(reg, cfg.Tiles)
+		require.NoError(t, err)
+
+		logger := slog.New(slog.NewTextHandler(&bytes.Buffer{}, nil))
+		renderer := render.NewTileRenderer(cfg, runners, cellTmpl, logger)
+
+		// Warm both tiles.
+		for i := range cfg.Tiles {
+			_, _, rerr := renderer.RenderTile(context.Background(), i)
+			require.Nil(t, rerr)
+		}
+		require.Equal(t, int32(2), atomic.LoadInt32(&hits))
+
+		return CachePurgeHandler("s3cr3t", []Dashboard{{Config: cfg, Runners: runners, Renderer: renderer}}, reg, logger), renderer, &hits
+	}
+
+	purge := func(h http.HandlerFunc, query, token string) (*httptest.ResponseRecorder, purgeResult) {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/cache/purge"+query, nil)
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+
+		var res purgeResult
+		_ = json.Unmarshal(w.Body.Bytes(), &res)
+		return w, res
+	}
+
+	t.Run("rejects missing or wrong token", func(t *testing.T) {
+		t.Parallel()
+		h, _, _ := setup(t)
+
+		w, _ := purge(h, "", "")
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+		w, _ = purge(h, "", "nope")
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("purges a single tile", func(t *testing.T) {
+		t.Parallel()
+		h, renderer, hits := setup(t)
+
+		w, res := purge(h, "?tile=1", "s3cr3t")
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, purgeResult{Scope: "tile", Target: "1", Responses: 1, Renders: 1}, res)
+
+		// Tile 0 is still served from cache, tile 1 hits upstream again.
+		_, _, _ = renderer.RenderTile(context.Background(), 0)
+		_, _, _ = renderer.RenderTile(context.Background(), 1)
+		assert.Equal(t, int32(3), atomic.LoadInt32(hits))
+	})
+
+	t.Run("purges a provider", func(t *testing.T) {
+		t.Parallel()
+		h, renderer, hits := setup(t)
+
+		w, res := purge(h, "?provider=JIRA", "s3cr3t")
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, purgeResult{Scope: "provider", Target: "jira", Responses: 2, Renders: 2}, res)
+
+		_, _, _ = renderer.RenderTile(context.Background(), 0)
+		_, _, _ = renderer.RenderTile(context.Background(), 1)
+		assert.Equal(t, int32(4), atomic.LoadInt32(hits))
+	})
+
+	t.Run("purges everything", func(t *testing.T) {
+		t.Parallel()
+		h, _, _ := setup(t)
+
+		w, res := purge(h, "", "s3cr3t")
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+		assert.Equal(t, purgeResult{Scope: "all", Responses: 2, Renders: 2}, res)
+	})
+
+	t.Run("reports incomplete purges", func(t *testing.T) {
+		t.Parallel()
+		reg, err := providers.BuildRegistry(map[string]config.Provider{"jira": {BaseURL: "http://example.com"}},
+			func(string) (cache.Cache, error) { return failingPurgeCache{cache.NewMemCache()}, nil })
+		require.NoError(t, err)
+		h := CachePurgeHandler("s3cr3t", nil, reg, slog.New(slog.NewTextHandler(&bytes.Buffer{}, nil)))
+
+		w, res := purge(h, "", "s3cr3t")
+		require.Equal(t, http.StatusInternalServerError, w.Code)
+		assert.Equal(t, purgeResult{Scope: "all", Responses: 1, Error: `provider "jira": connection reset`}, res)
+
+		w, res = purge(h, "?provider=jira", "s3cr3t")
+		require.Equal(t, http.StatusInternalServerError, w.Code)
+		assert.Equal(t, purgeResult{Scope: "provider", Target: "jira", Responses: 1, Error: "connection reset"}, res)
+	})
+
+	t.Run("rejects unknown targets", func(t *testing.T) {
+		t.Parallel()
+		h, _, _ := setup(t)
+
+		w, _ := purge(h, "?tile=7", "s3cr3t")
+		assert.Equal(t, http.StatusNotFound, w.Code)
+
+		w, _ = purge(h, "?provider=nope", "s3cr3t")
+		assert.Equal(t, http.StatusNotFound, w.Code)
+
+		w, _ = purge(h, "?provider=jira&tile=0", "s3cr3t")
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		w, _ = purge(h, "?dashboard=nope", "s3cr3t")
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("scopes purges to named dashboards", func(t *testing.T) {
+		t.Parallel()
+
+		var hits int32
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&hits, 1)
+			_, _ = w.Write([]byte(`{"v":"` + r.URL.Path + `"}`))
+		}))
+		t.Cleanup(ts.Close)
+
+		tmpDir := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "tile.gohtml"), []byte(`{{define "tile.gohtml"}}{{index .Data "v"}}{{end}}`), 0o644))
+		cellTmpl, err := templates.ParseCellTemplates(tmpDir, templates.TemplateFuncMap())
+		require.NoError(t, err)
+
+		reg, err := providers.BuildRegistry(map[string]config.Provider{"jira": {BaseURL: ts.URL}}, nil)
+		require.NoError(t, err)
+		pool := providers.NewRunnerPool(reg)
+		logger := slog.New(slog.NewTextHandler(&bytes.Buffer{}, nil))
+
+		var dashboards []Dashboard
+		for _, name := range []string{"a", "b"} {
+			cfg := config.DashboardConfig{
+				Name:  name,
+				Tiles: []config.Tile{{Title: name, Template: "tile.gohtml", Request: config.Request{Provider: "jira", Path: "/" + name, TTL: time.Minute}}},
+			}
+			runners, err := pool.Build(cfg.Tiles)
+			require.NoError(t, err)
+			renderer := render.NewTileRenderer(cfg, runners, cellTmpl, logger)
+			_, _, rerr := renderer.RenderTile(context.Background(), 0)
+			require.Nil(t, rerr)
+			dashboards = append(dashboards, Dashboard{Config: cfg, Runners: runners, Renderer: renderer})
+		}
+		h := CachePurgeHandler("s3cr3t", dashboards, reg, logger)
+
+		w, _ := purge(h, "?tile=0", "s3cr3t")
+		assert.Equal(t, http.StatusBadRequest, w.Code, "tile needs a dashboard when several are served")
+
+		w, res := purge(h, "?dashboard=b&tile=0", "s3cr3t")
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, purgeResult{Scope: "tile", Dashboard: "b", Target: "0", Responses: 1, Renders: 1}, res)
+
+		w, res = purge(h, "?dashboard=a", "s3cr3t")
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, purgeResult{Scope: "dashboard", Dashboard: "a", Responses: 1, Renders: 1}, res)
+
+		w, res = purge(h, "", "s3cr3t")
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, purgeResult{Scope: "all"}, res)
+	})
+}
+
+// failingPurgeCache is a cache whose purge drops one entry and then fails.
+type failingPurgeCache struct {
+	*cache.MemCache
+}
+
+func (failingPurgeCache) Purge() (int, error) {
+	return 1, errors.New("connection reset")
+}
+
+func TestCacheStatsHandler(t *testing.T) {
+	t.Parallel()
+
+	reg, err := providers.BuildRegistry(map[string]config.Provider{"jira": {BaseURL: "http://example.com"}}, nil)
+	require.NoError(t, err)
+	reg["jira"].Cache.Set("k", cache.Entry{Value: map[string]any{"v": 1}}, time.Minute)
+
+	h := CacheStatsHandler("s3cr3t", reg)
+
+	t.Run("rejects missing token", func(t *testing.T) {
+		t.Parallel()
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/cache/stats", nil))
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("reports stats per provider", func(t *testing.T) {
+		t.Parallel()
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/cache/stats", nil)
+		req.Header.Set("Authorization", "Bearer s3cr3t")
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		var got map[string]map[string]any
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &got))
+		require.Contains(t, got, "jira")
+		assert.EqualValues(t, 1, got["jira"]["entries"])
+	})
+}
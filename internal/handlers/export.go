@@ -0,0 +1,41 @@
+package handlers
+
+import (
+	"bytes"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/gi8lino/tiledash/internal/config"
+	"github.com/gi8lino/tiledash/internal/render"
+)
+
+// ExportHandler serves a self-contained HTML snapshot of the dashboard as a download.
+// Dashboard parameters are taken from the query, as for tiles.
+func ExportHandler(
+	exporter *render.Exporter,
+	cfg config.DashboardConfig,
+	renderer *render.TileRenderer,
+	logger *slog.Logger,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		params, err := cfg.ResolveParams(r.URL.Query())
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		var buf bytes.Buffer
+		if err := exporter.Export(config.WithParams(r.Context(), params), &buf, renderer); err != nil {
+			logger.Error("export failed", "dashboard", cfg.Name, "error", err)
+			http.Error(w, "failed to export dashboard", http.StatusInternalServerError)
+			return
+		}
+
+		filename := render.ExportFilename(cfg.Name, time.Now())
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Header().Set("Content-Disposition", `attachment; filename="`+filename+`"`)
+		w.WriteHeader(http.StatusOK)
+		_, _ = buf.WriteTo(w)
+	}
+}
@@ -12,6 +12,8 @@ import (
 
 // HashHandler returns an HTTP handler that responds with a hash of either the full config
 // or the current data for a specific tile, based on the requested path parameter.
+// The hash doubles as a strong ETag, so pollers can use If-None-Match to get a 304.
+// Tile hashes are computed for the dashboard parameters given in the query.
 func HashHandler(
 	cfg config.DashboardConfig,
 	renderer *render.TileRenderer,
@@ -28,6 +30,9 @@ func HashHandler(
 				http.Error(w, "failed to compute hash for config", http.StatusInternalServerError)
 				return
 			}
+			if handleETag(w, r, h) {
+				return
+			}
 			w.WriteHeader(http.StatusOK)
 			w.Write([]byte(h)) // nolint:errcheck
 			return
@@ -40,7 +45,13 @@ func HashHandler(
 				return
 			}
 
-			result, status, renderErr := renderer.RenderTile(r.Context(), idx)
+			params, err := cfg.ResolveParams(r.URL.Query())
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+
+			result, status, renderErr := renderer.RenderTile(config.WithParams(r.Context(), params), idx)
 			if renderErr != nil {
 				if status == http.StatusNotFound || status == http.StatusBadRequest {
 					http.Error(w, "invalid tile id", status)
@@ -51,6 +62,9 @@ func HashHandler(
 				return
 			}
 
+			if handleETag(w, r, result.Hash) {
+				return
+			}
 			w.WriteHeader(http.StatusOK)
 			w.Write([]byte(result.Hash)) // nolint:errcheck
 		}
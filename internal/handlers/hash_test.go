@@ -95,6 +95,16 @@ func TestHashHandler(t *testing.T) {
 		expected, herr := hash.Any("bar")
 		require.NoError(t, herr)
 		assert.Equal(t, expected, body)
+		assert.Equal(t, `"`+expected+`"`, res.Header.Get("ETag"))
+
+		// A poller presenting the current hash gets 304.
+		req = httptest.NewRequest(http.MethodGet, "/api/v1/hash/0", nil)
+		req.SetPathValue("id", "0")
+		req.Header.Set("If-None-Match", `"`+expected+`"`)
+		w = httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusNotModified, w.Code)
+		assert.Empty(t, w.Body.String())
 	})
 
 	t.Run("tile hash reflects runner data", func(t *testing.T) {
@@ -0,0 +1,52 @@
+package handlers
+
+import (
+	"io/fs"
+	"net/http"
+
+	"github.com/gi8lino/tiledash/internal/config"
+	"github.com/gi8lino/tiledash/internal/providers"
+	"github.com/gi8lino/tiledash/internal/render"
+	"github.com/gi8lino/tiledash/internal/templates"
+)
+
+// Dashboard bundles a dashboard's config with the runners and renderer serving its tiles.
+type Dashboard struct {
+	Config   config.DashboardConfig
+	Runners  []providers.Runner
+	Renderer *render.TileRenderer
+}
+
+// IndexHandler renders a page linking to every named dashboard at /d/{name}
+// and every playlist at /p/{name}.
+func IndexHandler(
+	webFS fs.FS,
+	routePrefix string,
+	version string,
+	dashboards []Dashboard,
+	playlists []config.Playlist,
+) http.HandlerFunc {
+	funcMap := templates.TemplateFuncMap()
+	indexTmpl := templates.ParseIndexTemplates(webFS, funcMap)
+
+	type entry struct {
+		Name  string
+		Title string
+		Tiles int
+	}
+	entries := make([]entry, 0, len(dashboards))
+	for _, d := range dashboards {
+		entries = append(entries, entry{Name: d.Config.Name, Title: d.Config.Title, Tiles: len(d.Config.Tiles)})
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		if err := indexTmpl.ExecuteTemplate(w, "index", map[string]any{
+			"Version":     version,
+			"RoutePrefix": routePrefix,
+			"Dashboards":  entries,
+			"Playlists":   playlists,
+		}); err != nil {
+			renderErrorPage(w, http.StatusInternalServerError, indexTmpl, "Error", "Failed to render dashboard index.", err)
+		}
+	}
+}
@@ -0,0 +1,40 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"testing/fstest"
+
+	"github.com/gi8lino/tiledash/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIndexHandler(t *testing.T) {
+	t.Parallel()
+
+	webFS := fstest.MapFS{
+		"web/templates/index.gohtml": &fstest.MapFile{Data: []byte(
+			`{{define "index"}}{{range .Dashboards}}<a href="{{$.RoutePrefix}}/d/{{.Name}}">{{.Title}} ({{.Tiles}})</a>{{end}} v{{.Version}}{{end}}`,
+		)},
+		"web/templates/errors/page.gohtml": &fstest.MapFile{Data: []byte(`{{define "page_error"}}Error: {{.Message}}{{end}}`)},
+	}
+
+	t.Run("lists dashboards", func(t *testing.T) {
+		t.Parallel()
+
+		dashboards := []Dashboard{
+			{Config: config.DashboardConfig{Name: "team-a", Title: "Team A", Tiles: []config.Tile{{}, {}}}},
+			{Config: config.DashboardConfig{Name: "team-b", Title: "Team B"}},
+		}
+
+		w := httptest.NewRecorder()
+		IndexHandler(webFS, "/tiledash", "1.2.3", dashboards, nil).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t,
+			`<a href="/tiledash/d/team-a">Team A (2)</a><a href="/tiledash/d/team-b">Team B (0)</a> v1.2.3`,
+			w.Body.String())
+	})
+}
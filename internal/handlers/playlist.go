@@ -0,0 +1,97 @@
+package handlers
+
+import (
+	"fmt"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/gi8lino/tiledash/internal/config"
+	"github.com/gi8lino/tiledash/internal/templates"
+)
+
+// playlistEntry is a resolved playlist stop as consumed by playlist.js.
+type playlistEntry struct {
+	Name    string `json:"name"`
+	Title   string `json:"title"`
+	URL     string `json:"url"`
+	Seconds int    `json:"seconds"`
+}
+
+// PlaylistHandler renders a kiosk page that rotates through the playlist's dashboards.
+// The optional ?start= parameter selects the first entry by 1-based position or dashboard name.
+func PlaylistHandler(
+	webFS fs.FS,
+	routePrefix string,
+	version string,
+	playlist config.Playlist,
+	dashboards []Dashboard,
+) http.HandlerFunc {
+	funcMap := templates.TemplateFuncMap()
+	playlistTmpl := templates.ParsePlaylistTemplates(webFS, funcMap)
+
+	boards := make(map[string]config.DashboardConfig, len(dashboards))
+	for _, d := range dashboards {
+		boards[d.Config.Name] = d.Config
+	}
+
+	entries := make([]playlistEntry, 0, len(playlist.Entries))
+	for _, e := range playlist.Entries {
+		board := boards[e.Dashboard]
+		pe := playlistEntry{
+			Name:    e.Dashboard,
+			Title:   board.Title,
+			URL:     routePrefix + "/d/" + url.PathEscape(e.Dashboard),
+			Seconds: int(playlist.Dwell(e).Seconds()),
+		}
+		if e.Page != "" {
+			pe.URL += "?page=" + url.QueryEscape(e.Page)
+			for _, p := range board.Pages {
+				if p.Name == e.Page {
+					pe.Title += " – " + p.Title
+				}
+			}
+		}
+		entries = append(entries, pe)
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		start, err := playlistStart(r.URL.Query().Get("start"), entries)
+		if err != nil {
+			renderErrorPage(w, http.StatusBadRequest, playlistTmpl, "Bad Request", "Invalid playlist start entry.", err)
+			return
+		}
+
+		if err := playlistTmpl.ExecuteTemplate(w, "playlist", map[string]any{
+			"Version":     version,
+			"RoutePrefix": routePrefix,
+			"Name":        playlist.Name,
+			"Entries":     entries,
+			"Start":       start,
+		}); err != nil {
+			renderErrorPage(w, http.StatusInternalServerError, playlistTmpl, "Error", "Failed to render playlist.", err)
+		}
+	}
+}
+
+// playlistStart resolves ?start= to a 0-based entry index. Empty means the first entry.
+func playlistStart(raw string, entries []playlistEntry) (int, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return 0, nil
+	}
+	if n, err := strconv.Atoi(raw); err == nil {
+		if n < 1 || n > len(entries) {
+			return 0, fmt.Errorf("start %d out of range (1-%d)", n, len(entries))
+		}
+		return n - 1, nil
+	}
+	for i, e := range entries {
+		if e.Name == raw {
+			return i, nil
+		}
+	}
+	return 0, fmt.Errorf("no entry for dashboard %q", raw)
+}
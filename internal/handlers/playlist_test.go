@@ -0,0 +1,78 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/gi8lino/tiledash/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPlaylistHandler(t *testing.T) {
+	t.Parallel()
+
+	webFS := fstest.MapFS{
+		"web/templates/playlist.gohtml": &fstest.MapFile{Data: []byte(
+			`{{define "playlist"}}{{.Name}} start={{.Start}} {{range .Entries}}[{{.Title}} {{.URL}} {{.Seconds}}s]{{end}}{{end}}`,
+		)},
+		"web/templates/errors/page.gohtml": &fstest.MapFile{Data: []byte(`{{define "page_error"}}Error: {{.Error}}{{end}}`)},
+	}
+	dashboards := []Dashboard{
+		{Config: config.DashboardConfig{Name: "team-a", Title: "Team A"}},
+		{Config: config.DashboardConfig{Name: "team-b", Title: "Team B", Pages: []config.Page{{Name: "ops", Title: "Ops"}}}},
+	}
+	playlist := config.Playlist{
+		Name:     "office",
+		Interval: 30 * time.Second,
+		Entries: []config.PlaylistEntry{
+			{Dashboard: "team-a"},
+			{Dashboard: "team-b", Duration: 2 * time.Minute},
+			{Dashboard: "team-b", Page: "ops"},
+		},
+	}
+	h := PlaylistHandler(webFS, "/tiledash", "1.2.3", playlist, dashboards)
+
+	t.Run("resolves entries", func(t *testing.T) {
+		t.Parallel()
+
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/p/office", nil))
+
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t,
+			"office start=0 [Team A /tiledash/d/team-a 30s][Team B /tiledash/d/team-b 120s][Team B – Ops /tiledash/d/team-b?page=ops 30s]",
+			w.Body.String())
+	})
+
+	t.Run("start by position or name", func(t *testing.T) {
+		t.Parallel()
+
+		for query, want := range map[string]string{"2": "start=1", "team-b": "start=1", "1": "start=0"} {
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/p/office?start="+query, nil))
+
+			require.Equal(t, http.StatusOK, w.Code, query)
+			assert.Contains(t, w.Body.String(), want, query)
+		}
+	})
+
+	t.Run("rejects unknown start", func(t *testing.T) {
+		t.Parallel()
+
+		for query, want := range map[string]string{
+			"4":    "start 4 out of range (1-3)",
+			"0":    "start 0 out of range (1-3)",
+			"nope": `no entry for dashboard &#34;nope&#34;`,
+		} {
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/p/office?start="+query, nil))
+
+			assert.Equal(t, http.StatusBadRequest, w.Code, query)
+			assert.Equal(t, "Error: "+want, w.Body.String(), query)
+		}
+	})
+}
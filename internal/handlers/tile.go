@@ -6,12 +6,20 @@ import (
 	"net/http"
 	"strconv"
 
+	"github.com/gi8lino/tiledash/internal/config"
 	"github.com/gi8lino/tiledash/internal/render"
 	"github.com/gi8lino/tiledash/internal/templates"
 )
 
 // TileHandler serves a tile by index using precompiled runners and cached renders.
-func TileHandler(renderer *render.TileRenderer, errTmpl *template.Template, logger *slog.Logger) http.HandlerFunc {
+// The tile hash is sent as a strong ETag; a matching If-None-Match is answered with 304.
+// Dashboard parameters are taken from the query and rendered with the tile.
+func TileHandler(
+	cfg config.DashboardConfig,
+	renderer *render.TileRenderer,
+	errTmpl *template.Template,
+	logger *slog.Logger,
+) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		id := r.PathValue("id")
 		if id == "" {
@@ -26,12 +34,23 @@ func TileHandler(renderer *render.TileRenderer, errTmpl *template.Template, logg
 			return
 		}
 
-		result, status, renderErr := renderer.RenderTile(r.Context(), idx)
+		params, err := cfg.ResolveParams(r.URL.Query())
+		if err != nil {
+			logger.Error("invalid dashboard parameter", "id", id, "error", err)
+			renderCellError(w, http.StatusBadRequest, errTmpl,
+				templates.NewRenderError("render", "Invalid dashboard parameter", err.Error()))
+			return
+		}
+
+		result, status, renderErr := renderer.RenderTile(config.WithParams(r.Context(), params), idx)
 		if renderErr != nil {
 			renderCellError(w, status, errTmpl, renderErr)
 			return
 		}
 
+		if handleETag(w, r, result.Hash) {
+			return
+		}
 		w.Header().Set("Content-Type", "text/html")
 		w.WriteHeader(http.StatusOK)
 		_, _ = w.Write([]byte(result.HTML))
@@ -86,7 +86,7 @@ func TestTileHandler(t *testing.T) {
 
 		renderer := render.NewTileRenderer(cfg, runners, cellTmpl, logger)
 
-		h := TileHandler(renderer, errTmpl, logger)
+		h := TileHandler(cfg, renderer, errTmpl, logger)
 		h.ServeHTTP(w, req)
 
 		res := w.Result()
@@ -97,6 +97,21 @@ func TestTileHandler(t *testing.T) {
 
 		assert.Equal(t, http.StatusOK, res.StatusCode)
 		assert.Contains(t, string(body), "Cell: Test Cell / value")
+
+		result, _, rerr := renderer.RenderTile(context.Background(), 0)
+		require.Nil(t, rerr)
+		etag := res.Header.Get("ETag")
+		assert.Equal(t, `"`+result.Hash+`"`, etag)
+		assert.Equal(t, "no-cache", res.Header.Get("Cache-Control"))
+
+		// Revalidating with the ETag yields 304 and no body.
+		req = httptest.NewRequest(http.MethodGet, "/api/v1/tile/0", nil)
+		req.SetPathValue("id", "0")
+		req.Header.Set("If-None-Match", etag)
+		w = httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusNotModified, w.Code)
+		assert.Empty(t, w.Body.String())
 	})
 
 	t.Run("renders error on upstream failure", func(t *testing.T) {
@@ -140,7 +155,7 @@ func TestTileHandler(t *testing.T) {
 		require.NoError(t, err)
 		renderer := render.NewTileRenderer(cfg, runners, cellTmpl, logger)
 
-		h := TileHandler(renderer, errTmpl, logger)
+		h := TileHandler(cfg, renderer, errTmpl, logger)
 		h.ServeHTTP(w, req)
 
 		res := w.Result()
@@ -149,6 +164,8 @@ func TestTileHandler(t *testing.T) {
 		require.NoError(t, err)
 
 		require.Equal(t, http.StatusInternalServerError, res.StatusCode)
+		assert.Empty(t, res.Header.Get("ETag"))
+		assert.Equal(t, "no-store", res.Header.Get("Cache-Control"))
 
 		// The handler sets Message="request failed"
 		assert.Equal(t, `<div class="error">Error: request failed</div>`, strings.TrimSpace(string(body)))
@@ -180,7 +197,7 @@ func TestTileHandler(t *testing.T) {
 		require.NoError(t, err)
 		renderer := render.NewTileRenderer(cfg, runners, cellTmpl, logger)
 
-		h := TileHandler(renderer, errTmpl, logger)
+		h := TileHandler(cfg, renderer, errTmpl, logger)
 		h.ServeHTTP(w, req)
 
 		res := w.Result()
@@ -217,7 +234,7 @@ func TestTileHandler(t *testing.T) {
 		require.NoError(t, err)
 		renderer := render.NewTileRenderer(cfg, runners, cellTmpl, logger)
 
-		h := TileHandler(renderer, errTmpl, logger)
+		h := TileHandler(cfg, renderer, errTmpl, logger)
 		h.ServeHTTP(w, req)
 
 		res := w.Result()
@@ -225,4 +242,51 @@ func TestTileHandler(t *testing.T) {
 
 		assert.Equal(t, http.StatusBadRequest, res.StatusCode)
 	})
+
+	t.Run("forwards dashboard params", func(t *testing.T) {
+		t.Parallel()
+
+		webFS := fstest.MapFS{
+			"web/templates/errors/tile.gohtml": &fstest.MapFile{
+				Data: []byte(`{{define "tile_error"}}ERROR: {{.Message}}{{end}}`),
+			},
+		}
+		tmpDir := t.TempDir()
+		testutils.MustWriteFile(t,
+			filepath.Join(tmpDir, "tile.gohtml"),
+			`{{define "tile.gohtml"}}<div>team={{ .Params.team }}</div>{{end}}`,
+		)
+
+		cfg := config.DashboardConfig{
+			Params: []config.Param{{Name: "team", Default: "payments", Values: []string{"payments", "billing"}}},
+			Tiles:  []config.Tile{{Title: "Team", Template: "tile.gohtml"}},
+		}
+		logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+		funcMap := templates.TemplateFuncMap()
+		errTmpl := templates.ParseCellErrorTemplate(webFS, funcMap)
+		cellTmpl, err := templates.ParseCellTemplates(tmpDir, funcMap)
+		require.NoError(t, err)
+		runners := []providers.Runner{fakeRunner{acc: providers.Accumulator{}, pages: 1, status: http.StatusOK}}
+		renderer := render.NewTileRenderer(cfg, runners, cellTmpl, logger)
+		h := TileHandler(cfg, renderer, errTmpl, logger)
+
+		for query, want := range map[string]string{"": "team=payments", "?team=billing": "team=billing"} {
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/tile/0"+query, nil)
+			req.SetPathValue("id", "0")
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.Contains(t, w.Body.String(), want)
+		}
+
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/tile/0?team=ops", nil)
+		req.SetPathValue("id", "0")
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "ERROR: Invalid dashboard parameter")
+	})
 }
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"html/template"
 	"net/http"
+	"strings"
 
 	"github.com/gi8lino/tiledash/internal/templates"
 )
@@ -44,9 +45,38 @@ func renderCellError(
 	re *templates.RenderError,
 ) {
 	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store") // errors are transient; never reuse them
 	w.WriteHeader(status)
 
 	if tplErr := tileErrTmpl.ExecuteTemplate(w, "tile_error", re); tplErr != nil {
 		fmt.Fprintf(w, `<div class="alert alert-danger">Failed to render tile error: %s</div>`, tplErr)
 	}
 }
+
+// cacheControlRevalidate lets browsers and proxies store a response but revalidate it before every reuse.
+const cacheControlRevalidate = "no-cache"
+
+// handleETag sets a strong ETag for hash along with Cache-Control. If the request's If-None-Match
+// already matches, it writes 304 Not Modified and returns true; the caller must not write a body.
+func handleETag(w http.ResponseWriter, r *http.Request, hash string) bool {
+	etag := `"` + hash + `"`
+	w.Header().Set("ETag", etag)
+	w.Header().Set("Cache-Control", cacheControlRevalidate)
+
+	if !etagMatches(r.Header.Get("If-None-Match"), etag) {
+		return false
+	}
+	w.WriteHeader(http.StatusNotModified)
+	return true
+}
+
+// etagMatches reports whether an If-None-Match header value matches etag (weak comparison, RFC 9110).
+func etagMatches(header, etag string) bool {
+	for tag := range strings.SplitSeq(header, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "*" || strings.TrimPrefix(tag, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
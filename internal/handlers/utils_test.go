@@ -57,3 +57,39 @@ func TestRenderErrorPage(t *testing.T) {
 		assert.Equal(t, "<div class=\"alert alert-danger\">Failed to render error page: html/template: \"page_error\" is undefined</div>", rr.Body.String())
 	})
 }
+
+func TestHandleETag(t *testing.T) {
+	t.Parallel()
+
+	t.Run("sets ETag and Cache-Control without a match", func(t *testing.T) {
+		t.Parallel()
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		rr := httptest.NewRecorder()
+
+		assert.False(t, handleETag(rr, req, "abc"))
+		assert.Equal(t, `"abc"`, rr.Header().Get("ETag"))
+		assert.Equal(t, "no-cache", rr.Header().Get("Cache-Control"))
+		assert.Equal(t, http.StatusOK, rr.Code)
+	})
+
+	t.Run("answers a matching If-None-Match with 304", func(t *testing.T) {
+		t.Parallel()
+		for _, inm := range []string{`"abc"`, `W/"abc"`, `"old", "abc"`, `*`} {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.Header.Set("If-None-Match", inm)
+			rr := httptest.NewRecorder()
+
+			assert.True(t, handleETag(rr, req, "abc"), inm)
+			assert.Equal(t, http.StatusNotModified, rr.Code, inm)
+		}
+	})
+
+	t.Run("ignores a stale If-None-Match", func(t *testing.T) {
+		t.Parallel()
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("If-None-Match", `"old"`)
+		rr := httptest.NewRecorder()
+
+		assert.False(t, handleETag(rr, req, "abc"))
+	})
+}
@@ -0,0 +1,130 @@
+package lru
+
+import (
+	"container/list"
+	"sync"
+)
+
+// Cache is a size-bounded map that evicts the least recently used entry once it is full.
+// It is safe for concurrent use.
+type Cache[K comparable, V any] struct {
+	mu      sync.Mutex
+	size    int
+	items   map[K]*list.Element // key -> element in order (value is *item[K, V])
+	order   *list.List          // front = most recently used
+	onEvict func(K, V)
+}
+
+// item is a key/value pair stored in the recency list.
+type item[K comparable, V any] struct {
+	key K
+	val V
+}
+
+// New returns a cache holding at most size entries (at least one). onEvict, if not nil,
+// is called for every entry dropped to make room, after the cache lock is released.
+func New[K comparable, V any](size int, onEvict func(K, V)) *Cache[K, V] {
+	return &Cache[K, V]{
+		size:    max(size, 1),
+		items:   make(map[K]*list.Element),
+		order:   list.New(),
+		onEvict: onEvict,
+	}
+}
+
+// Get returns the value for key and marks it as recently used.
+func (c *Cache[K, V]) Get(key K) (V, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	el, ok := c.items[key]
+	if !ok {
+		var zero V
+		return zero, false
+	}
+	c.order.MoveToFront(el)
+	return el.Value.(*item[K, V]).val, true
+}
+
+// Add stores value under key, evicting the least recently used entries beyond the size.
+func (c *Cache[K, V]) Add(key K, value V) {
+	c.mu.Lock()
+	if el, ok := c.items[key]; ok {
+		el.Value.(*item[K, V]).val = value
+		c.order.MoveToFront(el)
+		c.mu.Unlock()
+		return
+	}
+	c.items[key] = c.order.PushFront(&item[K, V]{key: key, val: value})
+
+	var evicted []*item[K, V]
+	for c.order.Len() > c.size {
+		it := c.order.Remove(c.order.Back()).(*item[K, V])
+		delete(c.items, it.key)
+		evicted = append(evicted, it)
+	}
+	c.mu.Unlock()
+
+	if c.onEvict != nil {
+		for _, it := range evicted {
+			c.onEvict(it.key, it.val)
+		}
+	}
+}
+
+// Remove deletes key and reports whether it was present.
+func (c *Cache[K, V]) Remove(key K) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	el, ok := c.items[key]
+	if ok {
+		c.order.Remove(el)
+		delete(c.items, key)
+	}
+	return ok
+}
+
+// RemoveFunc deletes every entry for which fn returns true and returns how many were removed.
+func (c *Cache[K, V]) RemoveFunc(fn func(K, V) bool) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	n := 0
+	for key, el := range c.items {
+		if fn(key, el.Value.(*item[K, V]).val) {
+			c.order.Remove(el)
+			delete(c.items, key)
+			n++
+		}
+	}
+	return n
+}
+
+// Clear deletes every entry without calling onEvict and returns the removed keys.
+func (c *Cache[K, V]) Clear() []K {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	keys := make([]K, 0, len(c.items))
+	for key := range c.items {
+		keys = append(keys, key)
+	}
+	clear(c.items)
+	c.order.Init()
+	return keys
+}
+
+// Values returns the values from the most to the least recently used.
+func (c *Cache[K, V]) Values() []V {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	out := make([]V, 0, c.order.Len())
+	for el := c.order.Front(); el != nil; el = el.Next() {
+		out = append(out, el.Value.(*item[K, V]).val)
+	}
+	return out
+}
+
+// Len returns the number of entries.
+func (c *Cache[K, V]) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
@@ -0,0 +1,82 @@
+package lru
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCache(t *testing.T) {
+	t.Parallel()
+
+	t.Run("evicts the least recently used", func(t *testing.T) {
+		t.Parallel()
+		var evicted []string
+		c := New(2, func(k string, v int) { evicted = append(evicted, k) })
+		c.Add("a", 1)
+		c.Add("b", 2)
+		_, _ = c.Get("a") // b is now the oldest
+		c.Add("c", 3)
+
+		assert.Equal(t, []string{"b"}, evicted)
+		_, ok := c.Get("b")
+		assert.False(t, ok)
+		v, ok := c.Get("a")
+		assert.True(t, ok)
+		assert.Equal(t, 1, v)
+		assert.Equal(t, 2, c.Len())
+	})
+
+	t.Run("add updates in place", func(t *testing.T) {
+		t.Parallel()
+		c := New[string, int](2, nil)
+		c.Add("a", 1)
+		c.Add("b", 2)
+		c.Add("a", 10)
+		c.Add("c", 3) // evicts b, since a was just used
+
+		assert.Equal(t, []int{3, 10}, c.Values())
+	})
+
+	t.Run("size is at least one", func(t *testing.T) {
+		t.Parallel()
+		c := New[string, int](0, nil)
+		c.Add("a", 1)
+		c.Add("b", 2)
+		assert.Equal(t, []int{2}, c.Values())
+	})
+
+	t.Run("remove", func(t *testing.T) {
+		t.Parallel()
+		c := New[string, int](3, nil)
+		c.Add("a", 1)
+		assert.True(t, c.Remove("a"))
+		assert.False(t, c.Remove("a"))
+		assert.Equal(t, 0, c.Len())
+	})
+
+	t.Run("remove func", func(t *testing.T) {
+		t.Parallel()
+		c := New[int, int](5, nil)
+		for i := range 5 {
+			c.Add(i, i*i)
+		}
+		assert.Equal(t, 2, c.RemoveFunc(func(k, v int) bool { return v > 5 }))
+		assert.Equal(t, []int{4, 1, 0}, c.Values())
+	})
+
+	t.Run("clear skips onEvict", func(t *testing.T) {
+		t.Parallel()
+		evicted := 0
+		c := New(3, func(string, int) { evicted++ })
+		c.Add("a", 1)
+		c.Add("b", 2)
+
+		keys := c.Clear()
+		sort.Strings(keys)
+		assert.Equal(t, []string{"a", "b"}, keys)
+		assert.Equal(t, 0, evicted)
+		assert.Equal(t, 0, c.Len())
+	})
+}
@@ -9,24 +9,34 @@ import (
 	"maps"
 	"net/http"
 	"net/url"
+	"slices"
 	"strings"
 	"time"
 
 	"github.com/gi8lino/tiledash/internal/cache"
 	"github.com/gi8lino/tiledash/internal/config"
 	"github.com/gi8lino/tiledash/internal/fetcher"
+	"github.com/gi8lino/tiledash/internal/lru"
 )
 
+// staleRetention is how long a page with ETag/Last-Modified validators is kept past its TTL,
+// so an expired page can be revalidated with a conditional request instead of refetched.
+const staleRetention = time.Hour
+
+// maxTrackedKeys bounds the cache keys a runner remembers for targeted purges (one per page URL).
+// Forgotten keys stay in the shared provider cache until their TTL expires.
+const maxTrackedKeys = 1024
+
 // HTTPProvider represents a single configured upstream (baseURL + auth + client).
 type HTTPProvider struct {
 	Name   string
 	Base   *url.URL
 	Auth   *config.AuthConfig
 	Client *http.Client
-	Cache  *cache.MemCache
+	Cache  cache.Cache
 }
 
-// NewHTTPProvider constructs an HTTPProvider from config.
+// NewHTTPProvider constructs an HTTPProvider from config, backed by an unbounded in-memory cache.
 func NewHTTPProvider(name string, pc config.Provider) (*HTTPProvider, error) {
 	if strings.TrimSpace(pc.BaseURL) == "" {
 		return nil, fmt.Errorf("provider %q: missing baseURL", name)
@@ -55,12 +65,16 @@ type HTTPRunner struct {
 	baseBody    map[string]any
 
 	// Pre-normalized data for the non-paginated fast path.
-	// When req.Paginate == false, these are filled and reused on every Do().
+	// When pagination is off, these are filled and reused on every Do().
 	preURL      *url.URL // absolute, normalized URL with merged query
 	preCacheKey string   // stable hash from Normalize(method+URL+headers+body)
 	preBody     []byte   // exact body bytes we will send (nil for no body)
 	preTTL      time.Duration
 	preHeaders  http.Header // includes content-type if needed
+
+	// Cache keys this runner has written, so a purge can target a single tile.
+	// Keys that fall out of the set have their pages dropped, so nothing untracked stays cached.
+	keys *lru.Cache[string, struct{}]
 }
 
 // NewRunner prepares a runnable request bound to this provider.
@@ -92,9 +106,10 @@ func (p *HTTPProvider) NewRunner(req config.Request) *HTTPRunner {
 		baseHeaders: hdr,
 		baseBody:    baseBody,
 	}
+	r.keys = lru.New[string, struct{}](maxTrackedKeys, nil)
 
 	// If the request is not paginated, fully normalize once and cache fields for reuse.
-	if !req.Paginate {
+	if !req.Paginated() {
 		// Build the exact body we will send.
 		var bodyBytes []byte
 		var contentType string
@@ -141,7 +156,7 @@ func (p *HTTPProvider) NewRunner(req config.Request) *HTTPRunner {
 
 // Do executes the configured request (paginated or not) and returns accumulator, pageCount, and HTTP status.
 func (r *HTTPRunner) Do(ctx context.Context) (Accumulator, int, int, error) {
-	if !r.req.Paginate {
+	if !r.req.Paginated() {
 		return r.runNonPaginated(ctx)
 	}
 	return r.runPaginated(ctx)
@@ -152,8 +167,10 @@ func (r *HTTPRunner) runNonPaginated(ctx context.Context) (Accumulator, int, int
 	// Fast path: use pre-normalized URL/cache key/body/headers if NewRunner succeeded in precomputing them.
 	if r.preURL != nil {
 		useCache := r.preTTL > 0 && !fetcher.IsNoCache(ctx)
+		var stale *cache.Entry
 		if useCache {
-			if page, ok := r.prov.Cache.Get(r.preCacheKey); ok {
+			var page map[string]any
+			if page, stale = r.cachedPage(r.preCacheKey); page != nil {
 				acc := newAccumulator()
 				appendPage(acc, page)
 				mergeCommonArrays(acc, page)
@@ -173,6 +190,7 @@ func (r *HTTPRunner) runNonPaginated(ctx context.Context) (Accumulator, int, int
 		}
 		req.Header = r.preHeaders.Clone() // don't mutate cached headers
 		applyAuth(req, r.prov.Auth)
+		setConditionalHeaders(req, stale)
 
 		res, err := r.prov.Client.Do(req)
 		if err != nil {
@@ -180,6 +198,14 @@ func (r *HTTPRunner) runNonPaginated(ctx context.Context) (Accumulator, int, int
 		}
 		defer res.Body.Close() // nolint:errcheck
 
+		if stale != nil && res.StatusCode == http.StatusNotModified {
+			page := r.refreshPage(r.preCacheKey, *stale, res.Header, r.preTTL)
+			acc := newAccumulator()
+			appendPage(acc, page)
+			mergeCommonArrays(acc, page)
+			return acc, 1, http.StatusOK, nil
+		}
+
 		raw, err := io.ReadAll(res.Body)
 		if err != nil {
 			return nil, 0, res.StatusCode, fmt.Errorf("read body: %w", err)
@@ -196,7 +222,7 @@ func (r *HTTPRunner) runNonPaginated(ctx context.Context) (Accumulator, int, int
 		}
 
 		if useCache {
-			r.prov.Cache.Set(r.preCacheKey, page, r.preTTL)
+			r.storePage(r.preCacheKey, page, res.Header, r.preTTL)
 		}
 
 		acc := newAccumulator()
@@ -321,8 +347,10 @@ func (r *HTTPRunner) doOnceNormalized(
 
 	// Cache lookup if allowed.
 	useCache := ttl > 0 && !fetcher.IsNoCache(ctx)
+	var stale *cache.Entry
 	if useCache {
-		if cached, ok := r.prov.Cache.Get(cacheKey); ok {
+		var cached map[string]any
+		if cached, stale = r.cachedPage(cacheKey); cached != nil {
 			return http.StatusOK, cached, nil
 		}
 	}
@@ -335,6 +363,7 @@ func (r *HTTPRunner) doOnceNormalized(
 
 	req.Header = hdr
 	applyAuth(req, r.prov.Auth)
+	setConditionalHeaders(req, stale)
 
 	res, rerr := r.prov.Client.Do(req)
 	if rerr != nil {
@@ -342,6 +371,11 @@ func (r *HTTPRunner) doOnceNormalized(
 	}
 	defer res.Body.Close() // nolint:errcheck
 
+	// Upstream confirmed our stale copy is still current.
+	if stale != nil && res.StatusCode == http.StatusNotModified {
+		return http.StatusOK, r.refreshPage(cacheKey, *stale, res.Header, ttl), nil
+	}
+
 	raw, rderr := io.ReadAll(res.Body)
 	status = res.StatusCode
 	if rderr != nil {
@@ -359,11 +393,96 @@ func (r *HTTPRunner) doOnceNormalized(
 
 	// Store in cache if enabled.
 	if useCache {
-		r.prov.Cache.Set(cacheKey, page, ttl)
+		r.storePage(cacheKey, page, res.Header, ttl)
 	}
 	return
 }
 
+// cachedPage returns the cached page for key if it is still fresh. Otherwise it returns the
+// stale entry when it carries validators, so the caller can revalidate it.
+func (r *HTTPRunner) cachedPage(key string) (map[string]any, *cache.Entry) {
+	e, ok := r.prov.Cache.Get(key)
+	if !ok {
+		return nil, nil
+	}
+	if e.IsFresh(time.Now()) {
+		return e.Value, nil
+	}
+	if e.HasValidators() {
+		return nil, &e
+	}
+	return nil, nil
+}
+
+// setConditionalHeaders turns req into a conditional request for the stale entry (if any).
+func setConditionalHeaders(req *http.Request, stale *cache.Entry) {
+	if stale == nil {
+		return
+	}
+	if stale.ETag != "" {
+		req.Header.Set("If-None-Match", stale.ETag)
+	}
+	if stale.LastModified != "" {
+		req.Header.Set("If-Modified-Since", stale.LastModified)
+	}
+}
+
+// refreshPage re-stores a stale entry after a 304 (picking up any updated validators) and returns its page.
+func (r *HTTPRunner) refreshPage(key string, stale cache.Entry, hdr http.Header, ttl time.Duration) map[string]any {
+	if v := hdr.Get("ETag"); v != "" {
+		stale.ETag = v
+	}
+	if v := hdr.Get("Last-Modified"); v != "" {
+		stale.LastModified = v
+	}
+	r.storeEntry(key, stale, ttl)
+	return stale.Value
+}
+
+// storePage writes a page and its validators (from the response headers) to the provider cache.
+func (r *HTTPRunner) storePage(key string, page map[string]any, hdr http.Header, ttl time.Duration) {
+	r.storeEntry(key, cache.Entry{
+		Value:        page,
+		ETag:         hdr.Get("ETag"),
+		LastModified: hdr.Get("Last-Modified"),
+	}, ttl)
+}
+
+// storeEntry marks e fresh for ttl, writes it to the provider cache, and remembers the key for targeted purges.
+// Entries with validators are retained past their TTL so they can be revalidated later.
+func (r *HTTPRunner) storeEntry(key string, e cache.Entry, ttl time.Duration) {
+	e.FreshUntil = time.Now().Add(ttl)
+	retain := ttl
+	if e.HasValidators() {
+		retain += staleRetention
+	}
+	r.prov.Cache.Set(key, e, retain)
+	r.keys.Add(key, struct{}{})
+}
+
+// PurgeCache drops the cached pages this runner has written (the most recent maxTrackedKeys)
+// and returns how many keys were removed.
+func (r *HTTPRunner) PurgeCache() int {
+	keys := r.keys.Clear()
+	// The precomputed key may also have been written by a previous process (persistent caches).
+	if r.preCacheKey != "" && !slices.Contains(keys, r.preCacheKey) {
+		keys = append(keys, r.preCacheKey)
+	}
+
+	n := 0
+	for _, key := range keys {
+		if r.prov.Cache.Delete(key) {
+			n++
+		}
+	}
+	return n
+}
+
+// PurgeCache drops every cached page of this provider and returns how many entries were removed.
+func (p *HTTPProvider) PurgeCache() (int, error) {
+	return p.Cache.Purge()
+}
+
 // decodeJSONUseNumber decodes JSON into a map using UseNumber to preserve integer precision.
 func decodeJSONUseNumber(raw []byte) (map[string]any, error) {
 	if len(raw) == 0 {
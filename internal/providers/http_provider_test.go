@@ -1,15 +1,19 @@
 package providers
 
 import (
+	"context"
 	"encoding/json"
 	"io"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"strconv"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/gi8lino/tiledash/internal/config"
+	"github.com/gi8lino/tiledash/internal/fetcher"
 	"github.com/gi8lino/tiledash/internal/testutils"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -229,11 +233,12 @@ func TestRunner_Paginated_QueryParams(t *testing.T) {
 
 	p, _ := NewHTTPProvider("p", config.Provider{BaseURL: ts.URL})
 
+	paginate := true
 	r := p.NewRunner(config.Request{
 		Provider: "p",
 		Method:   http.MethodGet,
 		Path:     "/items",
-		Paginate: true,
+		Paginate: &paginate,
 		Page: config.PageParams{
 			Location:   "query",
 			StartField: "start",
@@ -294,11 +299,12 @@ func TestRunner_Paginated_Body(t *testing.T) {
 
 	p, _ := NewHTTPProvider("p", config.Provider{BaseURL: ts.URL})
 
+	paginate := true
 	r := p.NewRunner(config.Request{
 		Provider: "p",
 		Method:   http.MethodPost,
 		Path:     "/body",
-		Paginate: true,
+		Paginate: &paginate,
 		Page: config.PageParams{
 			Location:   "body",
 			StartField: "startAt",
@@ -325,3 +331,148 @@ func TestRunner_Paginated_Body(t *testing.T) {
 		t.Fatalf("unexpected items type: %T", v)
 	}
 }
+
+func TestRunner_ConditionalRequests(t *testing.T) {
+	t.Parallel()
+
+	// newServer serves a fixed page with validators and answers matching conditional requests with 304.
+	newServer := func(t *testing.T) (*httptest.Server, *atomic.Int32, *atomic.Int32) {
+		t.Helper()
+		var full, notModified atomic.Int32
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("If-None-Match") == `"v1"` || r.Header.Get("If-Modified-Since") == "Mon, 02 Jan 2006 15:04:05 GMT" {
+				notModified.Add(1)
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+			full.Add(1)
+			if r.URL.Query().Get("lm") != "" {
+				w.Header().Set("Last-Modified", "Mon, 02 Jan 2006 15:04:05 GMT")
+			} else {
+				w.Header().Set("ETag", `"v1"`)
+			}
+			_ = json.NewEncoder(w).Encode(map[string]any{"items": []any{map[string]any{"id": 1}}, "total": 1})
+		}))
+		t.Cleanup(ts.Close)
+		return ts, &full, &notModified
+	}
+
+	t.Run("non-paginated revalidates with If-None-Match", func(t *testing.T) {
+		t.Parallel()
+		ts, full, notModified := newServer(t)
+		p, err := NewHTTPProvider("p", config.Provider{BaseURL: ts.URL})
+		require.NoError(t, err)
+		r := p.NewRunner(config.Request{Provider: "p", Path: "/etag", TTL: 20 * time.Millisecond})
+
+		_, _, status, err := r.Do(t.Context())
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, status)
+
+		// Fresh: served from cache without contacting upstream.
+		_, _, _, err = r.Do(t.Context())
+		require.NoError(t, err)
+		assert.Equal(t, int32(1), full.Load())
+		assert.Equal(t, int32(0), notModified.Load())
+
+		// Stale: a 304 refreshes the cached page.
+		time.Sleep(40 * time.Millisecond)
+		acc, pages, status, err := r.Do(t.Context())
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, status)
+		assert.Equal(t, 1, pages)
+		assert.Equal(t, int32(1), full.Load())
+		assert.Equal(t, int32(1), notModified.Load())
+		assert.EqualValues(t, 1, acc["pages"].([]map[string]any)[0]["total"])
+
+		// Fresh again after the refresh.
+		_, _, _, err = r.Do(t.Context())
+		require.NoError(t, err)
+		assert.Equal(t, int32(1), notModified.Load())
+	})
+
+	t.Run("paginated revalidates with If-Modified-Since", func(t *testing.T) {
+		t.Parallel()
+		ts, full, notModified := newServer(t)
+		p, err := NewHTTPProvider("p", config.Provider{BaseURL: ts.URL})
+		require.NoError(t, err)
+		paginate := true
+		r := p.NewRunner(config.Request{
+			Provider: "p",
+			Path:     "/lm",
+			Query:    map[string]string{"lm": "1"},
+			TTL:      20 * time.Millisecond,
+			Paginate: &paginate,
+			Page:     config.PageParams{StartField: "startAt", LimitField: "maxResults", TotalField: "total"},
+		})
+
+		_, _, _, err = r.Do(t.Context())
+		require.NoError(t, err)
+		time.Sleep(40 * time.Millisecond)
+
+		_, pages, status, err := r.Do(t.Context())
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, status)
+		assert.Equal(t, 1, pages)
+		assert.Equal(t, int32(1), full.Load())
+		assert.Equal(t, int32(1), notModified.Load())
+	})
+
+	t.Run("no-cache context skips revalidation", func(t *testing.T) {
+		t.Parallel()
+		ts, full, notModified := newServer(t)
+		p, err := NewHTTPProvider("p", config.Provider{BaseURL: ts.URL})
+		require.NoError(t, err)
+		r := p.NewRunner(config.Request{Provider: "p", Path: "/etag", TTL: time.Minute})
+
+		_, _, _, err = r.Do(t.Context())
+		require.NoError(t, err)
+		_, _, _, err = r.Do(context.WithValue(t.Context(), fetcher.ContextKey("nocache"), true))
+		require.NoError(t, err)
+		assert.Equal(t, int32(2), full.Load())
+		assert.Equal(t, int32(0), notModified.Load())
+	})
+}
+
+func TestRunner_PurgeCache(t *testing.T) {
+	t.Parallel()
+
+	newServer := func(t *testing.T) *httptest.Server {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_ = json.NewEncoder(w).Encode(map[string]any{"ok": true})
+		}))
+		t.Cleanup(ts.Close)
+		return ts
+	}
+
+	t.Run("counts pages written by a previous process", func(t *testing.T) {
+		t.Parallel()
+		p, err := NewHTTPProvider("p", config.Provider{BaseURL: newServer(t).URL})
+		require.NoError(t, err)
+		req := config.Request{Provider: "p", Path: "/x", TTL: time.Minute}
+
+		// A first runner fills the shared cache; a fresh runner (e.g. after a restart) never wrote it.
+		_, _, _, err = p.NewRunner(req).Do(t.Context())
+		require.NoError(t, err)
+		r := p.NewRunner(req)
+
+		assert.Equal(t, 1, r.PurgeCache())
+		assert.Equal(t, 0, r.PurgeCache())
+	})
+
+	t.Run("untracked keys are forgotten but stay in the shared cache", func(t *testing.T) {
+		t.Parallel()
+		p, err := NewHTTPProvider("p", config.Provider{BaseURL: "http://example.com"})
+		require.NoError(t, err)
+		r := p.NewRunner(config.Request{Provider: "p", Path: "/x"})
+
+		for i := range maxTrackedKeys + 2 {
+			r.storePage(strconv.Itoa(i), map[string]any{"i": i}, http.Header{}, time.Minute)
+		}
+		assert.Equal(t, maxTrackedKeys+2, p.Cache.Stats().Entries)
+		_, ok := p.Cache.Get("0")
+		assert.True(t, ok, "other runners may still use the page; it expires with its TTL")
+
+		assert.Equal(t, maxTrackedKeys, r.PurgeCache())
+		assert.Equal(t, 2, p.Cache.Stats().Entries)
+	})
+}
@@ -63,6 +63,17 @@ func mergeCommonArrays(acc Accumulator, page map[string]any) {
 	}
 }
 
+// AccumulatePages builds the accumulator a runner would return for the given response pages,
+// e.g. to render a tile from saved responses without calling the provider.
+func AccumulatePages(pages ...map[string]any) Accumulator {
+	acc := newAccumulator()
+	for _, page := range pages {
+		appendPage(acc, page)
+		mergeCommonArrays(acc, page)
+	}
+	return acc
+}
+
 // mergeCommonArraysAndCount merges like mergeCommonArrays and returns how many new items were added across all keys.
 func mergeCommonArraysAndCount(acc Accumulator, page map[string]any) int {
 	before := totalSeen(acc)
@@ -34,3 +34,23 @@ func TestMergeCommonArrays_Dedup(t *testing.T) {
 		t.Fatalf("merged issues length=%d, want %d", got, want)
 	}
 }
+
+// TestAccumulatePages ensures saved pages produce the same accumulator shape as a runner.
+func TestAccumulatePages(t *testing.T) {
+	t.Parallel()
+
+	page1 := map[string]any{"total": 3, "issues": []any{map[string]any{"id": 1}, map[string]any{"id": 2}}}
+	page2 := map[string]any{"total": 3, "issues": []any{map[string]any{"id": 3}}}
+
+	acc := AccumulatePages(page1, page2)
+
+	pages, _ := acc["pages"].([]map[string]any)
+	if got, want := len(pages), 2; got != want {
+		t.Fatalf("pages length=%d, want %d", got, want)
+	}
+	merged, _ := acc["merged"].(map[string]any)
+	iss, _ := merged["issues"].([]any)
+	if got, want := len(iss), 3; got != want {
+		t.Fatalf("merged issues length=%d, want %d", got, want)
+	}
+}
@@ -5,7 +5,9 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/gi8lino/tiledash/internal/cache"
 	"github.com/gi8lino/tiledash/internal/config"
+	"github.com/gi8lino/tiledash/internal/hash"
 )
 
 // Runner is a compiled, ready-to-execute request bound to a provider.
@@ -14,42 +16,147 @@ type Runner interface {
 	Do(ctx context.Context) (Accumulator, int, int, error)
 }
 
+// CachePurger is implemented by runners that can drop their cached upstream responses.
+type CachePurger interface {
+	// PurgeCache removes cached responses and returns how many entries were dropped.
+	PurgeCache() int
+}
+
 // Registry maps provider names to HTTPProvider instances.
 type Registry map[string]*HTTPProvider
 
 // BuildRegistry constructs a Registry from config providers.
-func BuildRegistry(cfg map[string]config.Provider) (Registry, error) {
+// newCache creates each provider's response cache; nil keeps the default in-memory cache.
+func BuildRegistry(cfg map[string]config.Provider, newCache cache.Factory) (Registry, error) {
 	out := Registry{}
 	for name, pc := range cfg {
 		key := strings.ToLower(strings.TrimSpace(name))
 		p, err := NewHTTPProvider(key, pc)
 		if err != nil {
+			out.Close()
 			return nil, err
 		}
+		if newCache != nil {
+			c, err := newCache(key)
+			if err != nil {
+				out.Close()
+				return nil, fmt.Errorf("provider %q: cache: %w", key, err)
+			}
+			p.Cache = c
+		}
 		out[key] = p
 	}
 	return out, nil
 }
 
+// TileError reports which tile (by index in the slice passed to BuildRunners or
+// RunnerPool.Build) a runner could not be built for.
+type TileError struct {
+	Index int
+	Title string
+	Err   error
+}
+
+// Error implements the error interface.
+func (e *TileError) Error() string {
+	return fmt.Sprintf("tile %d (%s): %v", e.Index, e.Title, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *TileError) Unwrap() error { return e.Err }
+
 // BuildRunners compiles runners via Registry.Compile for each tile.
+// Failures are returned as *TileError.
 func BuildRunners(reg Registry, tiles []config.Tile) ([]Runner, error) {
 	out := make([]Runner, len(tiles))
 	for i := range tiles {
-		r, err := reg.compile(tiles[i].Request)
+		r, err := reg.compile(tiles[i].Request, tiles[i].Vars)
+		if err != nil {
+			return nil, &TileError{Index: i, Title: tiles[i].Title, Err: err}
+		}
+		out[i] = r
+	}
+	return out, nil
+}
+
+// RunnerPool builds runners for several dashboards, handing out a single shared runner
+// for tiles that issue identical requests. It is meant for startup and is not safe for
+// concurrent use.
+type RunnerPool struct {
+	reg     Registry
+	runners map[string]Runner // request hash -> runner
+}
+
+// NewRunnerPool returns an empty pool compiling runners from reg.
+func NewRunnerPool(reg Registry) *RunnerPool {
+	return &RunnerPool{reg: reg, runners: map[string]Runner{}}
+}
+
+// Build returns one runner per tile, reusing runners already built for identical requests
+// (including identical vars). Failures are returned as *TileError.
+func (p *RunnerPool) Build(tiles []config.Tile) ([]Runner, error) {
+	out := make([]Runner, len(tiles))
+	for i := range tiles {
+		req := tiles[i].Request
+		req.Provider = strings.ToLower(strings.TrimSpace(req.Provider)) // provider names are case-insensitive
+		key, err := hash.Any([]any{req, tiles[i].Vars})
 		if err != nil {
-			return nil, fmt.Errorf("tile %d (%s): %w", i, tiles[i].Title, err)
+			return nil, &TileError{Index: i, Title: tiles[i].Title, Err: err}
 		}
+		if r, ok := p.runners[key]; ok {
+			out[i] = r
+			continue
+		}
+		r, err := p.reg.compile(tiles[i].Request, tiles[i].Vars)
+		if err != nil {
+			return nil, &TileError{Index: i, Title: tiles[i].Title, Err: err}
+		}
+		p.runners[key] = r
 		out[i] = r
 	}
 	return out, nil
 }
 
+// Len returns the number of distinct runners built so far.
+func (p *RunnerPool) Len() int {
+	return len(p.runners)
+}
+
+// Lookup returns the provider registered under name (case-insensitive).
+func (r Registry) Lookup(name string) (*HTTPProvider, bool) {
+	p, ok := r[strings.ToLower(strings.TrimSpace(name))]
+	return p, ok
+}
+
+// CacheStats returns cache statistics keyed by provider name.
+func (r Registry) CacheStats() map[string]cache.Stats {
+	out := make(map[string]cache.Stats, len(r))
+	for name, p := range r {
+		out[name] = p.Cache.Stats()
+	}
+	return out
+}
+
+// Close stops background work (cache janitors) of all providers.
+func (r Registry) Close() {
+	for _, p := range r {
+		p.Cache.Close()
+	}
+}
+
 // compile builds a Runner for a tile request using the registry.
-func (r Registry) compile(req config.Request) (Runner, error) {
-	name := strings.ToLower(strings.TrimSpace(req.Provider))
-	p, ok := r[name]
+// Requests containing template actions are rendered with vars on every Do.
+func (r Registry) compile(req config.Request, vars map[string]any) (Runner, error) {
+	p, ok := r.Lookup(req.Provider)
 	if !ok {
 		return nil, fmt.Errorf("unknown provider %q", req.Provider)
 	}
-	return p.NewRunner(req), nil
+	if !isTemplated(req) {
+		return p.NewRunner(req), nil
+	}
+	tmpl, err := newRequestTemplate(req, vars)
+	if err != nil {
+		return nil, err
+	}
+	return newTemplatedRunner(p, tmpl), nil
 }
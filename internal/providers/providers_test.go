@@ -2,10 +2,12 @@ package providers
 
 import (
 	"context"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 
+	"github.com/gi8lino/tiledash/internal/cache"
 	"github.com/gi8lino/tiledash/internal/config"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -25,7 +27,7 @@ func TestBuildRegistry(t *testing.T) {
 			"Jira-V2": {BaseURL: ts.URL}, // mixed case name
 		}
 
-		reg, err := BuildRegistry(provs)
+		reg, err := BuildRegistry(provs, nil)
 		require.NoError(t, err)
 		require.Len(t, reg, 1)
 
@@ -57,8 +59,31 @@ func TestBuildRegistry(t *testing.T) {
 	t.Run("BuildRegistry error on invalid baseURL", func(t *testing.T) {
 		_, err := BuildRegistry(map[string]config.Provider{
 			"bad": {BaseURL: "://bad"},
+		}, nil)
+		require.Error(t, err)
+	})
+
+	t.Run("BuildRegistry uses the cache factory per provider", func(t *testing.T) {
+		var names []string
+		reg, err := BuildRegistry(map[string]config.Provider{
+			"Jira": {BaseURL: "http://example.com"},
+		}, func(provider string) (cache.Cache, error) {
+			names = append(names, provider)
+			return cache.NewMemCache(cache.WithMaxEntries(1)), nil
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"jira"}, names)
+		require.NotNil(t, reg["jira"].Cache)
+	})
+
+	t.Run("BuildRegistry surfaces cache factory errors", func(t *testing.T) {
+		_, err := BuildRegistry(map[string]config.Provider{
+			"jira": {BaseURL: "http://example.com"},
+		}, func(string) (cache.Cache, error) {
+			return nil, errors.New("boom")
 		})
 		require.Error(t, err)
+		assert.EqualError(t, err, `provider "jira": cache: boom`)
 	})
 }
 
@@ -76,10 +101,10 @@ func TestCompile(t *testing.T) {
 		provs := map[string]config.Provider{
 			"Jira-V2": {BaseURL: ts.URL},
 		}
-		reg, err := BuildRegistry(provs)
+		reg, err := BuildRegistry(provs, nil)
 		require.NoError(t, err)
 
-		r, err := reg.compile(config.Request{Provider: "  JIRA-v2  "})
+		r, err := reg.compile(config.Request{Provider: "  JIRA-v2  "}, nil)
 		require.NoError(t, err)
 		require.NotNil(t, r)
 
@@ -93,7 +118,7 @@ func TestCompile(t *testing.T) {
 		t.Parallel()
 
 		reg := Registry{} // empty
-		_, err := reg.compile(config.Request{Provider: "missing"})
+		_, err := reg.compile(config.Request{Provider: "missing"}, nil)
 		require.Error(t, err)
 		assert.Contains(t, err.Error(), `unknown provider "missing"`)
 	})
@@ -102,7 +127,7 @@ func TestCompile(t *testing.T) {
 		t.Parallel()
 
 		reg := Registry{} // empty
-		_, err := reg.compile(config.Request{Provider: ""})
+		_, err := reg.compile(config.Request{Provider: ""}, nil)
 		require.Error(t, err)
 		assert.Contains(t, err.Error(), `unknown provider ""`)
 	})
@@ -120,4 +145,44 @@ func TestBuildRunners_ErrorWrapsTileInfo(t *testing.T) {
 	_, err := BuildRunners(reg, tiles)
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), `tile 0 (First): unknown provider "missing"`)
+
+	var tileErr *TileError
+	require.ErrorAs(t, err, &tileErr)
+	assert.Equal(t, 0, tileErr.Index)
+	assert.Equal(t, "First", tileErr.Title)
+}
+
+func TestRunnerPool(t *testing.T) {
+	t.Parallel()
+
+	reg, err := BuildRegistry(map[string]config.Provider{"jira": {BaseURL: "http://example.com"}}, nil)
+	require.NoError(t, err)
+
+	t.Run("shares runners for identical requests across builds", func(t *testing.T) {
+		t.Parallel()
+		pool := NewRunnerPool(reg)
+
+		a, err := pool.Build([]config.Tile{
+			{Title: "a", Request: config.Request{Provider: "jira", Path: "/x"}},
+			{Title: "b", Request: config.Request{Provider: "jira", Path: "/y"}},
+		})
+		require.NoError(t, err)
+		b, err := pool.Build([]config.Tile{
+			{Title: "c", Request: config.Request{Provider: "JIRA", Path: "/x"}},
+		})
+		require.NoError(t, err)
+
+		assert.Same(t, a[0], b[0])
+		assert.NotSame(t, a[0], a[1])
+		assert.Equal(t, 2, pool.Len())
+	})
+
+	t.Run("wraps errors with tile info", func(t *testing.T) {
+		t.Parallel()
+		pool := NewRunnerPool(reg)
+
+		_, err := pool.Build([]config.Tile{{Title: "bad", Request: config.Request{Provider: "nope"}}})
+		require.Error(t, err)
+		assert.EqualError(t, err, `tile 0 (bad): unknown provider "nope"`)
+	})
 }
@@ -0,0 +1,310 @@
+package providers
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+	"unicode"
+
+	"github.com/Masterminds/sprig/v3"
+	"github.com/containeroo/resolver"
+	"github.com/gi8lino/tiledash/internal/config"
+	"github.com/gi8lino/tiledash/internal/hash"
+	"github.com/gi8lino/tiledash/internal/lru"
+)
+
+// dayWeekRe matches whole-day/week duration components ("7d", "2w") that time.ParseDuration lacks.
+var dayWeekRe = regexp.MustCompile(`(\d+)([dw])`)
+
+// requestFuncMap returns the functions available to templated request fields:
+// sprig (text), "resolve" for env:/file: placeholders, and a date_modify that understands days and weeks.
+func requestFuncMap() template.FuncMap {
+	fm := sprig.TxtFuncMap()
+	fm["resolve"] = resolver.ResolveVariable
+	fm["date_modify"] = dateModify
+	fm["dateModify"] = dateModify
+	return fm
+}
+
+// dateModify shifts date by a Go duration that may also use "d" (24h) and "w" (7d) units,
+// e.g. "-7d" or "1w12h". Unparsable durations return date unchanged, like sprig.
+func dateModify(offset string, date time.Time) time.Time {
+	expanded := dayWeekRe.ReplaceAllStringFunc(offset, func(m string) string {
+		parts := dayWeekRe.FindStringSubmatch(m)
+		n, _ := strconv.Atoi(parts[1])
+		if parts[2] == "w" {
+			n *= 7
+		}
+		return strconv.Itoa(n*24) + "h"
+	})
+	d, err := time.ParseDuration(expanded)
+	if err != nil {
+		return date
+	}
+	return date.Add(d)
+}
+
+// isTemplated reports whether any request field contains template actions.
+func isTemplated(req config.Request) bool {
+	if hasAction(req.Path) || hasAction(req.Body) {
+		return true
+	}
+	for k, v := range req.Query {
+		if hasAction(k) || hasAction(v) {
+			return true
+		}
+	}
+	for k, v := range req.Headers {
+		if hasAction(k) || hasAction(v) {
+			return true
+		}
+	}
+	return anyHasAction(req.BodyJSON)
+}
+
+// hasAction reports whether s contains a template action.
+func hasAction(s string) bool {
+	return strings.Contains(s, "{{")
+}
+
+// anyHasAction reports whether any string inside a decoded YAML/JSON value contains a template action.
+func anyHasAction(v any) bool {
+	switch t := v.(type) {
+	case string:
+		return hasAction(t)
+	case map[string]any:
+		for k, e := range t {
+			if hasAction(k) || anyHasAction(e) {
+				return true
+			}
+		}
+	case []any:
+		for _, e := range t {
+			if anyHasAction(e) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// requestTemplate renders a templated request with the tile's vars (.Vars) and the
+// dashboard parameters of the current request (.Params).
+type requestTemplate struct {
+	req   config.Request
+	vars  map[string]any
+	tmpls map[string]*template.Template // template source -> parsed template
+}
+
+// newRequestTemplate parses every templated string of req once.
+func newRequestTemplate(req config.Request, vars map[string]any) (*requestTemplate, error) {
+	t := &requestTemplate{req: req, vars: vars, tmpls: map[string]*template.Template{}}
+
+	var errs []string
+	parse := func(field, src string) {
+		if !hasAction(src) || t.tmpls[src] != nil {
+			return
+		}
+		tmpl, err := template.New(field).Funcs(requestFuncMap()).Option("missingkey=error").Parse(src)
+		if err != nil {
+			errs = append(errs, err.Error())
+			return
+		}
+		t.tmpls[src] = tmpl
+	}
+
+	parse("path", req.Path)
+	parse("body", req.Body)
+	for k, v := range req.Query {
+		parse("query", k)
+		parse("query."+k, v)
+	}
+	for k, v := range req.Headers {
+		parse("headers", k)
+		parse("headers."+k, v)
+	}
+	walkStrings(req.BodyJSON, func(s string) { parse("bodyJSON", s) })
+
+	if len(errs) > 0 {
+		return nil, fmt.Errorf("request template: %s", strings.Join(errs, "; "))
+	}
+	return t, nil
+}
+
+// render executes the templates with the parameters in ctx and returns the concrete request.
+// Parameters are escaped for the field they are rendered into: the path sees them
+// path-escaped, query keys and values are query-escaped when the URL is built, and
+// headers containing control characters are rejected.
+func (t *requestTemplate) render(ctx context.Context) (config.Request, error) {
+	params := config.ParamsFromContext(ctx)
+	pathParams := make(config.Params, len(params))
+	for k, v := range params {
+		pathParams[k] = url.PathEscape(v)
+	}
+	if params == nil {
+		params = config.Params{}
+	}
+	data := map[string]any{"Vars": t.vars, "Params": params}
+	pathData := map[string]any{"Vars": t.vars, "Params": pathParams}
+
+	var firstErr error
+	execWith := func(src string, data any) string {
+		tmpl := t.tmpls[src]
+		if tmpl == nil || firstErr != nil {
+			return src
+		}
+		var buf bytes.Buffer
+		if err := tmpl.Execute(&buf, data); err != nil {
+			firstErr = err
+			return src
+		}
+		return buf.String()
+	}
+	exec := func(src string) string { return execWith(src, data) }
+	header := func(src string) string {
+		s := exec(src)
+		if firstErr == nil && strings.ContainsFunc(s, unicode.IsControl) {
+			firstErr = fmt.Errorf("header %q contains control characters", s)
+		}
+		return s
+	}
+
+	out := t.req
+	out.Path = execWith(t.req.Path, pathData)
+	out.Body = exec(t.req.Body)
+	if t.req.Query != nil {
+		out.Query = make(map[string]string, len(t.req.Query))
+		for k, v := range t.req.Query {
+			out.Query[exec(k)] = exec(v)
+		}
+	}
+	if t.req.Headers != nil {
+		out.Headers = make(map[string]string, len(t.req.Headers))
+		for k, v := range t.req.Headers {
+			out.Headers[header(k)] = header(v)
+		}
+	}
+	if t.req.BodyJSON != nil {
+		out.BodyJSON, _ = mapStrings(t.req.BodyJSON, exec).(map[string]any)
+	}
+
+	if firstErr != nil {
+		return config.Request{}, fmt.Errorf("render request: %w", firstErr)
+	}
+	return out, nil
+}
+
+// walkStrings calls fn for every string (map keys included) inside v.
+func walkStrings(v any, fn func(string)) {
+	switch t := v.(type) {
+	case string:
+		fn(t)
+	case map[string]any:
+		for k, e := range t {
+			fn(k)
+			walkStrings(e, fn)
+		}
+	case []any:
+		for _, e := range t {
+			walkStrings(e, fn)
+		}
+	}
+}
+
+// mapStrings returns a deep copy of v with every string (map keys included) replaced by fn(s).
+func mapStrings(v any, fn func(string) string) any {
+	switch t := v.(type) {
+	case string:
+		return fn(t)
+	case map[string]any:
+		out := make(map[string]any, len(t))
+		for k, e := range t {
+			out[fn(k)] = mapStrings(e, fn)
+		}
+		return out
+	case []any:
+		out := make([]any, len(t))
+		for i, e := range t {
+			out[i] = mapStrings(e, fn)
+		}
+		return out
+	default:
+		return v
+	}
+}
+
+// TemplatedRunner re-renders a templated request on every Do, so time-dependent values
+// (e.g. `now | date_modify "-7d"`) are evaluated per fetch rather than once at startup.
+// The runner compiled for the most recent rendering of each parameter set is reused while
+// the values don't change; only the maxParamSets most recently used parameter sets are kept.
+type TemplatedRunner struct {
+	prov *HTTPProvider
+	tmpl *requestTemplate
+
+	mu      sync.Mutex                         // serializes the lookup and replacement of a runner
+	runners *lru.Cache[string, renderedRunner] // keyed by encoded dashboard params
+}
+
+// maxParamSets bounds how many parameter sets a templated request keeps a compiled runner for.
+const maxParamSets = 128
+
+// newTemplatedRunner returns a TemplatedRunner rendering tmpl for provider p.
+func newTemplatedRunner(p *HTTPProvider, tmpl *requestTemplate) *TemplatedRunner {
+	return &TemplatedRunner{prov: p, tmpl: tmpl, runners: lru.New[string, renderedRunner](maxParamSets, nil)}
+}
+
+// renderedRunner is the HTTPRunner compiled for one rendering of a templated request.
+type renderedRunner struct {
+	key string // hash of the rendered request
+	run *HTTPRunner
+}
+
+// Do renders the request and executes it.
+func (r *TemplatedRunner) Do(ctx context.Context) (Accumulator, int, int, error) {
+	run, err := r.runner(ctx)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	return run.Do(ctx)
+}
+
+// runner returns the HTTPRunner for the current rendering of the request.
+// Different parameter values render different requests and therefore use separate cache keys.
+func (r *TemplatedRunner) runner(ctx context.Context) (*HTTPRunner, error) {
+	req, err := r.tmpl.render(ctx)
+	if err != nil {
+		return nil, err
+	}
+	key, err := hash.Any(req)
+	if err != nil {
+		return nil, err
+	}
+	params := config.ParamsFromContext(ctx).Encode()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if last, ok := r.runners.Get(params); ok && last.key == key {
+		return last.run, nil
+	}
+	run := r.prov.NewRunner(req)
+	r.runners.Add(params, renderedRunner{key: key, run: run})
+	return run, nil
+}
+
+// PurgeCache drops the cached pages of the current rendering of every parameter set.
+// Pages of earlier renderings (or of parameter sets dropped from the runner cache) are
+// never requested again and simply expire.
+func (r *TemplatedRunner) PurgeCache() int {
+	n := 0
+	for _, rr := range r.runners.Values() {
+		n += rr.run.PurgeCache()
+	}
+	return n
+}
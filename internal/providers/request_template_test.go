@@ -0,0 +1,260 @@
+package providers
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gi8lino/tiledash/internal/config"
+	"github.com/gi8lino/tiledash/internal/fetcher"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDateModify(t *testing.T) {
+	t.Parallel()
+
+	base := time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC)
+	tests := map[string]time.Time{
+		"-7d":   base.AddDate(0, 0, -7),
+		"1w":    base.AddDate(0, 0, 7),
+		"1d12h": base.Add(36 * time.Hour),
+		"-90m":  base.Add(-90 * time.Minute),
+		"bogus": base,
+	}
+	for offset, want := range tests {
+		assert.Equal(t, want, dateModify(offset, base), offset)
+	}
+}
+
+func TestIsTemplated(t *testing.T) {
+	t.Parallel()
+
+	assert.False(t, isTemplated(config.Request{Path: "/x", Query: map[string]string{"a": "b"}}))
+	assert.True(t, isTemplated(config.Request{Path: "/{{ .Vars.p }}"}))
+	assert.True(t, isTemplated(config.Request{Headers: map[string]string{"X": "{{ .Vars.h }}"}}))
+	assert.True(t, isTemplated(config.Request{BodyJSON: map[string]any{"a": []any{1, "{{ .Vars.x }}"}}}))
+}
+
+func TestRequestTemplate(t *testing.T) {
+	t.Parallel()
+
+	t.Run("renders every templated field", func(t *testing.T) {
+		t.Parallel()
+
+		req := config.Request{
+			Provider: "jira",
+			Path:     "/rest/api/2/project/{{ .Vars.project }}",
+			Query:    map[string]string{"jql": "filter={{ .Vars.filter }}", "static": "x"},
+			Headers:  map[string]string{"X-Team": "{{ .Vars.project | lower }}"},
+			Body:     `{"p":"{{ .Vars.project }}"}`,
+			BodyJSON: map[string]any{"nested": map[string]any{"list": []any{"{{ .Vars.filter }}", 3}}},
+		}
+		tmpl, err := newRequestTemplate(req, map[string]any{"project": "ABC", "filter": 42})
+		require.NoError(t, err)
+
+		got, err := tmpl.render(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, "/rest/api/2/project/ABC", got.Path)
+		assert.Equal(t, map[string]string{"jql": "filter=42", "static": "x"}, got.Query)
+		assert.Equal(t, map[string]string{"X-Team": "abc"}, got.Headers)
+		assert.Equal(t, `{"p":"ABC"}`, got.Body)
+		assert.Equal(t, map[string]any{"nested": map[string]any{"list": []any{"42", 3}}}, got.BodyJSON)
+
+		// The source request is left untouched.
+		assert.Equal(t, "/rest/api/2/project/{{ .Vars.project }}", req.Path)
+	})
+
+	t.Run("exposes dashboard params", func(t *testing.T) {
+		t.Parallel()
+
+		tmpl, err := newRequestTemplate(config.Request{
+			Path:  "/teams/{{ .Params.team }}",
+			Query: map[string]string{"project": "{{ .Vars.project }}"},
+		}, map[string]any{"project": "ABC"})
+		require.NoError(t, err)
+
+		ctx := config.WithParams(context.Background(), config.Params{"team": "billing"})
+		got, err := tmpl.render(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, "/teams/billing", got.Path)
+		assert.Equal(t, map[string]string{"project": "ABC"}, got.Query)
+
+		// Without params in ctx, referencing one is a missing key.
+		_, err = tmpl.render(context.Background())
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `map has no entry for key "team"`)
+	})
+
+	t.Run("escapes params by context", func(t *testing.T) {
+		t.Parallel()
+
+		tmpl, err := newRequestTemplate(config.Request{
+			Path:    "/teams/{{ .Params.team }}/issues",
+			Query:   map[string]string{"jql": "team = {{ .Params.team }}"},
+			Headers: map[string]string{"X-Team": "{{ .Params.team }}"},
+		}, nil)
+		require.NoError(t, err)
+
+		ctx := config.WithParams(context.Background(), config.Params{"team": "../admin?x=1&y=2#f"})
+		got, err := tmpl.render(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, "/teams/..%2Fadmin%3Fx=1&y=2%23f/issues", got.Path)
+		assert.Equal(t, "../admin?x=1&y=2#f", got.Headers["X-Team"])
+
+		base, _ := url.Parse("https://jira.example.com/api/")
+		spec := fetcher.RequestSpec{URL: got.Path, Query: got.Query}
+		u, _, err := spec.Normalize(base)
+		require.NoError(t, err)
+		assert.Equal(t, "/teams/..%2Fadmin%3Fx=1&y=2%23f/issues", u.EscapedPath())
+		assert.Equal(t, url.Values{"jql": {"team = ../admin?x=1&y=2#f"}}, u.Query())
+		assert.Empty(t, u.Fragment)
+	})
+
+	t.Run("rejects control characters in headers", func(t *testing.T) {
+		t.Parallel()
+
+		tmpl, err := newRequestTemplate(config.Request{
+			Headers: map[string]string{"X-Team": "{{ .Params.team }}"},
+		}, nil)
+		require.NoError(t, err)
+
+		ctx := config.WithParams(context.Background(), config.Params{"team": "a\r\nX-Evil: 1"})
+		_, err = tmpl.render(ctx)
+		require.Error(t, err)
+		assert.EqualError(t, err, `render request: header "a\r\nX-Evil: 1" contains control characters`)
+	})
+
+	t.Run("reports parse and execution errors", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := newRequestTemplate(config.Request{Path: "/{{ .Vars.x "}, nil)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "request template: template: path:")
+
+		tmpl, err := newRequestTemplate(config.Request{Path: "/{{ .Vars.missing }}"}, map[string]any{})
+		require.NoError(t, err)
+		_, err = tmpl.render(context.Background())
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `render request: template: path:1:9: executing "path" at <.Vars.missing>: map has no entry for key "missing"`)
+	})
+}
+
+func TestRequestTemplate_Resolve(t *testing.T) {
+	// t.Parallel() impossible due to t.Setenv
+	t.Setenv("TILEDASH_TEST_PROJECT", "XYZ")
+
+	tmpl, err := newRequestTemplate(config.Request{Path: `/p/{{ resolve "env:TILEDASH_TEST_PROJECT" }}`}, nil)
+	require.NoError(t, err)
+	got, err := tmpl.render(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "/p/XYZ", got.Path)
+}
+
+func TestTemplatedRunner(t *testing.T) {
+	t.Parallel()
+
+	var (
+		mu   sync.Mutex
+		seen []string
+	)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		seen = append(seen, r.URL.RequestURI())
+		mu.Unlock()
+		_, _ = io.WriteString(w, `{"ok":true}`)
+	}))
+	t.Cleanup(ts.Close)
+
+	reg, err := BuildRegistry(map[string]config.Provider{"p": {BaseURL: ts.URL}}, nil)
+	require.NoError(t, err)
+
+	run, err := reg.compile(config.Request{
+		Provider: "p",
+		Path:     "/{{ .Vars.project }}",
+		Query:    map[string]string{"since": `{{ now | date "15:04:05.000000000" }}`},
+		TTL:      time.Minute,
+	}, map[string]any{"project": "abc"})
+	require.NoError(t, err)
+
+	tr, ok := run.(*TemplatedRunner)
+	require.True(t, ok, "templated requests compile to a TemplatedRunner")
+
+	_, _, status, err := tr.Do(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, status)
+	first, ok := tr.runners.Get("")
+	require.True(t, ok)
+
+	time.Sleep(time.Millisecond)
+	_, _, _, err = tr.Do(context.Background())
+	require.NoError(t, err)
+
+	// The time-dependent query is rendered per fetch, so each fetch hits upstream.
+	mu.Lock()
+	defer mu.Unlock()
+	require.Len(t, seen, 2)
+	assert.Contains(t, seen[0], "/abc?since=")
+	assert.NotEqual(t, seen[0], seen[1])
+	last, ok := tr.runners.Get("")
+	require.True(t, ok)
+	assert.NotSame(t, first.run, last.run)
+	assert.Equal(t, 1, tr.PurgeCache())
+}
+
+func TestTemplatedRunner_Params(t *testing.T) {
+	t.Parallel()
+
+	var (
+		mu   sync.Mutex
+		seen []string
+	)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		seen = append(seen, r.URL.RequestURI())
+		mu.Unlock()
+		_, _ = io.WriteString(w, `{"ok":true}`)
+	}))
+	t.Cleanup(ts.Close)
+
+	reg, err := BuildRegistry(map[string]config.Provider{"p": {BaseURL: ts.URL}}, nil)
+	require.NoError(t, err)
+
+	run, err := reg.compile(config.Request{
+		Provider: "p",
+		Path:     "/{{ .Params.team }}",
+		TTL:      time.Minute,
+	}, nil)
+	require.NoError(t, err)
+
+	payments := config.WithParams(context.Background(), config.Params{"team": "payments"})
+	billing := config.WithParams(context.Background(), config.Params{"team": "billing"})
+	for _, ctx := range []context.Context{payments, billing, payments, billing} {
+		_, _, status, err := run.Do(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, status)
+	}
+
+	// Each parameter set is fetched once and then served from the provider cache.
+	mu.Lock()
+	assert.Equal(t, []string{"/payments", "/billing"}, seen)
+	mu.Unlock()
+
+	tr := run.(*TemplatedRunner)
+	assert.Equal(t, 2, tr.PurgeCache())
+
+	// Free-form values keep only the most recently used parameter sets.
+	for i := range maxParamSets + 1 {
+		_, _, _, err := run.Do(config.WithParams(context.Background(), config.Params{"team": strconv.Itoa(i)}))
+		require.NoError(t, err)
+	}
+	assert.Equal(t, maxParamSets, tr.runners.Len())
+	_, ok := tr.runners.Get(config.Params{"team": "0"}.Encode())
+	assert.False(t, ok)
+}
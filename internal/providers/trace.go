@@ -0,0 +1,116 @@
+package providers
+
+import (
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// redacted replaces the value of headers and query parameters that carry credentials in traces.
+const redacted = "[REDACTED]"
+
+// sensitiveHeaders are redacted in traces regardless of their value.
+var sensitiveHeaders = map[string]bool{
+	"Authorization":       true,
+	"Proxy-Authorization": true,
+	"Cookie":              true,
+	"Set-Cookie":          true,
+}
+
+// Trace makes every provider log each upstream request and response: URL, headers
+// (credentials in both redacted), status and timing. It is meant for debugging from the CLI.
+func (r Registry) Trace(logger *slog.Logger) {
+	for name, p := range r {
+		next := p.Client.Transport
+		if next == nil {
+			next = http.DefaultTransport
+		}
+		p.Client.Transport = &tracingTransport{next: next, logger: logger.With("provider", name)}
+	}
+}
+
+// tracingTransport logs requests and responses passing through next.
+type tracingTransport struct {
+	next   http.RoundTripper
+	logger *slog.Logger
+}
+
+// RoundTrip logs the request, forwards it, and logs the response or error with the elapsed time.
+func (t *tracingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	u := redactURL(req.URL)
+	t.logger.Info("request",
+		"method", req.Method,
+		"url", u,
+		"headers", redactHeaders(req.Header),
+	)
+
+	start := time.Now()
+	res, err := t.next.RoundTrip(req)
+	elapsed := time.Since(start)
+	if err != nil {
+		t.logger.Error("request failed", "url", u, "duration", elapsed, "error", err)
+		return nil, err
+	}
+
+	t.logger.Info("response",
+		"url", u,
+		"status", res.StatusCode,
+		"duration", elapsed,
+		"contentLength", res.ContentLength,
+		"headers", redactHeaders(res.Header),
+	)
+	return res, nil
+}
+
+// redactHeaders returns the headers as name -> value with credentials replaced.
+func redactHeaders(h http.Header) map[string]string {
+	out := make(map[string]string, len(h))
+	for name, values := range h {
+		if isSensitiveHeader(name) {
+			out[name] = redacted
+			continue
+		}
+		out[name] = strings.Join(values, ", ")
+	}
+	return out
+}
+
+// redactURL returns u as a string with the password of the user info and the values of
+// query parameters that likely carry a credential replaced.
+func redactURL(u *url.URL) string {
+	if u.RawQuery == "" {
+		return u.Redacted()
+	}
+	parts := strings.Split(u.RawQuery, "&")
+	for i, part := range parts {
+		rawName, _, _ := strings.Cut(part, "=")
+		name, err := url.QueryUnescape(rawName)
+		if err != nil {
+			name = rawName
+		}
+		if isSensitiveName(name) {
+			parts[i] = rawName + "=" + redacted
+		}
+	}
+	c := *u
+	c.RawQuery = strings.Join(parts, "&")
+	return c.Redacted()
+}
+
+// isSensitiveHeader reports whether a header likely carries a credential.
+func isSensitiveHeader(name string) bool {
+	return sensitiveHeaders[http.CanonicalHeaderKey(name)] || isSensitiveName(name)
+}
+
+// isSensitiveName reports whether a header or query parameter name suggests a credential.
+func isSensitiveName(name string) bool {
+	lower := strings.ToLower(name)
+	for _, s := range []string{"token", "secret", "api-key", "api_key", "apikey", "password", "signature"} {
+		if strings.Contains(lower, s) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,160 @@
+package render
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"html/template"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gi8lino/tiledash/internal/config"
+	"github.com/gi8lino/tiledash/internal/templates"
+)
+
+// exportWorkers bounds how many tiles are rendered concurrently for a snapshot.
+const exportWorkers = 4
+
+// Exporter renders whole dashboards into self-contained HTML snapshots: tiles are
+// rendered server-side, the page CSS and Bootstrap are inlined, and no scripts are included.
+type Exporter struct {
+	tmpl      *template.Template
+	bootstrap template.CSS
+	version   string
+	now       func() time.Time
+}
+
+// exportCell is a rendered tile of a snapshot.
+type exportCell struct {
+	ID int
+	config.Tile
+	HTML   template.HTML
+	Hidden bool // the tile template asked to be hidden (data-td-hidden)
+}
+
+// exportPage is a page of a snapshot; pages are stacked since snapshots have no tabs.
+type exportPage struct {
+	Title string
+	Grid  *config.GridConfig
+	Cells []exportCell
+}
+
+// NewExporter parses the export templates and loads the Bootstrap CSS to inline from webFS.
+func NewExporter(webFS fs.FS, version string) *Exporter {
+	// Always embedded in the binary; minimal test filesystems may leave it out.
+	css, _ := fs.ReadFile(webFS, "web/static/css/bootstrap.min.css")
+	return &Exporter{
+		tmpl:      templates.ParseExportTemplates(webFS, templates.TemplateFuncMap()),
+		bootstrap: template.CSS(css),
+		version:   version,
+		now:       time.Now,
+	}
+}
+
+// Export renders every tile of the renderer's dashboard and writes the snapshot to w.
+// Dashboard parameters are read from ctx (config.WithParams). Tiles that fail to render
+// show the tile error layout instead of failing the whole snapshot.
+func (e *Exporter) Export(ctx context.Context, w io.Writer, renderer *TileRenderer) error {
+	cfg := renderer.cfg
+	cells := e.renderCells(ctx, renderer)
+
+	var pages []exportPage
+	for _, p := range cfg.Pages {
+		page := exportPage{Title: p.Title, Grid: p.Grid}
+		for _, i := range cfg.PageTiles(p.Name) {
+			page.Cells = append(page.Cells, cells[i])
+		}
+		pages = append(pages, page)
+	}
+
+	customization := cfg.Customization
+	if customization == nil {
+		customization = &config.Customization{}
+	}
+
+	var buf bytes.Buffer
+	if err := e.tmpl.ExecuteTemplate(&buf, "export", map[string]any{
+		"Title":         cfg.Title,
+		"Version":       e.version,
+		"Generated":     e.now(),
+		"Params":        config.ParamsFromContext(ctx),
+		"Bootstrap":     e.bootstrap,
+		"Grid":          cfg.Grid,
+		"Customization": customization,
+		"Cells":         cells,
+		"Pages":         pages,
+	}); err != nil {
+		return fmt.Errorf("render export: %w", err)
+	}
+	_, err := buf.WriteTo(w)
+	return err
+}
+
+// renderCells renders all tiles, a few at a time.
+func (e *Exporter) renderCells(ctx context.Context, renderer *TileRenderer) []exportCell {
+	tiles := renderer.cfg.Tiles
+	cells := make([]exportCell, len(tiles))
+
+	sem := make(chan struct{}, exportWorkers)
+	var wg sync.WaitGroup
+	for i := range tiles {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			cell := exportCell{ID: i, Tile: tiles[i]}
+			res, _, renderErr := renderer.RenderTile(ctx, i)
+			if renderErr != nil {
+				cell.HTML = e.renderError(renderErr)
+			} else {
+				cell.HTML = template.HTML(res.HTML)
+				cell.Hidden = strings.Contains(res.HTML, "data-td-hidden")
+			}
+			cells[i] = cell
+		}()
+	}
+	wg.Wait()
+	return cells
+}
+
+// renderError renders a tile error with the tile error layout.
+func (e *Exporter) renderError(renderErr *templates.RenderError) template.HTML {
+	var buf bytes.Buffer
+	if err := e.tmpl.ExecuteTemplate(&buf, "tile_error", renderErr); err != nil {
+		return template.HTML(template.HTMLEscapeString(renderErr.Error()))
+	}
+	return template.HTML(buf.String())
+}
+
+// ExportFile writes a snapshot into dir under a timestamped file name and returns its path.
+func (e *Exporter) ExportFile(ctx context.Context, dir string, renderer *TileRenderer) (string, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", fmt.Errorf("export: %w", err)
+	}
+	path := filepath.Join(dir, ExportFilename(renderer.cfg.Name, e.now()))
+
+	var buf bytes.Buffer
+	if err := e.Export(ctx, &buf, renderer); err != nil {
+		return "", err
+	}
+	if err := os.WriteFile(path, buf.Bytes(), 0o644); err != nil {
+		return "", fmt.Errorf("export: %w", err)
+	}
+	return path, nil
+}
+
+// ExportFilename returns the snapshot file name for a dashboard at t,
+// e.g. "tiledash-20250102-150405.html" or "tiledash-ops-20250102-150405.html".
+func ExportFilename(dashboard string, t time.Time) string {
+	if dashboard == "" {
+		return "tiledash-" + t.Format("20060102-150405") + ".html"
+	}
+	return "tiledash-" + dashboard + "-" + t.Format("20060102-150405") + ".html"
+}
@@ -0,0 +1,154 @@
+package render
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/gi8lino/tiledash/internal/config"
+	"github.com/gi8lino/tiledash/internal/providers"
+	"github.com/gi8lino/tiledash/internal/templates"
+)
+
+// newTestExporter returns an exporter over the real web templates with a fixed clock.
+func newTestExporter(t *testing.T) *Exporter {
+	t.Helper()
+	webFS := fstest.MapFS{"web/static/css/bootstrap.min.css": &fstest.MapFile{Data: []byte(`.bootstrap-inline{}`)}}
+	for _, name := range []string{"export.gohtml", "css/page.gohtml", "errors/tile.gohtml"} {
+		data, err := os.ReadFile(filepath.Join("../../web/templates", name))
+		if err != nil {
+			t.Fatalf("read %s: %v", name, err)
+		}
+		webFS["web/templates/"+name] = &fstest.MapFile{Data: data}
+	}
+	e := NewExporter(webFS, "v1.2.3")
+	e.now = func() time.Time { return time.Date(2025, 1, 2, 15, 4, 5, 0, time.UTC) }
+	return e
+}
+
+func TestExport(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(tmpDir, "tile.gohtml"), []byte(
+		`{{define "tile.gohtml"}}<div>{{.Title}}={{index .Data "v"}} team={{.Params.team}}</div>{{end}}`+
+			`{{define "hidden.gohtml"}}<div data-td-hidden></div>{{end}}`,
+	), 0o644); err != nil {
+		t.Fatalf("write template: %v", err)
+	}
+	tmpl, err := templates.ParseCellTemplates(tmpDir, templates.TemplateFuncMap())
+	if err != nil {
+		t.Fatalf("parse template: %v", err)
+	}
+
+	cfg := config.DashboardConfig{
+		Title:         "Weekly",
+		Grid:          &config.GridConfig{Columns: 2, Rows: 1},
+		Customization: &config.Customization{},
+		Tiles: []config.Tile{
+			{Title: "Open", Template: "tile.gohtml", Position: config.Position{Row: 1, Col: 1}},
+			{Title: "Quiet", Template: "hidden.gohtml", Position: config.Position{Row: 1, Col: 2}},
+			{Title: "Broken", Template: "tile.gohtml", Position: config.Position{Row: 2, Col: 1}},
+		},
+	}
+	runners := []providers.Runner{
+		countingRunner{count: new(int32), acc: providers.Accumulator{"v": 42}, status: http.StatusOK},
+		countingRunner{count: new(int32), acc: providers.Accumulator{}, status: http.StatusOK},
+		countingRunner{count: new(int32), status: http.StatusBadGateway, err: errors.New("upstream down")},
+	}
+	renderer := NewTileRenderer(cfg, runners, tmpl, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	ctx := config.WithParams(context.Background(), config.Params{"team": "payments"})
+	var buf bytes.Buffer
+	if err := newTestExporter(t).Export(ctx, &buf, renderer); err != nil {
+		t.Fatalf("export: %v", err)
+	}
+	out := buf.String()
+
+	for _, want := range []string{
+		"<title>Weekly – 2025-01-02 15:04 UTC</title>",
+		".bootstrap-inline{}",
+		"<div>Open=42 team=payments</div>",
+		`class="card td-hidden"`,
+		"upstream down",
+		"team=payments",
+		"Version: v1.2.3",
+	} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("expected export to contain %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "<script") {
+		t.Fatalf("expected no scripts in export:\n%s", out)
+	}
+}
+
+func TestExportPages(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(tmpDir, "tile.gohtml"), []byte(`{{define "tile.gohtml"}}<p>{{.Title}}</p>{{end}}`), 0o644); err != nil {
+		t.Fatalf("write template: %v", err)
+	}
+	tmpl, err := templates.ParseCellTemplates(tmpDir, templates.TemplateFuncMap())
+	if err != nil {
+		t.Fatalf("parse template: %v", err)
+	}
+
+	cfg := config.DashboardConfig{
+		Name:  "ops",
+		Title: "Ops",
+		Pages: []config.Page{{Name: "a", Title: "Alpha"}, {Name: "b", Title: "Beta"}},
+		Tiles: []config.Tile{
+			{Title: "first", Template: "tile.gohtml", Page: "a"},
+			{Title: "second", Template: "tile.gohtml", Page: "b"},
+		},
+	}
+	runners := []providers.Runner{
+		countingRunner{count: new(int32), acc: providers.Accumulator{}, status: http.StatusOK},
+		countingRunner{count: new(int32), acc: providers.Accumulator{}, status: http.StatusOK},
+	}
+	renderer := NewTileRenderer(cfg, runners, tmpl, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	dir := filepath.Join(t.TempDir(), "snapshots")
+	path, err := newTestExporter(t).ExportFile(context.Background(), dir, renderer)
+	if err != nil {
+		t.Fatalf("export file: %v", err)
+	}
+	if want := filepath.Join(dir, "tiledash-ops-20250102-150405.html"); path != want {
+		t.Fatalf("expected path %q, got %q", want, path)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read export: %v", err)
+	}
+	out := string(data)
+	alpha := strings.Index(out, "Alpha")
+	first := strings.Index(out, "<p>first</p>")
+	beta := strings.Index(out, "Beta")
+	second := strings.Index(out, "<p>second</p>")
+	if alpha < 0 || first < alpha || beta < first || second < beta {
+		t.Fatalf("expected pages stacked in order:\n%s", out)
+	}
+}
+
+func TestExportFilename(t *testing.T) {
+	t.Parallel()
+
+	at := time.Date(2025, 1, 2, 15, 4, 5, 0, time.UTC)
+	if got := ExportFilename("", at); got != "tiledash-20250102-150405.html" {
+		t.Fatalf("unexpected filename %q", got)
+	}
+	if got := ExportFilename("ops", at); got != "tiledash-ops-20250102-150405.html" {
+		t.Fatalf("unexpected filename %q", got)
+	}
+}
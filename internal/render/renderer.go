@@ -5,31 +5,67 @@ import (
 	"html/template"
 	"log/slog"
 	"net/http"
+	"strconv"
 	"sync"
 	"time"
 
 	"github.com/gi8lino/tiledash/internal/config"
 	"github.com/gi8lino/tiledash/internal/hash"
+	"github.com/gi8lino/tiledash/internal/lru"
 	"github.com/gi8lino/tiledash/internal/providers"
 	"github.com/gi8lino/tiledash/internal/templates"
+	"github.com/gi8lino/tiledash/internal/transform"
+
+	"golang.org/x/sync/singleflight"
 )
 
+// renderTimeout bounds a shared render; it no longer follows the context of the caller that started it.
+const renderTimeout = 2 * time.Minute
+
+// maxRendersPerTile bounds the cached renders per tile; each parameter set renders separately.
+const maxRendersPerTile = 64
+
 // Result captures the rendered HTML and hash for a tile.
 type Result struct {
 	HTML string
 	Hash string
 }
 
-// TileRenderer renders tiles via runners, caches the rendered output per tile,
-// and exposes both the HTML and its hash (computed from the rendered HTML).
+// TileRenderer renders tiles via runners, caches the rendered output per tile and
+// dashboard parameter values, and exposes both the HTML and its hash (computed from
+// the rendered HTML). Parameters are read from the context (config.WithParams).
 type TileRenderer struct {
 	cfg      config.DashboardConfig
 	runners  []providers.Runner
 	tileTmpl *template.Template
 	logger   *slog.Logger
 
-	mu    sync.RWMutex
-	cache []cachedTile
+	cache      *lru.Cache[cacheKey, cachedTile] // least recently used renders are dropped first
+	transforms []tileTransform                  // compiled request transform per tile
+
+	mu  sync.Mutex // orders cache writes against invalidations
+	gen uint64     // bumped by every invalidation; renders started before it are not cached
+
+	inflight singleflight.Group // dedupes concurrent renders of the same tile
+}
+
+// renderOutcome carries a render result through singleflight.
+type renderOutcome struct {
+	result Result
+	status int
+	err    *templates.RenderError
+}
+
+// cacheKey identifies a render of a tile for a set of parameter values.
+type cacheKey struct {
+	idx    int
+	params string // encoded config.Params
+}
+
+// tileTransform is a tile's compiled request transform, or the error compiling it.
+type tileTransform struct {
+	fn  transform.Func
+	err error
 }
 
 type cachedTile struct {
@@ -38,13 +74,20 @@ type cachedTile struct {
 }
 
 // NewTileRenderer constructs a renderer over the provided runners and template set.
+// Request transforms are compiled once here; Validate already rejects invalid ones.
 func NewTileRenderer(cfg config.DashboardConfig, runners []providers.Runner, tmpl *template.Template, logger *slog.Logger) *TileRenderer {
+	transforms := make([]tileTransform, len(cfg.Tiles))
+	for i, tile := range cfg.Tiles {
+		fn, err := transform.Compile(tile.Request.Transform.JQ, tile.Request.Transform.JMESPath)
+		transforms[i] = tileTransform{fn: fn, err: err}
+	}
 	return &TileRenderer{
-		cfg:      cfg,
-		runners:  runners,
-		tileTmpl: tmpl,
-		logger:   logger,
-		cache:    make([]cachedTile, len(runners)),
+		cfg:        cfg,
+		runners:    runners,
+		tileTmpl:   tmpl,
+		logger:     logger,
+		cache:      lru.New[cacheKey, cachedTile](max(len(runners), 1)*maxRendersPerTile, nil),
+		transforms: transforms,
 	}
 }
 
@@ -59,18 +102,103 @@ func (t *TileRenderer) RenderTile(ctx context.Context, idx int) (Result, int, *t
 		return Result{}, http.StatusNotFound, templates.NewRenderError("render", "Invalid tile id", "index out of range")
 	}
 
-	ttl := t.cfg.Tiles[idx].Request.TTL
-
 	// Fast path: return cached render if still fresh.
-	if ttl > 0 {
-		now := time.Now()
-		t.mu.RLock()
-		entry := t.cache[idx]
-		t.mu.RUnlock()
-		if !entry.expires.IsZero() && now.Before(entry.expires) && entry.rendered.Hash != "" {
-			return entry.rendered, http.StatusOK, nil
+	key := cacheKey{idx: idx, params: config.ParamsFromContext(ctx).Encode()}
+	if result, ok := t.cached(key); ok {
+		return result, http.StatusOK, nil
+	}
+
+	// Concurrent callers for the same tile (browsers, hash polls, prewarm) share one render.
+	// It runs detached from the caller that started it, so one cancelled request doesn't fail
+	// the others; each caller still stops waiting once its own context is done.
+	ch := t.inflight.DoChan(strconv.Itoa(idx)+"?"+key.params, func() (any, error) {
+		renderCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), renderTimeout)
+		defer cancel()
+		result, status, err := t.render(renderCtx, key)
+		return renderOutcome{result: result, status: status, err: err}, nil
+	})
+	select {
+	case res := <-ch:
+		out := res.Val.(renderOutcome)
+		return out.result, out.status, out.err
+	case <-ctx.Done():
+		return Result{}, http.StatusGatewayTimeout, templates.NewRenderError("render", "render abandoned", ctx.Err().Error())
+	}
+}
+
+// CachedHash returns the hash of a tile's cached render for the parameters in ctx without rendering it.
+func (t *TileRenderer) CachedHash(ctx context.Context, idx int) (string, bool) {
+	if idx < 0 || idx >= len(t.runners) {
+		return "", false
+	}
+	result, ok := t.cached(cacheKey{idx: idx, params: config.ParamsFromContext(ctx).Encode()})
+	return result.Hash, ok
+}
+
+// Prewarm renders all cacheable tiles (with default parameters) concurrently with at most
+// workers in flight and gives up after timeout. It returns how many tiles were rendered successfully.
+func (t *TileRenderer) Prewarm(ctx context.Context, workers int, timeout time.Duration) int {
+	if workers <= 0 {
+		return 0
+	}
+	ctx = config.WithParams(ctx, t.cfg.DefaultParams())
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	var (
+		wg  sync.WaitGroup
+		mu  sync.Mutex
+		ok  int
+		sem = make(chan struct{}, workers)
+	)
+	for idx := range t.runners {
+		if t.cfg.Tiles[idx].Request.TTL <= 0 {
+			continue // nothing would be cached
 		}
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			wg.Wait()
+			return ok
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			if _, _, err := t.RenderTile(ctx, idx); err == nil {
+				mu.Lock()
+				ok++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	return ok
+}
+
+// cached returns the tile's cached render if it is still fresh.
+func (t *TileRenderer) cached(key cacheKey) (Result, bool) {
+	if t.cfg.Tiles[key.idx].Request.TTL <= 0 {
+		return Result{}, false
+	}
+	entry, _ := t.cache.Get(key)
+	if entry.expires.IsZero() || !time.Now().Before(entry.expires) || entry.rendered.Hash == "" {
+		return Result{}, false
 	}
+	return entry.rendered, true
+}
+
+// render fetches, renders, and hashes a tile, caching the result when the tile has a TTL.
+func (t *TileRenderer) render(ctx context.Context, key cacheKey) (Result, int, *templates.RenderError) {
+	idx := key.idx
+	ttl := t.cfg.Tiles[idx].Request.TTL
+
+	t.mu.Lock()
+	gen := t.gen
+	t.mu.Unlock()
 
 	acc, pages, status, err := t.runners[idx].Do(ctx)
 	if err != nil {
@@ -81,7 +209,12 @@ func (t *TileRenderer) RenderTile(ctx context.Context, idx int) (Result, int, *t
 		return Result{}, status, templates.NewRenderError("upstream", "request failed", err.Error())
 	}
 
-	html, renderErr := templates.RenderCell(ctx, idx, t.cfg, t.tileTmpl, acc)
+	tr := t.transforms[idx]
+	if tr.err != nil {
+		return Result{}, http.StatusInternalServerError, templates.NewRenderError("transform", "Invalid transform", tr.err.Error())
+	}
+
+	html, renderErr := templates.RenderCell(ctx, idx, t.cfg, t.tileTmpl, acc, tr.fn)
 	if renderErr != nil {
 		t.logger.Error("render tile error", "id", idx, "error", renderErr.Error())
 		return Result{}, http.StatusInternalServerError, renderErr
@@ -100,12 +233,32 @@ func (t *TileRenderer) RenderTile(ctx context.Context, idx int) (Result, int, *t
 
 	if ttl > 0 {
 		t.mu.Lock()
-		t.cache[idx] = cachedTile{
-			rendered: result,
-			expires:  time.Now().Add(ttl),
+		// A purge while this render ran may have dropped the data it was built from.
+		if t.gen == gen {
+			t.cache.Add(key, cachedTile{
+				rendered: result,
+				expires:  time.Now().Add(ttl),
+			})
 		}
 		t.mu.Unlock()
 	}
 
 	return result, http.StatusOK, nil
 }
+
+// Invalidate drops the cached renders of a tile (for all parameter values) and reports
+// whether an entry was present.
+func (t *TileRenderer) Invalidate(idx int) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.gen++
+	return t.cache.RemoveFunc(func(key cacheKey, _ cachedTile) bool { return key.idx == idx }) > 0
+}
+
+// InvalidateAll drops every cached render and returns how many entries were present.
+func (t *TileRenderer) InvalidateAll() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.gen++
+	return len(t.cache.Clear())
+}
@@ -2,10 +2,14 @@ package render
 
 import (
 	"context"
+	"html/template"
+	"io"
 	"log/slog"
 	"net/http"
 	"os"
 	"path/filepath"
+	"strconv"
+	"sync"
 	"sync/atomic"
 	"testing"
 	"time"
@@ -120,6 +124,105 @@ func TestRenderTileCachesWithinTTL(t *testing.T) {
 	}
 }
 
+func TestRenderTileCachesPerParams(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(tmpDir, "tile.gohtml"), []byte(`{{define "tile.gohtml"}}team={{.Params.team}}{{end}}`), 0o644); err != nil {
+		t.Fatalf("write template: %v", err)
+	}
+	tmpl, err := templates.ParseCellTemplates(tmpDir, templates.TemplateFuncMap())
+	if err != nil {
+		t.Fatalf("parse template: %v", err)
+	}
+
+	cfg := config.DashboardConfig{
+		Params: []config.Param{{Name: "team", Default: "payments"}},
+		Tiles: []config.Tile{{
+			Title:    "Cached",
+			Template: "tile.gohtml",
+			Request:  config.Request{TTL: time.Hour},
+		}},
+	}
+	count := int32(0)
+	runners := []providers.Runner{
+		countingRunner{count: &count, acc: providers.Accumulator{}, status: http.StatusOK},
+	}
+	renderer := NewTileRenderer(cfg, runners, tmpl, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	payments := config.WithParams(context.Background(), config.Params{"team": "payments"})
+	billing := config.WithParams(context.Background(), config.Params{"team": "billing"})
+
+	for _, ctx := range []context.Context{payments, billing, payments, billing} {
+		if _, _, err := renderer.RenderTile(ctx, 0); err != nil {
+			t.Fatalf("render error: %v", err)
+		}
+	}
+	if got := atomic.LoadInt32(&count); got != 2 {
+		t.Fatalf("expected one runner call per parameter set, got %d", got)
+	}
+
+	res, _, _ := renderer.RenderTile(billing, 0)
+	if res.HTML != "team=billing" {
+		t.Fatalf("unexpected HTML: %q", res.HTML)
+	}
+
+	// Prewarm uses the default parameters, so it finds the payments render cached.
+	if n := renderer.Prewarm(context.Background(), 1, time.Second); n != 1 {
+		t.Fatalf("expected 1 prewarmed tile, got %d", n)
+	}
+	if got := atomic.LoadInt32(&count); got != 2 {
+		t.Fatalf("expected prewarm to hit the cache, got %d calls", got)
+	}
+	if _, ok := renderer.CachedHash(payments, 0); !ok {
+		t.Fatal("expected cached hash for payments")
+	}
+
+	if !renderer.Invalidate(0) {
+		t.Fatal("expected invalidate to report cached entries")
+	}
+	if _, ok := renderer.CachedHash(billing, 0); ok {
+		t.Fatal("expected invalidate to drop every parameter set")
+	}
+}
+
+func TestRenderTileBoundsCachedParams(t *testing.T) {
+	t.Parallel()
+
+	cfg := config.DashboardConfig{
+		Tiles: []config.Tile{{
+			Title:    "Cached",
+			Template: "tile.gohtml",
+			Request:  config.Request{TTL: time.Hour},
+		}},
+	}
+	tmpl := template.Must(template.New("tile.gohtml").Parse(`{{define "tile.gohtml"}}ok{{end}}`))
+	count := int32(0)
+	runners := []providers.Runner{
+		countingRunner{count: &count, acc: providers.Accumulator{}, status: http.StatusOK},
+	}
+	renderer := NewTileRenderer(cfg, runners, tmpl, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	ctxFor := func(i int) context.Context {
+		return config.WithParams(context.Background(), config.Params{"q": strconv.Itoa(i)})
+	}
+	for i := range maxRendersPerTile + 1 {
+		if _, _, err := renderer.RenderTile(ctxFor(i), 0); err != nil {
+			t.Fatalf("render error: %v", err)
+		}
+	}
+
+	if n := renderer.cache.Len(); n != maxRendersPerTile {
+		t.Fatalf("expected %d cached renders, got %d", maxRendersPerTile, n)
+	}
+	if _, ok := renderer.CachedHash(ctxFor(0), 0); ok {
+		t.Fatal("expected the least recently used render to be dropped")
+	}
+	if _, ok := renderer.CachedHash(ctxFor(maxRendersPerTile), 0); !ok {
+		t.Fatal("expected the latest render to be cached")
+	}
+}
+
 func TestRenderTileCachesEmptyHTML(t *testing.T) {
 	t.Parallel()
 
@@ -267,3 +370,205 @@ func TestRenderTileUpstreamError(t *testing.T) {
 type assertError string
 
 func (a assertError) Error() string { return string(a) }
+
+// blockingRunner counts calls and waits for release before returning.
+type blockingRunner struct {
+	count   *int32
+	release <-chan struct{}
+}
+
+func (b blockingRunner) Do(ctx context.Context) (providers.Accumulator, int, int, error) {
+	atomic.AddInt32(b.count, 1)
+	select {
+	case <-b.release:
+	case <-ctx.Done():
+		return nil, 0, 0, ctx.Err()
+	}
+	return providers.Accumulator{"merged": map[string]any{"v": "ok"}}, 1, http.StatusOK, nil
+}
+
+func newTestTemplate(t *testing.T) *template.Template {
+	t.Helper()
+	tmpDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(tmpDir, "tile.gohtml"), []byte(`{{define "tile.gohtml"}}<div>{{index .Data "v"}}</div>{{end}}`), 0o644); err != nil {
+		t.Fatalf("write template: %v", err)
+	}
+	tmpl, err := templates.ParseCellTemplates(tmpDir, templates.TemplateFuncMap())
+	if err != nil {
+		t.Fatalf("parse template: %v", err)
+	}
+	return tmpl
+}
+
+func TestRenderTileDedupesConcurrentRenders(t *testing.T) {
+	t.Parallel()
+
+	cfg := config.DashboardConfig{
+		Tiles: []config.Tile{{Title: "Example", Template: "tile.gohtml", Request: config.Request{TTL: time.Minute}}},
+	}
+	count := int32(0)
+	release := make(chan struct{})
+	runners := []providers.Runner{blockingRunner{count: &count, release: release}}
+	renderer := NewTileRenderer(cfg, runners, newTestTemplate(t), slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	var wg sync.WaitGroup
+	for range 5 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, _, err := renderer.RenderTile(context.Background(), 0); err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		}()
+	}
+	time.Sleep(20 * time.Millisecond) // let all callers join the in-flight render
+	close(release)
+	wg.Wait()
+
+	if got := atomic.LoadInt32(&count); got != 1 {
+		t.Fatalf("expected a single upstream call, got %d", got)
+	}
+}
+
+func TestRenderTileSharedRenderOutlivesCancelledCaller(t *testing.T) {
+	t.Parallel()
+
+	cfg := config.DashboardConfig{
+		Tiles: []config.Tile{{Title: "Example", Template: "tile.gohtml", Request: config.Request{TTL: time.Minute}}},
+	}
+	count := int32(0)
+	release := make(chan struct{})
+	runners := []providers.Runner{blockingRunner{count: &count, release: release}}
+	renderer := NewTileRenderer(cfg, runners, newTestTemplate(t), slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	// The first caller starts the shared render and goes away (e.g. a closed browser tab).
+	ctx, cancel := context.WithCancel(context.Background())
+	first := make(chan int, 1)
+	go func() {
+		_, status, _ := renderer.RenderTile(ctx, 0)
+		first <- status
+	}()
+	time.Sleep(20 * time.Millisecond) // let the first caller start the render
+
+	second := make(chan int, 1)
+	go func() {
+		_, status, err := renderer.RenderTile(context.Background(), 0)
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+		second <- status
+	}()
+	time.Sleep(20 * time.Millisecond) // let the second caller join the in-flight render
+
+	cancel()
+	if status := <-first; status != http.StatusGatewayTimeout {
+		t.Fatalf("expected the cancelled caller to give up with 504, got %d", status)
+	}
+	close(release)
+	if status := <-second; status != http.StatusOK {
+		t.Fatalf("expected the waiting caller to get the render, got %d", status)
+	}
+	if got := atomic.LoadInt32(&count); got != 1 {
+		t.Fatalf("expected a single upstream call, got %d", got)
+	}
+}
+
+func TestRenderTileInFlightRenderNotCachedAfterInvalidate(t *testing.T) {
+	t.Parallel()
+
+	cfg := config.DashboardConfig{
+		Tiles: []config.Tile{{Title: "Example", Template: "tile.gohtml", Request: config.Request{TTL: time.Minute}}},
+	}
+	count := int32(0)
+	release := make(chan struct{})
+	runners := []providers.Runner{blockingRunner{count: &count, release: release}}
+	renderer := NewTileRenderer(cfg, runners, newTestTemplate(t), slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		if _, _, err := renderer.RenderTile(context.Background(), 0); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	}()
+	time.Sleep(20 * time.Millisecond) // let the render reach the runner
+
+	renderer.InvalidateAll() // a purge while the render is in flight
+	close(release)
+	<-done
+
+	if _, ok := renderer.CachedHash(context.Background(), 0); ok {
+		t.Fatal("expected the render started before the purge not to be cached")
+	}
+
+	// Renders started after the purge are cached again.
+	if _, _, err := renderer.RenderTile(context.Background(), 0); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := renderer.CachedHash(context.Background(), 0); !ok {
+		t.Fatal("expected a fresh render to be cached")
+	}
+	if got := atomic.LoadInt32(&count); got != 2 {
+		t.Fatalf("expected two upstream calls, got %d", got)
+	}
+}
+
+func TestPrewarm(t *testing.T) {
+	t.Parallel()
+
+	t.Run("renders cacheable tiles and exposes their hashes", func(t *testing.T) {
+		t.Parallel()
+
+		cfg := config.DashboardConfig{
+			Tiles: []config.Tile{
+				{Title: "A", Template: "tile.gohtml", Request: config.Request{TTL: time.Minute}},
+				{Title: "B", Template: "tile.gohtml", Request: config.Request{TTL: time.Minute}},
+				{Title: "NoTTL", Template: "tile.gohtml"},
+			},
+		}
+		count := int32(0)
+		acc := providers.Accumulator{"merged": map[string]any{"v": "ok"}}
+		runners := []providers.Runner{
+			countingRunner{count: &count, acc: acc, status: http.StatusOK},
+			countingRunner{count: &count, acc: acc, status: http.StatusOK},
+			countingRunner{count: &count, acc: acc, status: http.StatusOK},
+		}
+		renderer := NewTileRenderer(cfg, runners, newTestTemplate(t), slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+		if _, ok := renderer.CachedHash(context.Background(), 0); ok {
+			t.Fatal("expected no cached hash before prewarm")
+		}
+		if n := renderer.Prewarm(context.Background(), 2, time.Second); n != 2 {
+			t.Fatalf("expected 2 prewarmed tiles, got %d", n)
+		}
+		if got := atomic.LoadInt32(&count); got != 2 {
+			t.Fatalf("expected 2 upstream calls, got %d", got)
+		}
+		expected, _ := hash.Any("<div>ok</div>")
+		if h, ok := renderer.CachedHash(context.Background(), 1); !ok || h != expected {
+			t.Fatalf("unexpected cached hash: %q (ok=%v)", h, ok)
+		}
+		if _, ok := renderer.CachedHash(context.Background(), 2); ok {
+			t.Fatal("tiles without TTL must not be cached")
+		}
+	})
+
+	t.Run("gives up after the deadline", func(t *testing.T) {
+		t.Parallel()
+
+		cfg := config.DashboardConfig{
+			Tiles: []config.Tile{{Title: "Slow", Template: "tile.gohtml", Request: config.Request{TTL: time.Minute}}},
+		}
+		count := int32(0)
+		runners := []providers.Runner{blockingRunner{count: &count, release: make(chan struct{})}}
+		renderer := NewTileRenderer(cfg, runners, newTestTemplate(t), slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+		start := time.Now()
+		if n := renderer.Prewarm(context.Background(), 1, 20*time.Millisecond); n != 0 {
+			t.Fatalf("expected no prewarmed tiles, got %d", n)
+		}
+		if elapsed := time.Since(start); elapsed > time.Second {
+			t.Fatalf("prewarm ignored its deadline: %s", elapsed)
+		}
+	})
+}
@@ -16,22 +16,25 @@ import (
 )
 
 // NewRouter creates and wires the HTTP mux with handlers and middleware; mounts under routerPrefix  if provided.
+//
+// A single unnamed dashboard is served at "/" with its tile API under /api/v1. Named
+// dashboards are served at /d/{name} with their tile API under /api/v1/d/{name}, and "/"
+// lists them. Playlists are served at /p/{name}.
 func NewRouter(
 	webFS fs.FS,
-	cellTmpl *template.Template,
 	errTmpl *template.Template,
-	cfg config.DashboardConfig,
+	dashboards []handlers.Dashboard,
+	playlists []config.Playlist,
 	logger *slog.Logger,
-	runners []providers.Runner,
+	reg providers.Registry,
 	debug bool,
 	version string,
 	routePrefix string,
+	adminToken string,
 ) http.Handler {
 	// Inner mux registers canonical routes rooted at "/".
 	root := http.NewServeMux()
 
-	renderer := render.NewTileRenderer(cfg, runners, cellTmpl, logger)
-
 	// Serve embedded static files at /static/*.
 	staticContent, _ := fs.Sub(webFS, "web/static")       // sub-FS with static assets
 	fileServer := http.FileServer(http.FS(staticContent)) // file server for static
@@ -41,13 +44,37 @@ func NewRouter(
 	root.Handle("GET /healthz", handlers.Healthz())
 	root.Handle("POST /healthz", handlers.Healthz())
 
-	// Main dashboard handler.
-	root.Handle("/", handlers.BaseHandler(webFS, routePrefix, version, cfg, renderer, logger))
-
-	// API endpoints (tile content + tile hash), exposed under /api/v1/*.
+	// API endpoints (tile content + tile hash + snapshot export), exposed under /api/v1/*.
 	api := http.NewServeMux()
-	api.Handle("GET /tile/{id}", handlers.TileHandler(renderer, errTmpl, logger))
-	api.Handle("GET /hash/{id}", handlers.HashHandler(cfg, renderer, logger))
+	exporter := render.NewExporter(webFS, version)
+
+	if len(dashboards) == 1 && dashboards[0].Config.Name == "" {
+		// Main dashboard handler.
+		d := dashboards[0]
+		root.Handle("/", handlers.BaseHandler(webFS, routePrefix, version, d.Config, d.Renderer, logger))
+		api.Handle("GET /tile/{id}", handlers.TileHandler(d.Config, d.Renderer, errTmpl, logger))
+		api.Handle("GET /hash/{id}", handlers.HashHandler(d.Config, d.Renderer, logger))
+		api.Handle("GET /export", handlers.ExportHandler(exporter, d.Config, d.Renderer, logger))
+	} else {
+		// Named dashboards plus an index page.
+		root.Handle("GET /{$}", handlers.IndexHandler(webFS, routePrefix, version, dashboards, playlists))
+		for _, d := range dashboards {
+			name := d.Config.Name
+			root.Handle("GET /d/"+name, handlers.BaseHandler(webFS, routePrefix, version, d.Config, d.Renderer, logger))
+			api.Handle("GET /d/"+name+"/tile/{id}", handlers.TileHandler(d.Config, d.Renderer, errTmpl, logger))
+			api.Handle("GET /d/"+name+"/hash/{id}", handlers.HashHandler(d.Config, d.Renderer, logger))
+			api.Handle("GET /d/"+name+"/export", handlers.ExportHandler(exporter, d.Config, d.Renderer, logger))
+		}
+		for _, p := range playlists {
+			root.Handle("GET /p/"+p.Name, handlers.PlaylistHandler(webFS, routePrefix, version, p, dashboards))
+		}
+	}
+
+	// Admin endpoints are only mounted when an admin token is configured.
+	if adminToken != "" {
+		api.Handle("POST /cache/purge", handlers.CachePurgeHandler(adminToken, dashboards, reg, logger))
+		api.Handle("GET /cache/stats", handlers.CacheStatsHandler(adminToken, reg))
+	}
 	root.Handle("/api/v1/", http.StripPrefix("/api/v1", api))
 
 	// Mount the whole app under the prefix if provided
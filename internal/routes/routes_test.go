@@ -9,9 +9,12 @@ import (
 	"strings"
 	"testing"
 	"testing/fstest"
+	"time"
 
 	"github.com/gi8lino/tiledash/internal/config"
+	"github.com/gi8lino/tiledash/internal/handlers"
 	"github.com/gi8lino/tiledash/internal/providers"
+	"github.com/gi8lino/tiledash/internal/render"
 	"github.com/gi8lino/tiledash/internal/routes"
 	"github.com/gi8lino/tiledash/internal/templates"
 	"github.com/gi8lino/tiledash/internal/testutils"
@@ -39,7 +42,10 @@ func TestNewRouter(t *testing.T) {
 		"web/templates/css/debug.gohtml":   &fstest.MapFile{Data: []byte(`{{define "css_debug"}}css_debug{{end}}`)},
 		"web/templates/footer.gohtml":      &fstest.MapFile{Data: []byte(`{{define "footer"}}<footer>{{ .Version }}</footer>{{end}}`)},
 		"web/templates/errors/page.gohtml": &fstest.MapFile{Data: []byte(`{{define "page_error"}}<!-- error -->{{end}}`)},
+		"web/templates/index.gohtml":       &fstest.MapFile{Data: []byte(`{{define "index"}}{{range .Dashboards}}[{{.Name}}:{{.Title}}]{{end}}{{end}}`)},
+		"web/templates/playlist.gohtml":    &fstest.MapFile{Data: []byte(`{{define "playlist"}}{{.Name}}:{{.Start}}{{end}}`)},
 		"web/templates/errors/tile.gohtml": &fstest.MapFile{Data: []byte(`{{define "tile_error"}}<!-- tile error -->{{end}}`)},
+		"web/templates/export.gohtml":      &fstest.MapFile{Data: []byte(`{{define "export"}}snapshot {{.Title}}:{{range .Cells}}{{.HTML}}{{end}}{{end}}`)},
 
 		// static files
 		"web/static/css/bootstrap.min.css": &fstest.MapFile{Data: []byte(`/* bootstrap */`)},
@@ -61,13 +67,18 @@ func TestNewRouter(t *testing.T) {
 	cellTmpl, _ := templates.ParseCellTemplates(tmpDir, templates.TemplateFuncMap())
 	errTmpl := templates.ParseCellErrorTemplate(webFS, templates.TemplateFuncMap())
 
+	// single wraps one unnamed dashboard, as served at "/".
+	single := func(cfg config.DashboardConfig, runners []providers.Runner) []handlers.Dashboard {
+		return []handlers.Dashboard{{Config: cfg, Runners: runners, Renderer: render.NewTileRenderer(cfg, runners, cellTmpl, logger)}}
+	}
+
 	t.Run("GET /", func(t *testing.T) {
 		t.Parallel()
 
 		cfg := config.DashboardConfig{Title: "Home"}
 		var runners []providers.Runner // not used by "/" handler
 
-		router := routes.NewRouter(webFS, cellTmpl, errTmpl, cfg, logger, runners, debug, version, "")
+		router := routes.NewRouter(webFS, errTmpl, single(cfg, runners), nil, logger, nil, debug, version, "", "")
 
 		req := httptest.NewRequest("GET", "/", nil)
 		rec := httptest.NewRecorder()
@@ -83,7 +94,7 @@ func TestNewRouter(t *testing.T) {
 		cfg := config.DashboardConfig{}
 		var runners []providers.Runner
 
-		router := routes.NewRouter(webFS, cellTmpl, errTmpl, cfg, logger, runners, debug, version, "")
+		router := routes.NewRouter(webFS, errTmpl, single(cfg, runners), nil, logger, nil, debug, version, "", "")
 
 		req := httptest.NewRequest("GET", "/static/css/bootstrap.min.css", nil)
 		rec := httptest.NewRecorder()
@@ -99,7 +110,7 @@ func TestNewRouter(t *testing.T) {
 		cfg := config.DashboardConfig{}
 		var runners []providers.Runner
 
-		router := routes.NewRouter(webFS, cellTmpl, errTmpl, cfg, logger, runners, debug, version, "")
+		router := routes.NewRouter(webFS, errTmpl, single(cfg, runners), nil, logger, nil, debug, version, "", "")
 
 		req := httptest.NewRequest("GET", "/healthz", nil)
 		rec := httptest.NewRecorder()
@@ -115,7 +126,7 @@ func TestNewRouter(t *testing.T) {
 		cfg := config.DashboardConfig{}
 		var runners []providers.Runner
 
-		router := routes.NewRouter(webFS, cellTmpl, errTmpl, cfg, logger, runners, debug, version, "")
+		router := routes.NewRouter(webFS, errTmpl, single(cfg, runners), nil, logger, nil, debug, version, "", "")
 
 		req := httptest.NewRequest("POST", "/healthz", nil)
 		rec := httptest.NewRecorder()
@@ -146,7 +157,7 @@ func TestNewRouter(t *testing.T) {
 			},
 		}
 
-		router := routes.NewRouter(webFS, cellTmpl, errTmpl, cfg, logger, runners, debug, version, "")
+		router := routes.NewRouter(webFS, errTmpl, single(cfg, runners), nil, logger, nil, debug, version, "", "")
 
 		req := httptest.NewRequest("GET", "/api/v1/tile/0", nil)
 		rec := httptest.NewRecorder()
@@ -172,7 +183,7 @@ func TestNewRouter(t *testing.T) {
 			},
 		}
 
-		router := routes.NewRouter(webFS, cellTmpl, errTmpl, cfg, logger, runners, debug, version, "")
+		router := routes.NewRouter(webFS, errTmpl, single(cfg, runners), nil, logger, nil, debug, version, "", "")
 
 		req := httptest.NewRequest("GET", "/api/v1/hash/0", nil)
 		rec := httptest.NewRecorder()
@@ -182,6 +193,32 @@ func TestNewRouter(t *testing.T) {
 		assert.Regexp(t, `^[a-f0-9]+$`, rec.Body.String())
 	})
 
+	t.Run("GET /api/v1/export", func(t *testing.T) {
+		t.Parallel()
+
+		cfg := config.DashboardConfig{
+			Title: "Board",
+			Tiles: []config.Tile{{Template: "example.gohtml", Title: "Exported"}},
+		}
+		runners := []providers.Runner{
+			mockRunner{
+				fn: func(ctx context.Context) (providers.Accumulator, int, int, error) {
+					return providers.Accumulator{}, 1, http.StatusOK, nil
+				},
+			},
+		}
+
+		router := routes.NewRouter(webFS, errTmpl, single(cfg, runners), nil, logger, nil, debug, version, "", "")
+
+		req := httptest.NewRequest("GET", "/api/v1/export", nil)
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "snapshot Board:<div>Exported</div>", rec.Body.String())
+		assert.Contains(t, rec.Header().Get("Content-Disposition"), `attachment; filename="tiledash-`)
+	})
+
 	t.Run("GET /api/v1/hash/config", func(t *testing.T) {
 		t.Parallel()
 
@@ -192,7 +229,7 @@ func TestNewRouter(t *testing.T) {
 		}
 		var runners []providers.Runner
 
-		router := routes.NewRouter(webFS, cellTmpl, errTmpl, cfg, logger, runners, debug, version, "")
+		router := routes.NewRouter(webFS, errTmpl, single(cfg, runners), nil, logger, nil, debug, version, "", "")
 
 		req := httptest.NewRequest("GET", "/api/v1/hash/config", nil)
 		rec := httptest.NewRecorder()
@@ -201,4 +238,72 @@ func TestNewRouter(t *testing.T) {
 		assert.Equal(t, http.StatusOK, rec.Code)
 		assert.Regexp(t, `^[a-f0-9]+$`, rec.Body.String())
 	})
+
+	t.Run("POST /api/v1/cache/purge", func(t *testing.T) {
+		t.Parallel()
+
+		cfg := config.DashboardConfig{}
+		var runners []providers.Runner
+
+		// Without an admin token the endpoint is not mounted.
+		router := routes.NewRouter(webFS, errTmpl, single(cfg, runners), nil, logger, nil, debug, version, "", "")
+		req := httptest.NewRequest("POST", "/api/v1/cache/purge", nil)
+		req.Header.Set("Authorization", "Bearer token")
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+
+		router = routes.NewRouter(webFS, errTmpl, single(cfg, runners), nil, logger, nil, debug, version, "", "token")
+		req = httptest.NewRequest("POST", "/api/v1/cache/purge", nil)
+		req.Header.Set("Authorization", "Bearer token")
+		rec = httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.JSONEq(t, `{"scope":"all","responses":0,"renders":0}`, rec.Body.String())
+	})
+
+	t.Run("named dashboards", func(t *testing.T) {
+		t.Parallel()
+
+		var dashboards []handlers.Dashboard
+		for _, name := range []string{"team-a", "team-b"} {
+			cfg := config.DashboardConfig{Name: name, Title: "Title " + name}
+			var runners []providers.Runner
+			dashboards = append(dashboards, handlers.Dashboard{
+				Config:   cfg,
+				Runners:  runners,
+				Renderer: render.NewTileRenderer(cfg, runners, cellTmpl, logger),
+			})
+		}
+		playlists := []config.Playlist{{
+			Name:     "office",
+			Interval: time.Minute,
+			Entries:  []config.PlaylistEntry{{Dashboard: "team-a"}, {Dashboard: "team-b"}},
+		}}
+		router := routes.NewRouter(webFS, errTmpl, dashboards, playlists, logger, nil, debug, version, "", "")
+
+		cases := []struct {
+			path string
+			code int
+			body string
+		}{
+			{path: "/", code: http.StatusOK, body: "[team-a:Title team-a][team-b:Title team-b]"},
+			{path: "/d/team-b", code: http.StatusOK, body: "Tiledash"},
+			{path: "/d/nope", code: http.StatusNotFound},
+			{path: "/api/v1/d/team-a/hash/config", code: http.StatusOK},
+			{path: "/api/v1/hash/config", code: http.StatusNotFound},
+			{path: "/p/office?start=team-b", code: http.StatusOK, body: "office:1"},
+			{path: "/p/nope", code: http.StatusNotFound},
+		}
+		for _, tc := range cases {
+			req := httptest.NewRequest("GET", tc.path, nil)
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			assert.Equal(t, tc.code, rec.Code, tc.path)
+			if tc.body != "" {
+				assert.Contains(t, rec.Body.String(), tc.body, tc.path)
+			}
+		}
+	})
 }
@@ -0,0 +1,198 @@
+package templates
+
+import (
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// adf converts an Atlassian Document Format document (descriptions and comments of the
+// v3 REST API) to sanitized HTML. doc is the decoded document or its JSON; nil renders as empty.
+// Unknown nodes render their content, so newer node types degrade to plain text.
+func adf(doc any) (template.HTML, error) {
+	switch d := doc.(type) {
+	case nil:
+		return "", nil
+	case string, []byte:
+		src := markupString(d)
+		if strings.TrimSpace(src) == "" {
+			return "", nil
+		}
+		var v any
+		if err := json.Unmarshal([]byte(src), &v); err != nil {
+			return "", fmt.Errorf("adf: %w", err)
+		}
+		doc = v
+	}
+	node, ok := doc.(map[string]any)
+	if !ok {
+		return "", fmt.Errorf("adf: expected a document object, got %T", doc)
+	}
+	var b strings.Builder
+	adfNode(&b, node)
+	return sanitize(b.String()), nil
+}
+
+// adfBlocks maps ADF block nodes to the HTML element wrapping their content.
+var adfBlocks = map[string]string{
+	"paragraph":   "p",
+	"blockquote":  "blockquote",
+	"bulletList":  "ul",
+	"orderedList": "ol",
+	"listItem":    "li",
+	"table":       "table",
+	"tableRow":    "tr",
+	"tableHeader": "th",
+	"tableCell":   "td",
+	"taskList":    "ul",
+	"taskItem":    "li",
+}
+
+// adfNode writes a node and its content.
+func adfNode(b *strings.Builder, n map[string]any) {
+	typ, _ := n["type"].(string)
+	attrs, _ := n["attrs"].(map[string]any)
+
+	if tag, ok := adfBlocks[typ]; ok {
+		b.WriteString("<" + tag + ">")
+		adfContent(b, n)
+		b.WriteString("</" + tag + ">")
+		return
+	}
+
+	switch typ {
+	case "text":
+		text, _ := n["text"].(string)
+		b.WriteString(adfMarks(template.HTMLEscapeString(text), n["marks"]))
+	case "heading":
+		level := min(max(int(adfNumber(attrs["level"])), 1), 6)
+		tag := "h" + strconv.Itoa(level)
+		b.WriteString("<" + tag + ">")
+		adfContent(b, n)
+		b.WriteString("</" + tag + ">")
+	case "codeBlock":
+		class := ""
+		if lang, _ := attrs["language"].(string); lang != "" {
+			class = ` class="language-` + template.HTMLEscapeString(lang) + `"`
+		}
+		b.WriteString("<pre><code" + class + ">" + template.HTMLEscapeString(adfText(n)) + "</code></pre>")
+	case "hardBreak":
+		b.WriteString("<br>")
+	case "rule":
+		b.WriteString("<hr>")
+	case "panel":
+		b.WriteString(`<div class="td-panel">`)
+		adfContent(b, n)
+		b.WriteString("</div>")
+	case "expand", "nestedExpand":
+		if title, _ := attrs["title"].(string); title != "" {
+			b.WriteString("<p><strong>" + template.HTMLEscapeString(title) + "</strong></p>")
+		}
+		adfContent(b, n)
+	case "mention":
+		b.WriteString(`<span class="td-mention">` + template.HTMLEscapeString(adfAttr(attrs, "text", "id")) + "</span>")
+	case "emoji":
+		b.WriteString(template.HTMLEscapeString(adfAttr(attrs, "text", "shortName")))
+	case "status":
+		b.WriteString(`<span class="td-status">` + template.HTMLEscapeString(adfAttr(attrs, "text")) + "</span>")
+	case "date":
+		if ms := adfNumber(attrs["timestamp"]); ms > 0 {
+			b.WriteString(time.UnixMilli(int64(ms)).UTC().Format("2006-01-02"))
+		}
+	case "inlineCard", "blockCard", "embedCard":
+		if url, _ := attrs["url"].(string); url != "" {
+			b.WriteString(`<a href="` + template.HTMLEscapeString(url) + `">` + template.HTMLEscapeString(url) + "</a>")
+		}
+	case "media", "mediaSingle", "mediaGroup", "mediaInline":
+		// Attachments can't be resolved outside Jira.
+	default:
+		adfContent(b, n)
+	}
+}
+
+// adfContent writes the child nodes of n.
+func adfContent(b *strings.Builder, n map[string]any) {
+	content, _ := n["content"].([]any)
+	for _, c := range content {
+		if child, ok := c.(map[string]any); ok {
+			adfNode(b, child)
+		}
+	}
+}
+
+// adfMarks wraps escaped text in the HTML for its marks; links wrap everything else.
+func adfMarks(text string, marks any) string {
+	list, _ := marks.([]any)
+	href := ""
+	for _, m := range list {
+		mark, _ := m.(map[string]any)
+		typ, _ := mark["type"].(string)
+		attrs, _ := mark["attrs"].(map[string]any)
+		switch typ {
+		case "strong":
+			text = "<strong>" + text + "</strong>"
+		case "em":
+			text = "<em>" + text + "</em>"
+		case "code":
+			text = "<code>" + text + "</code>"
+		case "strike":
+			text = "<del>" + text + "</del>"
+		case "underline":
+			text = "<u>" + text + "</u>"
+		case "subsup":
+			if attrs["type"] == "sup" {
+				text = "<sup>" + text + "</sup>"
+			} else {
+				text = "<sub>" + text + "</sub>"
+			}
+		case "textColor":
+			if color, _ := attrs["color"].(string); chartColorRe.MatchString(color) {
+				text = `<span style="color: ` + color + `">` + text + "</span>"
+			}
+		case "link":
+			href, _ = attrs["href"].(string)
+		}
+	}
+	if href != "" {
+		text = `<a href="` + template.HTMLEscapeString(href) + `">` + text + "</a>"
+	}
+	return text
+}
+
+// adfText returns the plain text of a node's content.
+func adfText(n map[string]any) string {
+	var b strings.Builder
+	content, _ := n["content"].([]any)
+	for _, c := range content {
+		child, _ := c.(map[string]any)
+		if text, ok := child["text"].(string); ok {
+			b.WriteString(text)
+		} else if child != nil {
+			b.WriteString(adfText(child))
+		}
+	}
+	return b.String()
+}
+
+// adfAttr returns the first non-empty string attribute of keys.
+func adfAttr(attrs map[string]any, keys ...string) string {
+	for _, k := range keys {
+		if s, _ := attrs[k].(string); s != "" {
+			return s
+		}
+	}
+	return ""
+}
+
+// adfNumber returns a numeric attribute; ADF timestamps are strings of milliseconds.
+func adfNumber(v any) float64 {
+	if s, ok := v.(string); ok {
+		f, _ := strconv.ParseFloat(s, 64)
+		return f
+	}
+	f, _ := toFloat(v)
+	return f
+}
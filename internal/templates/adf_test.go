@@ -0,0 +1,97 @@
+package templates
+
+import (
+	"html/template"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestADF(t *testing.T) {
+	t.Parallel()
+
+	t.Run("blocks and marks", func(t *testing.T) {
+		t.Parallel()
+		doc := `{"type":"doc","version":1,"content":[
+			{"type":"heading","attrs":{"level":3},"content":[{"type":"text","text":"Plan"}]},
+			{"type":"paragraph","content":[
+				{"type":"text","text":"bold","marks":[{"type":"strong"},{"type":"em"}]},
+				{"type":"hardBreak"},
+				{"type":"text","text":"link","marks":[{"type":"link","attrs":{"href":"https://a.io"}},{"type":"code"}]},
+				{"type":"text","text":" "},
+				{"type":"mention","attrs":{"id":"1","text":"@Ann"}},
+				{"type":"emoji","attrs":{"shortName":":smile:","text":"😄"}},
+				{"type":"status","attrs":{"text":"Done"}}
+			]},
+			{"type":"bulletList","content":[{"type":"listItem","content":[{"type":"paragraph","content":[{"type":"text","text":"one"}]}]}]},
+			{"type":"codeBlock","attrs":{"language":"go"},"content":[{"type":"text","text":"a < b"}]},
+			{"type":"rule"},
+			{"type":"table","content":[{"type":"tableRow","content":[
+				{"type":"tableHeader","content":[{"type":"paragraph","content":[{"type":"text","text":"h"}]}]},
+				{"type":"tableCell","content":[{"type":"paragraph","content":[{"type":"text","text":"c"}]}]}
+			]}]}
+		]}`
+		out, err := adf(doc)
+		require.NoError(t, err)
+		assert.Equal(t, template.HTML(`<h3>Plan</h3>`+
+			`<p><em><strong>bold</strong></em><br><a href="https://a.io" rel="nofollow noreferrer noopener" target="_blank"><code>link</code></a> `+
+			`<span class="td-mention">@Ann</span>😄<span class="td-status">Done</span></p>`+
+			`<ul><li><p>one</p></li></ul>`+
+			`<pre><code class="language-go">a &lt; b</code></pre><hr>`+
+			`<table><tr><th><p>h</p></th><td><p>c</p></td></tr></table>`), out)
+	})
+
+	t.Run("decoded documents", func(t *testing.T) {
+		t.Parallel()
+		out, err := adf(map[string]any{"type": "doc", "content": []any{
+			map[string]any{"type": "paragraph", "content": []any{
+				map[string]any{"type": "date", "attrs": map[string]any{"timestamp": "1735689600000"}},
+			}},
+		}})
+		require.NoError(t, err)
+		assert.Equal(t, template.HTML("<p>2025-01-01</p>"), out)
+	})
+
+	t.Run("unsafe content", func(t *testing.T) {
+		t.Parallel()
+		out, err := adf(map[string]any{"type": "doc", "content": []any{
+			map[string]any{"type": "paragraph", "content": []any{
+				map[string]any{"type": "text", "text": "<script>x</script>", "marks": []any{
+					map[string]any{"type": "link", "attrs": map[string]any{"href": "javascript:alert(1)"}},
+					map[string]any{"type": "textColor", "attrs": map[string]any{"color": "red;background:url(x)"}},
+				}},
+				map[string]any{"type": "inlineCard", "attrs": map[string]any{"url": "javascript:alert(1)"}},
+			}},
+		}})
+		require.NoError(t, err)
+		assert.Equal(t, template.HTML("<p>&lt;script&gt;x&lt;/script&gt;javascript:alert(1)</p>"), out)
+	})
+
+	t.Run("unknown nodes render their content", func(t *testing.T) {
+		t.Parallel()
+		out, err := adf(map[string]any{"type": "doc", "content": []any{
+			map[string]any{"type": "somethingNew", "content": []any{map[string]any{"type": "text", "text": "kept"}}},
+			map[string]any{"type": "mediaSingle", "content": []any{map[string]any{"type": "media"}}},
+		}})
+		require.NoError(t, err)
+		assert.Equal(t, template.HTML("kept"), out)
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		t.Parallel()
+		for _, doc := range []any{nil, "", " "} {
+			out, err := adf(doc)
+			require.NoError(t, err)
+			assert.Empty(t, out)
+		}
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		t.Parallel()
+		_, err := adf("{")
+		require.EqualError(t, err, "adf: unexpected end of JSON input")
+		_, err = adf([]any{})
+		require.EqualError(t, err, "adf: expected a document object, got []interface {}")
+	})
+}
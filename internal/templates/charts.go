@@ -0,0 +1,402 @@
+package templates
+
+import (
+	"fmt"
+	"html/template"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// chartPalette is the default series colors (Bootstrap's theme colors).
+var chartPalette = []string{
+	"#0d6efd", "#198754", "#ffc107", "#dc3545", "#0dcaf0",
+	"#6f42c1", "#fd7e14", "#20c997", "#d63384", "#6c757d",
+}
+
+// chartColorRe restricts colors to values that cannot break out of an SVG attribute.
+var chartColorRe = regexp.MustCompile(`^(#[0-9a-fA-F]{3,8}|[a-zA-Z]+|(rgb|rgba|hsl|hsla)\([0-9.,%\s]+\)|var\(--[a-zA-Z0-9-]+\))$`)
+
+// chartPoint is a labeled value of a chart.
+type chartPoint struct {
+	Label string
+	Value float64
+}
+
+// chartOptions are the options shared by all charts; not every chart uses all of them.
+type chartOptions struct {
+	Width     float64
+	Height    float64
+	Colors    []string
+	Max       float64 // scale maximum; 0 uses the largest value (gauge: 100)
+	Title     string  // accessible name of the chart
+	Labels    bool    // bar: draw labels and values
+	Legend    bool    // donut: draw a legend
+	Thickness float64 // donut and gauge: ring width
+}
+
+// barChart renders a vertical bar chart. data is a list of numbers, a list of
+// {key|label|name, count|value} dicts (as returned by countBy) or a map of label to number.
+// Options (a dict): width, height, color, colors, max, title, labels.
+func barChart(data any, opts ...map[string]any) (template.HTML, error) {
+	points, err := chartPoints(data)
+	if err != nil {
+		return "", fmt.Errorf("barChart: %w", err)
+	}
+	o, err := parseChartOptions(chartOptions{Width: 300, Height: 150, Labels: true}, opts)
+	if err != nil {
+		return "", fmt.Errorf("barChart: %w", err)
+	}
+
+	const labelH, valueH = 16.0, 14.0
+	top, bottom := 0.0, o.Height
+	if o.Labels {
+		top, bottom = valueH, o.Height-labelH
+	}
+	scale := chartMax(points, o.Max)
+	slot := o.Width / float64(max(len(points), 1))
+	barW := slot * 0.8
+
+	var b strings.Builder
+	openSVG(&b, "bar", o.Width, o.Height, o.Title)
+	for i, p := range points {
+		h := (bottom - top) * math.Max(0, math.Min(p.Value, scale)) / scale
+		x := float64(i)*slot + (slot-barW)/2
+		fmt.Fprintf(&b, `<rect x="%s" y="%s" width="%s" height="%s" fill="%s"><title>%s</title></rect>`,
+			num(x), num(bottom-h), num(barW), num(h), o.color(i), pointTitle(p))
+		if o.Labels {
+			cx := num(x + barW/2)
+			fmt.Fprintf(&b, `<text x="%s" y="%s" text-anchor="middle" font-size="10">%s</text>`,
+				cx, num(bottom-h-3), num(p.Value))
+			fmt.Fprintf(&b, `<text x="%s" y="%s" text-anchor="middle" font-size="10">%s</text>`,
+				cx, num(o.Height-4), template.HTMLEscapeString(p.Label))
+		}
+	}
+	b.WriteString(`</svg>`)
+	return template.HTML(b.String()), nil
+}
+
+// donutChart renders a donut chart with a legend. data is as for barChart.
+// Options: width (the donut's diameter), thickness, colors, title, legend.
+func donutChart(data any, opts ...map[string]any) (template.HTML, error) {
+	points, err := chartPoints(data)
+	if err != nil {
+		return "", fmt.Errorf("donutChart: %w", err)
+	}
+	o, err := parseChartOptions(chartOptions{Width: 120, Legend: true}, opts)
+	if err != nil {
+		return "", fmt.Errorf("donutChart: %w", err)
+	}
+	if o.Thickness <= 0 {
+		o.Thickness = o.Width * 0.18
+	}
+
+	const legendW, lineH = 140.0, 16.0
+	size := o.Width
+	width, height := size, size
+	if o.Legend {
+		width += legendW
+		height = math.Max(size, lineH*float64(len(points))+4)
+	}
+
+	var total float64
+	for _, p := range points {
+		total += math.Max(0, p.Value)
+	}
+
+	cx, cy := size/2, size/2
+	r := (size - o.Thickness) / 2
+	circ := 2 * math.Pi * r
+
+	var b strings.Builder
+	openSVG(&b, "donut", width, height, o.Title)
+	fmt.Fprintf(&b, `<circle cx="%s" cy="%s" r="%s" fill="none" stroke="#e9ecef" stroke-width="%s"/>`,
+		num(cx), num(cy), num(r), num(o.Thickness))
+	offset := 0.0
+	for i, p := range points {
+		if total <= 0 || p.Value <= 0 {
+			continue
+		}
+		dash := circ * p.Value / total
+		fmt.Fprintf(&b, `<circle cx="%s" cy="%s" r="%s" fill="none" stroke="%s" stroke-width="%s" stroke-dasharray="%s %s" stroke-dashoffset="%s" transform="rotate(-90 %s %s)"><title>%s</title></circle>`,
+			num(cx), num(cy), num(r), o.color(i), num(o.Thickness), num(dash), num(circ-dash), num(-offset), num(cx), num(cy), pointTitle(p))
+		offset += dash
+	}
+	fmt.Fprintf(&b, `<text x="%s" y="%s" text-anchor="middle" dominant-baseline="middle" font-size="%s" font-weight="bold">%s</text>`,
+		num(cx), num(cy), num(size/6), num(total))
+	if o.Legend {
+		for i, p := range points {
+			y := lineH*float64(i) + 4
+			fmt.Fprintf(&b, `<rect x="%s" y="%s" width="10" height="10" fill="%s"/>`, num(size+10), num(y), o.color(i))
+			fmt.Fprintf(&b, `<text x="%s" y="%s" font-size="11">%s (%s)</text>`,
+				num(size+26), num(y+9), template.HTMLEscapeString(p.Label), num(p.Value))
+		}
+	}
+	b.WriteString(`</svg>`)
+	return template.HTML(b.String()), nil
+}
+
+// sparkline renders a line of the values, scaled between their minimum and maximum.
+// Options: width, height, color, title.
+func sparkline(data any, opts ...map[string]any) (template.HTML, error) {
+	points, err := chartPoints(data)
+	if err != nil {
+		return "", fmt.Errorf("sparkline: %w", err)
+	}
+	o, err := parseChartOptions(chartOptions{Width: 120, Height: 30}, opts)
+	if err != nil {
+		return "", fmt.Errorf("sparkline: %w", err)
+	}
+
+	const pad = 2.0
+	lo, hi := math.Inf(1), math.Inf(-1)
+	for _, p := range points {
+		lo, hi = math.Min(lo, p.Value), math.Max(hi, p.Value)
+	}
+	step := (o.Width - 2*pad) / float64(max(len(points)-1, 1))
+	y := func(v float64) float64 {
+		if hi == lo {
+			return o.Height / 2
+		}
+		return pad + (o.Height-2*pad)*(hi-v)/(hi-lo)
+	}
+
+	var b strings.Builder
+	openSVG(&b, "sparkline", o.Width, o.Height, o.Title)
+	coords := make([]string, len(points))
+	for i, p := range points {
+		coords[i] = num(pad+float64(i)*step) + "," + num(y(p.Value))
+	}
+	if len(points) > 0 {
+		fmt.Fprintf(&b, `<polyline points="%s" fill="none" stroke="%s" stroke-width="1.5" stroke-linejoin="round" stroke-linecap="round"/>`,
+			strings.Join(coords, " "), o.color(0))
+		last := points[len(points)-1]
+		fmt.Fprintf(&b, `<circle cx="%s" cy="%s" r="2" fill="%s"><title>%s</title></circle>`,
+			num(pad+float64(len(points)-1)*step), num(y(last.Value)), o.color(0), pointTitle(last))
+	}
+	b.WriteString(`</svg>`)
+	return template.HTML(b.String()), nil
+}
+
+// gauge renders a half-circle progress gauge of value out of max (default 100).
+// Options: width, height, color, max, thickness, title.
+func gauge(value any, opts ...map[string]any) (template.HTML, error) {
+	v, ok := toFloat(value)
+	if !ok {
+		return "", fmt.Errorf("gauge: value must be a number, got %T", value)
+	}
+	o, err := parseChartOptions(chartOptions{Width: 120, Height: 70, Max: 100}, opts)
+	if err != nil {
+		return "", fmt.Errorf("gauge: %w", err)
+	}
+	if o.Max <= 0 {
+		return "", fmt.Errorf("gauge: max must be positive")
+	}
+	if o.Thickness <= 0 {
+		o.Thickness = o.Width * 0.12
+	}
+
+	frac := math.Max(0, math.Min(v/o.Max, 1))
+	cx, cy := o.Width/2, o.Height-4
+	r := math.Min(o.Width/2, o.Height-4) - o.Thickness/2
+	arc := func(f float64) string {
+		theta := math.Pi * (1 - f)
+		return fmt.Sprintf("M %s %s A %s %s 0 0 1 %s %s",
+			num(cx-r), num(cy), num(r), num(r), num(cx+r*math.Cos(theta)), num(cy-r*math.Sin(theta)))
+	}
+
+	var b strings.Builder
+	openSVG(&b, "gauge", o.Width, o.Height, o.Title)
+	fmt.Fprintf(&b, `<path d="%s" fill="none" stroke="#e9ecef" stroke-width="%s"/>`, arc(1), num(o.Thickness))
+	if frac > 0 {
+		fmt.Fprintf(&b, `<path d="%s" fill="none" stroke="%s" stroke-width="%s"><title>%s / %s</title></path>`,
+			arc(frac), o.color(0), num(o.Thickness), num(v), num(o.Max))
+	}
+	fmt.Fprintf(&b, `<text x="%s" y="%s" text-anchor="middle" font-size="%s" font-weight="bold">%s%%</text>`,
+		num(cx), num(cy-2), num(o.Width/7), num(math.Round(frac*100)))
+	b.WriteString(`</svg>`)
+	return template.HTML(b.String()), nil
+}
+
+// openSVG writes the opening svg tag; text uses the surrounding color.
+func openSVG(b *strings.Builder, kind string, width, height float64, title string) {
+	fmt.Fprintf(b, `<svg xmlns="http://www.w3.org/2000/svg" class="td-chart td-chart-%s" width="%s" height="%s" viewBox="0 0 %s %s" role="img" fill="currentColor"`,
+		kind, num(width), num(height), num(width), num(height))
+	if title != "" {
+		fmt.Fprintf(b, ` aria-label="%s"`, template.HTMLEscapeString(title))
+	}
+	b.WriteString(`>`)
+	if title != "" {
+		fmt.Fprintf(b, `<title>%s</title>`, template.HTMLEscapeString(title))
+	}
+}
+
+// chartPoints converts chart data into points.
+func chartPoints(data any) ([]chartPoint, error) {
+	if list := toList(data); list != nil {
+		points := make([]chartPoint, 0, len(list))
+		for i, item := range list {
+			if v, ok := toFloat(item); ok {
+				points = append(points, chartPoint{Label: strconv.Itoa(i + 1), Value: v})
+				continue
+			}
+			p, err := pairPoint(item)
+			if err != nil {
+				return nil, fmt.Errorf("item %d: %w", i, err)
+			}
+			points = append(points, p)
+		}
+		return points, nil
+	}
+
+	m, ok := data.(map[string]any)
+	if !ok {
+		if mi, isInts := data.(map[string]int); isInts {
+			m = make(map[string]any, len(mi))
+			for k, v := range mi {
+				m[k] = v
+			}
+		} else {
+			return nil, fmt.Errorf("expected a list or a map of numbers, got %T", data)
+		}
+	}
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	points := make([]chartPoint, 0, len(keys))
+	for _, k := range keys {
+		v, ok := toFloat(m[k])
+		if !ok {
+			return nil, fmt.Errorf("%q: expected a number, got %T", k, m[k])
+		}
+		points = append(points, chartPoint{Label: k, Value: v})
+	}
+	return points, nil
+}
+
+// pairPoint reads a point from a {key|label|name, count|value} dict.
+func pairPoint(item any) (chartPoint, error) {
+	var p chartPoint
+	for _, k := range []string{"label", "key", "name"} {
+		if v, ok := lookupPath(item, k); ok {
+			p.Label = fmt.Sprint(v)
+			break
+		}
+	}
+	for _, k := range []string{"value", "count"} {
+		if v, ok := lookupPath(item, k); ok {
+			f, isNum := toFloat(v)
+			if !isNum {
+				return p, fmt.Errorf("%s: expected a number, got %T", k, v)
+			}
+			p.Value = f
+			return p, nil
+		}
+	}
+	return p, fmt.Errorf("expected a number or a dict with value or count, got %T", item)
+}
+
+// parseChartOptions overlays option dicts onto the chart's defaults.
+func parseChartOptions(o chartOptions, opts []map[string]any) (chartOptions, error) {
+	for _, m := range opts {
+		keys := make([]string, 0, len(m))
+		for k := range m {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys) // "color" before "colors" regardless of map order
+		for _, k := range keys {
+			v := m[k]
+			var err error
+			switch k {
+			case "width":
+				o.Width, err = positive(k, v)
+			case "height":
+				o.Height, err = positive(k, v)
+			case "thickness":
+				o.Thickness, err = positive(k, v)
+			case "max":
+				o.Max, err = positive(k, v)
+			case "color":
+				o.Colors = []string{fmt.Sprint(v)}
+			case "colors":
+				o.Colors = o.Colors[:0:0]
+				for _, c := range toList(v) {
+					o.Colors = append(o.Colors, fmt.Sprint(c))
+				}
+			case "title":
+				o.Title = fmt.Sprint(v)
+			case "labels":
+				o.Labels, err = boolOption(k, v)
+			case "legend":
+				o.Legend, err = boolOption(k, v)
+			default:
+				err = fmt.Errorf("unknown option %q", k)
+			}
+			if err != nil {
+				return o, err
+			}
+		}
+	}
+	for _, c := range o.Colors {
+		if !chartColorRe.MatchString(c) {
+			return o, fmt.Errorf("invalid color %q", c)
+		}
+	}
+	return o, nil
+}
+
+// color returns the i-th series color, cycling through the configured colors or the palette.
+func (o chartOptions) color(i int) string {
+	if len(o.Colors) > 0 {
+		return o.Colors[i%len(o.Colors)]
+	}
+	return chartPalette[i%len(chartPalette)]
+}
+
+// positive reads a positive number option.
+func positive(name string, v any) (float64, error) {
+	f, ok := toFloat(v)
+	if !ok || f <= 0 {
+		return 0, fmt.Errorf("option %q must be a positive number, got %v", name, v)
+	}
+	return f, nil
+}
+
+// boolOption reads a boolean option.
+func boolOption(name string, v any) (bool, error) {
+	b, ok := v.(bool)
+	if !ok {
+		return false, fmt.Errorf("option %q must be true or false, got %v", name, v)
+	}
+	return b, nil
+}
+
+// chartMax returns the scale maximum: the configured one or the largest value (at least 1).
+func chartMax(points []chartPoint, configured float64) float64 {
+	if configured > 0 {
+		return configured
+	}
+	m := 1.0
+	for _, p := range points {
+		m = math.Max(m, p.Value)
+	}
+	return m
+}
+
+// pointTitle returns the escaped tooltip of a point.
+func pointTitle(p chartPoint) string {
+	return template.HTMLEscapeString(p.Label + ": " + num(p.Value))
+}
+
+// num formats a coordinate or value with at most two decimals.
+func num(f float64) string {
+	r := math.Round(f*100) / 100
+	if r == 0 {
+		r = 0 // no "-0"
+	}
+	return strconv.FormatFloat(r, 'f', -1, 64)
+}
@@ -0,0 +1,167 @@
+package templates
+
+import (
+	"bytes"
+	"html/template"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChartPoints(t *testing.T) {
+	t.Parallel()
+
+	t.Run("numbers", func(t *testing.T) {
+		t.Parallel()
+		points, err := chartPoints([]any{1.0, 2, int64(3)})
+		require.NoError(t, err)
+		assert.Equal(t, []chartPoint{{"1", 1}, {"2", 2}, {"3", 3}}, points)
+	})
+
+	t.Run("countBy pairs and label/value dicts", func(t *testing.T) {
+		t.Parallel()
+		points, err := chartPoints([]any{
+			map[string]any{"key": "Done", "count": 4},
+			map[string]any{"label": "Open", "value": 2.5},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []chartPoint{{"Done", 4}, {"Open", 2.5}}, points)
+	})
+
+	t.Run("map sorted by label", func(t *testing.T) {
+		t.Parallel()
+		points, err := chartPoints(map[string]any{"b": 2, "a": 1})
+		require.NoError(t, err)
+		assert.Equal(t, []chartPoint{{"a", 1}, {"b", 2}}, points)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		t.Parallel()
+		_, err := chartPoints("nope")
+		require.EqualError(t, err, "expected a list or a map of numbers, got string")
+		_, err = chartPoints([]any{map[string]any{"key": "x"}})
+		require.EqualError(t, err, "item 0: expected a number or a dict with value or count, got map[string]interface {}")
+		_, err = chartPoints(map[string]any{"a": "x"})
+		require.EqualError(t, err, `"a": expected a number, got string`)
+	})
+}
+
+func TestParseChartOptions(t *testing.T) {
+	t.Parallel()
+
+	o, err := parseChartOptions(chartOptions{Width: 1}, []map[string]any{{
+		"width": 200, "height": 50.5, "colors": []any{"red", "#fff"}, "title": "Status", "labels": false,
+	}})
+	require.NoError(t, err)
+	assert.Equal(t, chartOptions{Width: 200, Height: 50.5, Colors: []string{"red", "#fff"}, Title: "Status"}, o)
+	assert.Equal(t, "#fff", o.color(3))
+
+	for opt, msg := range map[string]string{
+		"width":  `option "width" must be a positive number, got -1`,
+		"labels": `option "labels" must be true or false, got -1`,
+		"bogus":  `unknown option "bogus"`,
+	} {
+		_, err := parseChartOptions(chartOptions{}, []map[string]any{{opt: -1}})
+		require.EqualError(t, err, msg)
+	}
+
+	_, err = parseChartOptions(chartOptions{}, []map[string]any{{"color": `red" onload="alert(1)`}})
+	require.EqualError(t, err, `invalid color "red\" onload=\"alert(1)"`)
+}
+
+func TestBarChart(t *testing.T) {
+	t.Parallel()
+
+	out, err := barChart([]any{
+		map[string]any{"key": "Done", "count": 4},
+		map[string]any{"key": "<b>Open</b>", "count": 2},
+	}, map[string]any{"width": 100, "height": 100, "color": "#123"})
+	require.NoError(t, err)
+
+	svg := string(out)
+	assert.True(t, strings.HasPrefix(svg, `<svg xmlns="http://www.w3.org/2000/svg" class="td-chart td-chart-bar" width="100" height="100" viewBox="0 0 100 100"`))
+	assert.Contains(t, svg, `<rect x="5" y="14" width="40" height="70" fill="#123"><title>Done: 4</title></rect>`)
+	assert.Contains(t, svg, `<rect x="55" y="49" width="40" height="35" fill="#123">`)
+	assert.Contains(t, svg, `&lt;b&gt;Open&lt;/b&gt;`)
+	assert.NotContains(t, svg, `<b>`)
+
+	again, err := barChart([]any{
+		map[string]any{"key": "Done", "count": 4},
+		map[string]any{"key": "<b>Open</b>", "count": 2},
+	}, map[string]any{"width": 100, "height": 100, "color": "#123"})
+	require.NoError(t, err)
+	assert.Equal(t, out, again, "output is deterministic so tile hashes stay stable")
+}
+
+func TestDonutChart(t *testing.T) {
+	t.Parallel()
+
+	out, err := donutChart(map[string]any{"Done": 3, "Open": 1}, map[string]any{"title": "Status"})
+	require.NoError(t, err)
+	svg := string(out)
+	assert.Contains(t, svg, `aria-label="Status"><title>Status</title>`)
+	assert.Equal(t, 3, strings.Count(svg, "<circle"), "track and one ring per slice")
+	assert.Contains(t, svg, `stroke="#0d6efd"`)
+	assert.Contains(t, svg, `stroke="#198754"`)
+	assert.Contains(t, svg, `>4</text>`, "total in the center")
+	assert.Contains(t, svg, `>Done (3)</text>`)
+
+	out, err = donutChart([]any{1, 1}, map[string]any{"legend": false})
+	require.NoError(t, err)
+	assert.Contains(t, string(out), `width="120" height="120"`)
+}
+
+func TestSparkline(t *testing.T) {
+	t.Parallel()
+
+	out, err := sparkline([]any{1, 3, 2}, map[string]any{"width": 44, "height": 14})
+	require.NoError(t, err)
+	assert.Contains(t, string(out), `<polyline points="2,12 22,2 42,7"`)
+
+	out, err = sparkline([]any{5, 5})
+	require.NoError(t, err)
+	assert.Contains(t, string(out), `points="2,15 118,15"`)
+
+	out, err = sparkline([]any{})
+	require.NoError(t, err)
+	assert.NotContains(t, string(out), "polyline")
+}
+
+func TestGauge(t *testing.T) {
+	t.Parallel()
+
+	out, err := gauge(3, map[string]any{"max": 4})
+	require.NoError(t, err)
+	assert.Contains(t, string(out), `>75%</text>`)
+	assert.Contains(t, string(out), `<title>3 / 4</title>`)
+
+	out, err = gauge(150)
+	require.NoError(t, err)
+	assert.Contains(t, string(out), `>100%</text>`)
+
+	out, err = gauge(0)
+	require.NoError(t, err)
+	assert.Equal(t, 1, strings.Count(string(out), "<path"), "only the track")
+
+	_, err = gauge("x")
+	require.EqualError(t, err, "gauge: value must be a number, got string")
+}
+
+func TestChartsInTemplates(t *testing.T) {
+	t.Parallel()
+
+	tmpl := template.Must(template.New("t").Funcs(TemplateFuncMap()).Parse(
+		`<div>{{ barChart (countBy "fields.status.name" .Data) (dict "height" 80) }}</div>`,
+	))
+	var buf bytes.Buffer
+	require.NoError(t, tmpl.Execute(&buf, map[string]any{"Data": testIssues()}))
+	assert.Contains(t, buf.String(), `<div><svg xmlns="http://www.w3.org/2000/svg" class="td-chart td-chart-bar"`)
+	assert.Contains(t, buf.String(), `<title>Done: 2</title>`)
+
+	tmpl = template.Must(template.New("t").Funcs(TemplateFuncMap()).Parse(`{{ gauge 1 (dict "colour" "red") }}`))
+	err := tmpl.Execute(&buf, nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `gauge: unknown option "colour"`)
+}
@@ -0,0 +1,358 @@
+package templates
+
+import (
+	"html/template"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	wikiBlockRe   = regexp.MustCompile(`^\{(code|noformat|quote|panel)(?::([^}]*))?\}`)
+	wikiHeadingRe = regexp.MustCompile(`^h([1-6])\.\s+(.*)$`)
+	wikiQuoteRe   = regexp.MustCompile(`^bq\.\s+(.*)$`)
+	wikiRuleRe    = regexp.MustCompile(`^-{4,}$`)
+	wikiListRe    = regexp.MustCompile(`^([*#]+|-)\s+(.*)$`)
+
+	wikiMonoRe  = regexp.MustCompile(`\{\{(.+?)\}\}`)
+	wikiColorRe = regexp.MustCompile(`\{color:([^}]+)\}(.*?)\{color\}`)
+	wikiLinkRe  = regexp.MustCompile(`\[([^\[\]]+)\]`)
+	wikiImageRe = regexp.MustCompile(`!([^!\s][^!]*)!`)
+	wikiURLRe   = regexp.MustCompile(`\b(https?://[^\s<>\[\]|]+[^\s<>\[\]|.,;:!?)])`)
+	wikiCiteRe  = regexp.MustCompile(`\?\?(.+?)\?\?`)
+	wikiSlotRe  = regexp.MustCompile("\x01([0-9]+)\x01")
+
+	// wikiEffects maps Jira's text effect markers to HTML elements.
+	wikiEffects = []struct {
+		re  *regexp.Regexp
+		tag string
+	}{
+		{wikiEffectRe(`*`, false), "strong"},
+		{wikiEffectRe(`_`, false), "em"},
+		{wikiEffectRe(`-`, false), "del"},
+		{wikiEffectRe(`+`, false), "ins"},
+		{wikiEffectRe(`^`, true), "sup"},
+		{wikiEffectRe(`~`, true), "sub"},
+	}
+)
+
+// wikiEffectRe matches text wrapped in marker, like *bold*; markers must not touch spaces on
+// the inside nor, unless inWord (x^2^), letters or digits on the outside, so
+// snake_case_names stay as they are.
+func wikiEffectRe(marker string, inWord bool) *regexp.Regexp {
+	m := regexp.QuoteMeta(marker)
+	before, after := `(^|[^\p{L}\p{N}])`, `($|[^\p{L}\p{N}])`
+	if inWord {
+		before, after = `()`, `()`
+	}
+	return regexp.MustCompile(before + m + `([^\s` + m + `](?:[^` + m + `]*?[^\s` + m + `])?)` + m + after)
+}
+
+// jiraWiki converts Jira wiki markup (descriptions and comments of the v2 REST API)
+// to sanitized HTML. nil renders as empty.
+func jiraWiki(src any) template.HTML {
+	return sanitize(wikiToHTML(markupString(src)))
+}
+
+// wikiWriter converts wiki markup line by line, tracking the open paragraph, lists and table.
+type wikiWriter struct {
+	b     strings.Builder
+	para  []string // lines of the open paragraph, already converted
+	lists []byte   // open lists from the outermost: '*' (ul) or '#' (ol)
+	table bool     // a table is open
+}
+
+// wikiToHTML converts wiki markup to unsanitized HTML.
+func wikiToHTML(src string) string {
+	w := &wikiWriter{}
+	lines := strings.Split(strings.ReplaceAll(src, "\r\n", "\n"), "\n")
+	for i := 0; i < len(lines); i++ {
+		line := strings.TrimSpace(lines[i])
+
+		if m := wikiBlockRe.FindStringSubmatch(line); m != nil {
+			w.flush()
+			var body string
+			body, i = wikiBlockBody(lines, i, m[1], line[len(m[0]):])
+			w.block(m[1], m[2], body)
+			continue
+		}
+
+		switch {
+		case line == "":
+			w.flush()
+		case wikiHeadingRe.MatchString(line):
+			w.flush()
+			m := wikiHeadingRe.FindStringSubmatch(line)
+			w.b.WriteString("<h" + m[1] + ">" + wikiInline(m[2]) + "</h" + m[1] + ">")
+		case wikiQuoteRe.MatchString(line):
+			w.flush()
+			w.b.WriteString("<blockquote><p>" + wikiInline(wikiQuoteRe.FindStringSubmatch(line)[1]) + "</p></blockquote>")
+		case wikiRuleRe.MatchString(line):
+			w.flush()
+			w.b.WriteString("<hr>")
+		case wikiListRe.MatchString(line):
+			w.closeParagraph()
+			w.closeTable()
+			m := wikiListRe.FindStringSubmatch(line)
+			w.listItem(m[1], m[2])
+		case strings.HasPrefix(line, "|"):
+			w.closeParagraph()
+			w.closeLists()
+			w.tableRow(line)
+		default:
+			w.closeLists()
+			w.closeTable()
+			w.para = append(w.para, wikiInline(line))
+		}
+	}
+	w.flush()
+	return w.b.String()
+}
+
+// wikiBlockBody returns the content of a {tag} block starting on line i, whose opening
+// tag is followed by rest, and the index of the line that closes it.
+func wikiBlockBody(lines []string, i int, tag, rest string) (string, int) {
+	closing := "{" + tag + "}"
+	if before, _, ok := strings.Cut(rest, closing); ok {
+		return before, i
+	}
+	body := []string{rest}
+	for i++; i < len(lines); i++ {
+		if before, _, ok := strings.Cut(lines[i], closing); ok {
+			body = append(body, before)
+			break
+		}
+		body = append(body, lines[i])
+	}
+	return strings.Trim(strings.Join(body, "\n"), "\n"), i
+}
+
+// block writes a {code}, {noformat}, {quote} or {panel} block.
+func (w *wikiWriter) block(tag, params, body string) {
+	switch tag {
+	case "code":
+		class := ""
+		if lang, _, _ := strings.Cut(params, "|"); lang != "" && !strings.Contains(lang, "=") {
+			class = ` class="language-` + template.HTMLEscapeString(lang) + `"`
+		}
+		w.b.WriteString("<pre><code" + class + ">" + template.HTMLEscapeString(body) + "</code></pre>")
+	case "noformat":
+		w.b.WriteString("<pre>" + template.HTMLEscapeString(body) + "</pre>")
+	case "quote":
+		w.b.WriteString("<blockquote>" + wikiToHTML(body) + "</blockquote>")
+	case "panel":
+		w.b.WriteString(`<div class="td-panel">`)
+		for p := range strings.SplitSeq(params, "|") {
+			if k, v, _ := strings.Cut(p, "="); k == "title" && v != "" {
+				w.b.WriteString("<p><strong>" + wikiInline(v) + "</strong></p>")
+			}
+		}
+		w.b.WriteString(wikiToHTML(body) + "</div>")
+	}
+}
+
+// listItem writes an item of a (nested) list; marker is e.g. "*", "##" or "*#".
+func (w *wikiWriter) listItem(marker, text string) {
+	if marker == "-" {
+		marker = "*"
+	}
+	for n := len(w.lists); n > 0 && (n > len(marker) || w.lists[n-1] != marker[n-1]); n = len(w.lists) {
+		w.b.WriteString("</li>" + wikiListTag(w.lists[n-1], true))
+		w.lists = w.lists[:n-1]
+	}
+	if len(w.lists) == len(marker) {
+		w.b.WriteString("</li>")
+	}
+	for len(w.lists) < len(marker) {
+		kind := marker[len(w.lists)]
+		w.b.WriteString(wikiListTag(kind, false))
+		w.lists = append(w.lists, kind)
+	}
+	w.b.WriteString("<li>" + wikiInline(text))
+}
+
+// wikiListTag returns the opening or closing tag of a list of the given kind.
+func wikiListTag(kind byte, closing bool) string {
+	tag := "ul"
+	if kind == '#' {
+		tag = "ol"
+	}
+	if closing {
+		return "</" + tag + ">"
+	}
+	return "<" + tag + ">"
+}
+
+// tableRow writes a table row; cells starting with || are headers.
+func (w *wikiWriter) tableRow(line string) {
+	if !w.table {
+		w.b.WriteString("<table>")
+		w.table = true
+	}
+	w.b.WriteString("<tr>")
+	for _, c := range wikiCells(line) {
+		tag := "td"
+		if c.header {
+			tag = "th"
+		}
+		w.b.WriteString("<" + tag + ">" + wikiInline(strings.TrimSpace(c.text)) + "</" + tag + ">")
+	}
+	w.b.WriteString("</tr>")
+}
+
+// wikiCell is a cell of a table row.
+type wikiCell struct {
+	header bool
+	text   string
+}
+
+// wikiCells splits a table row into cells, ignoring pipes inside [links] and {macros}.
+func wikiCells(line string) []wikiCell {
+	var (
+		cells   []wikiCell
+		cur     strings.Builder
+		header  bool
+		depth   int
+		started bool
+	)
+	for i := 0; i < len(line); i++ {
+		c := line[i]
+		switch {
+		case c == '[' || c == '{':
+			depth++
+		case (c == ']' || c == '}') && depth > 0:
+			depth--
+		case c == '|' && depth == 0:
+			if started {
+				cells = append(cells, wikiCell{header: header, text: cur.String()})
+				cur.Reset()
+			}
+			header = i+1 < len(line) && line[i+1] == '|'
+			if header {
+				i++
+			}
+			started = true
+			continue
+		}
+		cur.WriteByte(c)
+	}
+	if strings.TrimSpace(cur.String()) != "" {
+		cells = append(cells, wikiCell{header: header, text: cur.String()})
+	}
+	return cells
+}
+
+// flush closes everything that is open.
+func (w *wikiWriter) flush() {
+	w.closeParagraph()
+	w.closeLists()
+	w.closeTable()
+}
+
+func (w *wikiWriter) closeParagraph() {
+	if len(w.para) == 0 {
+		return
+	}
+	w.b.WriteString("<p>" + strings.Join(w.para, "<br>") + "</p>")
+	w.para = nil
+}
+
+func (w *wikiWriter) closeLists() {
+	for i := len(w.lists) - 1; i >= 0; i-- {
+		w.b.WriteString("</li>" + wikiListTag(w.lists[i], true))
+	}
+	w.lists = nil
+}
+
+func (w *wikiWriter) closeTable() {
+	if w.table {
+		w.b.WriteString("</table>")
+		w.table = false
+	}
+}
+
+// wikiInline converts the inline markup of a line: text effects, {{monospace}}, links,
+// mentions, images, colors and \\ line breaks. Converted parts are parked in slots so
+// later rules don't touch them.
+func wikiInline(s string) string {
+	var slots []string
+	park := func(html string) string {
+		slots = append(slots, html)
+		return "\x01" + strconv.Itoa(len(slots)-1) + "\x01"
+	}
+
+	s = wikiMonoRe.ReplaceAllStringFunc(s, func(m string) string {
+		return park("<code>" + template.HTMLEscapeString(wikiMonoRe.FindStringSubmatch(m)[1]) + "</code>")
+	})
+	s = wikiColorRe.ReplaceAllStringFunc(s, func(m string) string {
+		sm := wikiColorRe.FindStringSubmatch(m)
+		color := strings.TrimSpace(sm[1])
+		if !chartColorRe.MatchString(color) {
+			return park(wikiInline(sm[2]))
+		}
+		return park(`<span style="color: ` + color + `">` + wikiInline(sm[2]) + "</span>")
+	})
+	s = wikiLinkRe.ReplaceAllStringFunc(s, func(m string) string {
+		return park(wikiLink(wikiLinkRe.FindStringSubmatch(m)[1]))
+	})
+	s = wikiImageRe.ReplaceAllStringFunc(s, func(m string) string {
+		src, _, _ := strings.Cut(wikiImageRe.FindStringSubmatch(m)[1], "|")
+		if !isWebURL(src) {
+			return "" // attachments can't be resolved outside Jira
+		}
+		return park(`<img src="` + template.HTMLEscapeString(src) + `" alt="">`)
+	})
+	s = wikiURLRe.ReplaceAllStringFunc(s, func(m string) string {
+		return park(wikiAnchor(m, template.HTMLEscapeString(m)))
+	})
+
+	s = template.HTMLEscapeString(s)
+	s = strings.ReplaceAll(s, `\\`, "<br>")
+	s = wikiCiteRe.ReplaceAllString(s, "<cite>$1</cite>")
+	for _, e := range wikiEffects {
+		// Adjacent matches share the delimiter between them, so repeat until nothing changes.
+		for range 4 {
+			next := e.re.ReplaceAllString(s, "${1}<"+e.tag+">${2}</"+e.tag+">${3}")
+			if next == s {
+				break
+			}
+			s = next
+		}
+	}
+
+	return wikiSlotRe.ReplaceAllStringFunc(s, func(m string) string {
+		n, _ := strconv.Atoi(m[1 : len(m)-1])
+		return slots[n]
+	})
+}
+
+// wikiLink converts the content of [...]: [url], [text|url] or a [~user] mention.
+// Anything that isn't a web or mail link is kept as text.
+func wikiLink(content string) string {
+	if user, ok := strings.CutPrefix(content, "~"); ok {
+		user = strings.TrimPrefix(user, "accountid:")
+		return `<span class="td-mention">@` + template.HTMLEscapeString(user) + "</span>"
+	}
+	text, target, ok := strings.Cut(content, "|")
+	if !ok {
+		target = text
+	}
+	target, _, _ = strings.Cut(strings.TrimSpace(target), "|") // drop a tooltip
+	if !isWebURL(target) && !strings.HasPrefix(target, "mailto:") {
+		if !ok {
+			return template.HTMLEscapeString("[" + content + "]")
+		}
+		return template.HTMLEscapeString(text)
+	}
+	return wikiAnchor(target, template.HTMLEscapeString(text))
+}
+
+// wikiAnchor returns a link to href with the given (escaped) label.
+func wikiAnchor(href, label string) string {
+	return `<a href="` + template.HTMLEscapeString(href) + `">` + label + "</a>"
+}
+
+// isWebURL reports whether s is an absolute http or https URL.
+func isWebURL(s string) bool {
+	return strings.HasPrefix(s, "http://") || strings.HasPrefix(s, "https://")
+}
@@ -0,0 +1,102 @@
+package templates
+
+import (
+	"html/template"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJiraWiki(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		src  any
+		want string
+	}{
+		{
+			name: "text effects",
+			src:  "*bold* _em_ -del- +ins+ x^2^ H~2~O ??cite?? {{a*b*}}",
+			want: "<p><strong>bold</strong> <em>em</em> <del>del</del> <ins>ins</ins> x<sup>2</sup> H<sub>2</sub>O <cite>cite</cite> <code>a*b*</code></p>",
+		},
+		{
+			name: "markers inside words are text",
+			src:  "snake_case_name 2-3-4 a - b",
+			want: "<p>snake_case_name 2-3-4 a - b</p>",
+		},
+		{
+			name: "headings, rules and quotes",
+			src:  "h2. Title\n----\nbq. said",
+			want: "<h2>Title</h2><hr><blockquote><p>said</p></blockquote>",
+		},
+		{
+			name: "paragraphs and line breaks",
+			src:  "one\ntwo\\\\three\n\nfour",
+			want: "<p>one<br>two<br>three</p><p>four</p>",
+		},
+		{
+			name: "nested lists",
+			src:  "* a\n** b\n* c\n# d\n#* e",
+			want: "<ul><li>a<ul><li>b</li></ul></li><li>c</li></ul><ol><li>d<ul><li>e</li></ul></li></ol>",
+		},
+		{
+			name: "tables",
+			src:  "||Key||Owner||\n|[X-1|https://jira/X-1]|*Ann*|",
+			want: `<table><tr><th>Key</th><th>Owner</th></tr><tr><td><a href="https://jira/X-1" rel="nofollow noreferrer noopener" target="_blank">X-1</a></td><td><strong>Ann</strong></td></tr></table>`,
+		},
+		{
+			name: "links, mentions and bare URLs",
+			src:  "[https://a.io] [~accountid:42] [WIP] see https://b.io/x.",
+			want: `<p><a href="https://a.io" rel="nofollow noreferrer noopener" target="_blank">https://a.io</a> <span class="td-mention">@42</span> [WIP] see <a href="https://b.io/x" rel="nofollow noreferrer noopener" target="_blank">https://b.io/x</a>.</p>`,
+		},
+		{
+			name: "code and noformat blocks",
+			src:  "{code:java}\nif (a < b) {}\n{code}\n{noformat}*raw*{noformat}",
+			want: `<pre><code class="language-java">if (a &lt; b) {}</code></pre><pre>*raw*</pre>`,
+		},
+		{
+			name: "quote and panel blocks",
+			src:  "{quote}\n*q*\n{quote}\n{panel:title=Note|borderStyle=dashed}\nbody\n{panel}",
+			want: `<blockquote><p><strong>q</strong></p></blockquote><div class="td-panel"><p><strong>Note</strong></p><p>body</p></div>`,
+		},
+		{
+			name: "colors",
+			src:  "{color:#ff0000}red{color} {color:red;x}plain{color}",
+			want: `<p><span style="color: #ff0000">red</span> plain</p>`,
+		},
+		{
+			name: "images",
+			src:  "!https://a.io/x.png|thumbnail! !attached.png!",
+			want: `<p><img src="https://a.io/x.png" alt=""> </p>`,
+		},
+		{
+			name: "unsafe input",
+			src:  "<script>alert(1)</script> [x|javascript:alert(1)] !javascript:alert(1)!",
+			want: "<p>&lt;script&gt;alert(1)&lt;/script&gt; x </p>",
+		},
+		{
+			name: "nil",
+			src:  nil,
+			want: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, template.HTML(tt.want), jiraWiki(tt.src))
+		})
+	}
+}
+
+func TestWikiCells(t *testing.T) {
+	t.Parallel()
+
+	cells := wikiCells("||a|[b|https://x]|{{c|d}}|")
+	assert.Equal(t, []wikiCell{
+		{header: true, text: "a"},
+		{header: false, text: "[b|https://x]"},
+		{header: false, text: "{{c|d}}"},
+	}, cells)
+}
@@ -0,0 +1,66 @@
+package templates
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"regexp"
+
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/extension"
+)
+
+// markupPolicy sanitizes HTML converted from markup: user-generated content elements only,
+// no scripts, styles or event handlers, links limited to http, https and mailto and
+// opened in a new tab without referrer.
+var markupPolicy = newMarkupPolicy()
+
+// markdownRenderer converts GitHub flavored markdown; raw HTML in the source is dropped.
+var markdownRenderer = goldmark.New(goldmark.WithExtensions(extension.GFM))
+
+// newMarkupPolicy returns the sanitizer policy shared by markdown, jiraWiki and adf.
+func newMarkupPolicy() *bluemonday.Policy {
+	p := bluemonday.UGCPolicy()
+	p.AllowURLSchemes("http", "https", "mailto")
+	p.RequireNoFollowOnLinks(true)
+	p.RequireNoReferrerOnLinks(true)
+	p.AddTargetBlankToFullyQualifiedLinks(true)
+	// Jira panels, mentions and statuses are styled by class; text colors come from wiki markup and ADF.
+	p.AllowAttrs("class").Matching(regexp.MustCompile(`^td-[a-z-]+$`)).OnElements("div", "span")
+	p.AllowStyles("color").Matching(chartColorRe).OnElements("span")
+	p.AllowAttrs("class").Matching(regexp.MustCompile(`^language-[a-zA-Z0-9+#-]+$`)).OnElements("code")
+	// Task list items render as disabled checkboxes.
+	p.AllowAttrs("type").Matching(regexp.MustCompile(`^checkbox$`)).OnElements("input")
+	p.AllowAttrs("checked", "disabled").Matching(regexp.MustCompile(`^(|checked|disabled)$`)).OnElements("input")
+	return p
+}
+
+// sanitize strips anything unsafe from converted HTML.
+func sanitize(s string) template.HTML {
+	return template.HTML(markupPolicy.Sanitize(s))
+}
+
+// markdown converts markdown (GitHub flavored: tables, strikethrough, task lists,
+// autolinks) to sanitized HTML. nil renders as empty.
+func markdown(src any) (template.HTML, error) {
+	var buf bytes.Buffer
+	if err := markdownRenderer.Convert([]byte(markupString(src)), &buf); err != nil {
+		return "", fmt.Errorf("markdown: %w", err)
+	}
+	return sanitize(buf.String()), nil
+}
+
+// markupString returns the markup source of v; nil (e.g. an empty Jira field) is "".
+func markupString(v any) string {
+	switch s := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return s
+	case []byte:
+		return string(s)
+	default:
+		return fmt.Sprint(v)
+	}
+}
@@ -0,0 +1,81 @@
+package templates
+
+import (
+	"bytes"
+	"html/template"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMarkdown(t *testing.T) {
+	t.Parallel()
+
+	t.Run("GitHub flavored", func(t *testing.T) {
+		t.Parallel()
+		out, err := markdown("# Release\n\n**Done** ~~late~~\n\n- [x] ship")
+		require.NoError(t, err)
+		assert.Equal(t, template.HTML("<h1>Release</h1>\n<p><strong>Done</strong> <del>late</del></p>\n"+
+			`<ul>`+"\n"+`<li><input checked="" disabled="" type="checkbox"> ship</li>`+"\n</ul>\n"), out)
+	})
+
+	t.Run("links are safe", func(t *testing.T) {
+		t.Parallel()
+		out, err := markdown("[docs](https://example.com) [evil](javascript:alert(1))")
+		require.NoError(t, err)
+		assert.Equal(t, template.HTML(`<p><a href="https://example.com" rel="nofollow noreferrer noopener" target="_blank">docs</a> evil</p>`+"\n"), out)
+	})
+
+	t.Run("raw HTML is dropped", func(t *testing.T) {
+		t.Parallel()
+		out, err := markdown(`hi <script>alert(1)</script><img src=x onerror="alert(1)">`)
+		require.NoError(t, err)
+		assert.NotContains(t, out, "<script")
+		assert.NotContains(t, out, "onerror")
+	})
+
+	t.Run("code language is kept", func(t *testing.T) {
+		t.Parallel()
+		out, err := markdown("```go\nx := 1\n```")
+		require.NoError(t, err)
+		assert.Equal(t, template.HTML(`<pre><code class="language-go">x := 1`+"\n</code></pre>\n"), out)
+	})
+
+	t.Run("nil is empty", func(t *testing.T) {
+		t.Parallel()
+		out, err := markdown(nil)
+		require.NoError(t, err)
+		assert.Empty(t, out)
+	})
+}
+
+func TestSanitize(t *testing.T) {
+	t.Parallel()
+
+	t.Run("allowed classes and colors", func(t *testing.T) {
+		t.Parallel()
+		out := sanitize(`<div class="td-panel"><span class="td-mention" style="color: #f00">@a</span></div>`)
+		assert.Equal(t, template.HTML(`<div class="td-panel"><span class="td-mention" style="color: #f00">@a</span></div>`), out)
+	})
+
+	t.Run("other classes and styles are dropped", func(t *testing.T) {
+		t.Parallel()
+		out := sanitize(`<span class="btn" style="background: url(javascript:x)" onclick="x()">a</span>`)
+		assert.Equal(t, template.HTML(`<span>a</span>`), out)
+	})
+}
+
+func TestMarkupFuncsInTemplates(t *testing.T) {
+	t.Parallel()
+
+	tmpl := template.Must(template.New("t").Funcs(TemplateFuncMap()).Parse(
+		`{{ markdown .md }}|{{ jiraWiki .wiki }}|{{ adf .doc }}`))
+	var buf bytes.Buffer
+	require.NoError(t, tmpl.Execute(&buf, map[string]any{
+		"md":   "*a*",
+		"wiki": "*b*",
+		"doc":  `{"type":"doc","content":[{"type":"paragraph","content":[{"type":"text","text":"c"}]}]}`,
+	}))
+	assert.Equal(t, "<p><em>a</em></p>\n|<p><strong>b</strong></p>|<p>c</p>", buf.String())
+}
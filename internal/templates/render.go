@@ -9,6 +9,7 @@ import (
 	"reflect"
 
 	"github.com/gi8lino/tiledash/internal/config"
+	"github.com/gi8lino/tiledash/internal/transform"
 )
 
 // RenderError is a generic error returned by RenderCell to surface UI-friendly failures.
@@ -32,13 +33,15 @@ func (e *RenderError) Error() string {
 	return fmt.Sprintf("%s: %s (%s)", e.Title, e.Message, e.Detail)
 }
 
-// RenderCell renders a single dashboard tile by index using pre-fetched data.
+// RenderCell renders a single dashboard tile by index using pre-fetched data. fn is the
+// tile's compiled request transform (nil for none); callers compile it once per tile.
 func RenderCell(
 	ctx context.Context,
 	id int,
 	cfg config.DashboardConfig,
 	tileTmpl *template.Template,
 	data any, // []byte JSON, map[string]any payload, or accumulator {"merged":..., "pages":[...]}
+	fn transform.Func,
 ) (template.HTML, *RenderError) {
 	tile, err := cfg.GetCellByIndex(id)
 	if err != nil {
@@ -50,13 +53,30 @@ func RenderCell(
 		return "", NewRenderError("json", "Response could not be parsed", nerr.Error())
 	}
 
+	// Optional transform replaces .Data; .Acc keeps the original response.
+	var accIn any = acc
+	if fn != nil {
+		if acc == nil {
+			accIn = primary // single-page payloads have no accumulator; keep the untransformed page
+		}
+		if primary, err = fn(ctx, primary); err != nil {
+			return "", NewRenderError("transform", "Response transformation failed", err.Error())
+		}
+	}
+
+	params := config.ParamsFromContext(ctx)
+	if params == nil {
+		params = config.Params{}
+	}
+
 	// Build template input (keep "Data" compatible with your existing templates).
 	in := map[string]any{
-		"ID":    id,
-		"Title": tile.Title,
-		"Data":  primary, // prefer merged or first page payload
-		"Acc":   acc,     // optional full accumulator
-		"Raw":   raw,     // original input for debugging
+		"ID":     id,
+		"Title":  tile.Title,
+		"Data":   primary, // merged or first page payload, or the transform result
+		"Acc":    accIn,   // optional full accumulator, or the untransformed payload
+		"Raw":    raw,     // original input for debugging
+		"Params": params,  // dashboard URL parameters
 	}
 
 	var buf bytes.Buffer
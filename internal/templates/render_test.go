@@ -7,6 +7,7 @@ import (
 	"testing"
 
 	"github.com/gi8lino/tiledash/internal/config"
+	"github.com/gi8lino/tiledash/internal/transform"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -39,7 +40,7 @@ func TestRenderCell(t *testing.T) {
 			},
 		}
 
-		html, rerr := RenderCell(t.Context(), 0, cfg, tmpl, map[string]any{"key": "value"})
+		html, rerr := RenderCell(t.Context(), 0, cfg, tmpl, map[string]any{"key": "value"}, nil)
 		require.Nil(t, rerr)
 		assert.Equal(t, "<div>Test: value</div>", string(html))
 	})
@@ -53,7 +54,7 @@ func TestRenderCell(t *testing.T) {
 			Tiles: []config.Tile{{Title: "T", Template: "s1"}},
 		}
 
-		html, rerr := RenderCell(t.Context(), 0, cfg, tmpl, []byte(`{"k":"v"}`))
+		html, rerr := RenderCell(t.Context(), 0, cfg, tmpl, []byte(`{"k":"v"}`), nil)
 		assert.Nil(t, rerr)
 		assert.Equal(t, "<span>v</span>", string(html))
 	})
@@ -77,7 +78,7 @@ func TestRenderCell(t *testing.T) {
 			},
 		}
 
-		html, rerr := RenderCell(t.Context(), 0, cfg, tmpl, acc)
+		html, rerr := RenderCell(t.Context(), 0, cfg, tmpl, acc, nil)
 		require.Nil(t, rerr)
 		assert.Equal(t, "2", string(html))
 	})
@@ -98,7 +99,7 @@ func TestRenderCell(t *testing.T) {
 			},
 		}
 
-		html, rerr := RenderCell(t.Context(), 0, cfg, tmpl, acc)
+		html, rerr := RenderCell(t.Context(), 0, cfg, tmpl, acc, nil)
 		require.Nil(t, rerr)
 		assert.Equal(t, "yes", string(html))
 	})
@@ -112,11 +113,74 @@ func TestRenderCell(t *testing.T) {
 			Tiles: []config.Tile{{Title: "N", Template: "s1"}},
 		}
 
-		html, rerr := RenderCell(t.Context(), 0, cfg, tmpl, Acc{"x": "ok"})
+		html, rerr := RenderCell(t.Context(), 0, cfg, tmpl, Acc{"x": "ok"}, nil)
 		require.Nil(t, rerr)
 		assert.Equal(t, "ok", string(html))
 	})
 
+	t.Run("transform replaces Data and keeps Acc", func(t *testing.T) {
+		t.Parallel()
+
+		tmpl := template.Must(template.New("").Parse(
+			`{{define "s1"}}{{ .Data.open }}/{{ len (index .Acc.merged "issues") }}{{end}}`))
+		cfg := config.DashboardConfig{
+			Tiles: []config.Tile{{Title: "T", Template: "s1", Request: config.Request{
+				Transform: config.Transform{JQ: `{open: [.issues[] | select(.status == "Open")] | length}`},
+			}}},
+		}
+		acc := map[string]any{"merged": map[string]any{"issues": []any{
+			map[string]any{"status": "Open"}, map[string]any{"status": "Done"}, map[string]any{"status": "Open"},
+		}}}
+
+		fn, err := compileTransform(cfg.Tiles[0].Request.Transform)
+		require.NoError(t, err)
+		html, rerr := RenderCell(t.Context(), 0, cfg, tmpl, acc, fn)
+		require.Nil(t, rerr)
+		assert.Equal(t, "2/3", string(html))
+
+		cfg.Tiles[0].Request.Transform = config.Transform{JMESPath: `{open: length(issues[?status=='Open'])}`}
+		fn, err = compileTransform(cfg.Tiles[0].Request.Transform)
+		require.NoError(t, err)
+		html, rerr = RenderCell(t.Context(), 0, cfg, tmpl, acc, fn)
+		require.Nil(t, rerr)
+		assert.Equal(t, "2/3", string(html))
+	})
+
+	t.Run("transform of a single page keeps the page as Acc", func(t *testing.T) {
+		t.Parallel()
+
+		tmpl := template.Must(template.New("").Parse(`{{define "s1"}}{{ .Data }}/{{ len .Acc.issues }}{{end}}`))
+		cfg := config.DashboardConfig{
+			Tiles: []config.Tile{{Title: "T", Template: "s1", Request: config.Request{
+				Transform: config.Transform{JQ: `.issues | length`},
+			}}},
+		}
+
+		fn, err := compileTransform(cfg.Tiles[0].Request.Transform)
+		require.NoError(t, err)
+		html, rerr := RenderCell(t.Context(), 0, cfg, tmpl, map[string]any{"issues": []any{1, 2}}, fn)
+		require.Nil(t, rerr)
+		assert.Equal(t, "2/2", string(html))
+	})
+
+	t.Run("handles transform error", func(t *testing.T) {
+		t.Parallel()
+
+		tmpl := template.Must(template.New("").Parse(`{{define "s1"}}ok{{end}}`))
+		cfg := config.DashboardConfig{
+			Tiles: []config.Tile{{Title: "T", Template: "s1", Request: config.Request{
+				Transform: config.Transform{JQ: `.issues + 1`},
+			}}},
+		}
+
+		fn, err := compileTransform(cfg.Tiles[0].Request.Transform)
+		require.NoError(t, err)
+		html, rerr := RenderCell(t.Context(), 0, cfg, tmpl, map[string]any{"issues": []any{}}, fn)
+		require.Error(t, rerr)
+		assert.Contains(t, rerr.Error(), "transform: Response transformation failed (jq: ")
+		assert.Empty(t, html)
+	})
+
 	t.Run("handles JSON parsing error for invalid []byte", func(t *testing.T) {
 		t.Parallel()
 
@@ -125,7 +189,7 @@ func TestRenderCell(t *testing.T) {
 			Tiles: []config.Tile{{Title: "Broken JSON", Template: "s1"}},
 		}
 
-		html, rerr := RenderCell(t.Context(), 0, cfg, tmpl, []byte(`{invalid json}`))
+		html, rerr := RenderCell(t.Context(), 0, cfg, tmpl, []byte(`{invalid json}`), nil)
 		require.Error(t, rerr)
 		assert.EqualError(t, rerr, "json: Response could not be parsed (invalid character 'i' looking for beginning of object key string)")
 		assert.Empty(t, html)
@@ -141,7 +205,7 @@ func TestRenderCell(t *testing.T) {
 			Tiles: []config.Tile{{Title: "Template Fail", Template: "s1"}},
 		}
 
-		html, rerr := RenderCell(t.Context(), 0, cfg, errTmpl, map[string]any{})
+		html, rerr := RenderCell(t.Context(), 0, cfg, errTmpl, map[string]any{}, nil)
 		require.Error(t, rerr)
 		assert.EqualError(t, rerr, `template: Template rendering failed (html/template: "s1" is undefined)`)
 		assert.Empty(t, html)
@@ -153,7 +217,7 @@ func TestRenderCell(t *testing.T) {
 		tmpl := template.Must(template.New("").Parse(`{{define "s1"}}ok{{end}}`))
 		cfg := config.DashboardConfig{} // no tiles
 
-		html, rerr := RenderCell(t.Context(), 42, cfg, tmpl, map[string]any{})
+		html, rerr := RenderCell(t.Context(), 42, cfg, tmpl, map[string]any{}, nil)
 		require.Error(t, rerr)
 		assert.EqualError(t, rerr, "render: Failed to get tile (index out of range)")
 		assert.Empty(t, html)
@@ -366,3 +430,8 @@ func TestNormalizeData(t *testing.T) {
 		assert.NotNil(t, raw)
 	})
 }
+
+// compileTransform compiles the transform of a tile like the renderer does.
+func compileTransform(t config.Transform) (transform.Func, error) {
+	return transform.Compile(t.JQ, t.JMESPath)
+}
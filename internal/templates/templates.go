@@ -24,6 +24,43 @@ func ParseBaseTemplates(webFS fs.FS, funcMap template.FuncMap) *template.Templat
 	)
 }
 
+// ParseIndexTemplates parses the dashboard index page (plus the page error layout).
+func ParseIndexTemplates(webFS fs.FS, funcMap template.FuncMap) *template.Template {
+	return template.Must(
+		template.New("tiledash").
+			Funcs(funcMap).
+			ParseFS(webFS,
+				"web/templates/index.gohtml",
+				"web/templates/errors/page.gohtml",
+			),
+	)
+}
+
+// ParsePlaylistTemplates parses the kiosk playlist page (plus the page error layout).
+func ParsePlaylistTemplates(webFS fs.FS, funcMap template.FuncMap) *template.Template {
+	return template.Must(
+		template.New("tiledash").
+			Funcs(funcMap).
+			ParseFS(webFS,
+				"web/templates/playlist.gohtml",
+				"web/templates/errors/page.gohtml",
+			),
+	)
+}
+
+// ParseExportTemplates parses the static snapshot page with the page CSS and the tile error layout.
+func ParseExportTemplates(webFS fs.FS, funcMap template.FuncMap) *template.Template {
+	return template.Must(
+		template.New("tiledash").
+			Funcs(funcMap).
+			ParseFS(webFS,
+				"web/templates/export.gohtml",
+				"web/templates/css/page.gohtml",
+				"web/templates/errors/tile.gohtml",
+			),
+	)
+}
+
 // ParseCellTemplates parses user-defined section templates, ignoring missing files.
 func ParseCellTemplates(templateDir string, funcMap template.FuncMap) (*template.Template, error) {
 	tmpl := template.New("").Funcs(funcMap)
@@ -157,3 +157,14 @@ func TestParseSectionErrorTemplate(t *testing.T) {
 		)
 	})
 }
+
+func TestParseExportTemplates(t *testing.T) {
+	t.Parallel()
+
+	webFS := os.DirFS("../../") // show root directory
+
+	tmpl := templates.ParseExportTemplates(webFS, templates.TemplateFuncMap())
+	for _, name := range []string{"export", "export_grid", "css_page", "tile_error"} {
+		assert.NotNil(t, tmpl.Lookup(name), "template %q should be parsed", name)
+	}
+}
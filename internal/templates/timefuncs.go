@@ -0,0 +1,112 @@
+package templates
+
+import (
+	"fmt"
+	"html/template"
+	"time"
+
+	"github.com/gi8lino/tiledash/internal/config"
+	"github.com/gi8lino/tiledash/internal/timeutil"
+)
+
+// ForDashboard returns the tile templates with the time helpers bound to the dashboard's
+// timezone and locale. Without either, tmpl is returned as is (server time zone, English).
+// It must be called before tmpl is first executed.
+func ForDashboard(tmpl *template.Template, cfg config.DashboardConfig) (*template.Template, error) {
+	if cfg.Timezone == "" && cfg.Locale == "" {
+		return tmpl, nil
+	}
+	loc := time.Local
+	if cfg.Timezone != "" {
+		var err error
+		if loc, err = time.LoadLocation(cfg.Timezone); err != nil {
+			return nil, fmt.Errorf("timezone %q: %w", cfg.Timezone, err)
+		}
+	}
+	locale := timeutil.DefaultLocale
+	if cfg.Locale != "" {
+		var ok bool
+		if locale, ok = timeutil.LookupLocale(cfg.Locale); !ok {
+			return nil, fmt.Errorf("locale %q: unsupported", cfg.Locale)
+		}
+	}
+
+	clone, err := tmpl.Clone()
+	if err != nil {
+		return nil, fmt.Errorf("clone templates: %w", err)
+	}
+	return clone.Funcs(timeFuncs(loc, locale)), nil
+}
+
+// timeFuncs returns the time helpers bound to a time zone and locale. They sit next to
+// sprig's now, date and ago (which keep their behavior) under their own names.
+func timeFuncs(loc *time.Location, locale *timeutil.Locale) template.FuncMap {
+	parse := func(v any) (time.Time, error) { return timeutil.Parse(v, loc) }
+	// keepZone parses like parse but leaves time values in their zone, e.g. from inTimezone.
+	keepZone := func(v any) (time.Time, error) {
+		if t, ok := v.(time.Time); ok {
+			return t, nil
+		}
+		return parse(v)
+	}
+
+	return template.FuncMap{
+		"localNow": func() time.Time { return time.Now().In(loc) },
+		// localDate takes the arguments of sprig's date and falls back to now for unparsable input, like sprig.
+		"localDate": func(layout string, v any) string {
+			t, err := keepZone(v)
+			if err != nil {
+				t = time.Now().In(loc)
+			}
+			return t.Format(layout)
+		},
+		"parseTime": parse,
+		"formatTime": func(layout string, v any) (string, error) {
+			t, err := keepZone(v)
+			if err != nil {
+				return "", err
+			}
+			return locale.Format(t, layout), nil
+		},
+		"inTimezone": func(name string, v any) (time.Time, error) {
+			zone, err := time.LoadLocation(name)
+			if err != nil {
+				return time.Time{}, fmt.Errorf("inTimezone: %w", err)
+			}
+			return timeutil.Parse(v, zone)
+		},
+		"timeAgo": func(v any) (string, error) {
+			t, err := parse(v)
+			if err != nil {
+				return "", err
+			}
+			return locale.Ago(t, time.Now()), nil
+		},
+		"humanizeDuration": func(v any) (string, error) {
+			d, err := timeutil.ParseDuration(v)
+			if err != nil {
+				return "", err
+			}
+			return locale.Humanize(d), nil
+		},
+		"businessDaysBetween": func(from, to any) (int, error) {
+			a, err := parse(from)
+			if err != nil {
+				return 0, err
+			}
+			b, err := parse(to)
+			if err != nil {
+				return 0, err
+			}
+			return timeutil.BusinessDaysBetween(a, b), nil
+		},
+		"weekOf": func(v any) (int, error) {
+			t, err := parse(v)
+			if err != nil {
+				return 0, err
+			}
+			_, week := t.ISOWeek()
+			return week, nil
+		},
+	}
+}
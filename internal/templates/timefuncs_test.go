@@ -0,0 +1,136 @@
+package templates
+
+import (
+	"bytes"
+	"html/template"
+	"testing"
+	"time"
+
+	"github.com/gi8lino/tiledash/internal/config"
+	"github.com/gi8lino/tiledash/internal/timeutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestForDashboard(t *testing.T) {
+	t.Parallel()
+
+	parse := func(t *testing.T) *template.Template {
+		t.Helper()
+		return template.Must(template.New("").Funcs(TemplateFuncMap()).Parse(
+			`{{define "t"}}{{ .At | parseTime | localDate "15:04 MST" }} {{ formatTime "Monday" .At }} {{ .Since | humanizeDuration }}{{end}}`,
+		))
+	}
+	data := map[string]any{"At": "2025-03-10T12:00:00Z", "Since": 7200}
+
+	t.Run("binds timezone and locale", func(t *testing.T) {
+		t.Parallel()
+
+		tmpl, err := ForDashboard(parse(t), config.DashboardConfig{Timezone: "Europe/Zurich", Locale: "de"})
+		require.NoError(t, err)
+		var buf bytes.Buffer
+		require.NoError(t, tmpl.ExecuteTemplate(&buf, "t", data))
+		assert.Equal(t, "13:00 CET Montag 2 Stunden", buf.String())
+	})
+
+	t.Run("unchanged without settings", func(t *testing.T) {
+		t.Parallel()
+
+		base := parse(t)
+		tmpl, err := ForDashboard(base, config.DashboardConfig{})
+		require.NoError(t, err)
+		assert.Same(t, base, tmpl)
+	})
+
+	t.Run("dashboards do not affect each other", func(t *testing.T) {
+		t.Parallel()
+
+		base := parse(t)
+		ny, err := ForDashboard(base, config.DashboardConfig{Timezone: "America/New_York"})
+		require.NoError(t, err)
+		tokyo, err := ForDashboard(base, config.DashboardConfig{Timezone: "Asia/Tokyo", Locale: "fr"})
+		require.NoError(t, err)
+
+		var a, b bytes.Buffer
+		require.NoError(t, ny.ExecuteTemplate(&a, "t", data))
+		require.NoError(t, tokyo.ExecuteTemplate(&b, "t", data))
+		assert.Equal(t, "08:00 EDT Monday 2 hours", a.String())
+		assert.Equal(t, "21:00 JST lundi 2 heures", b.String())
+	})
+
+	t.Run("sprig's now, date and ago keep their behavior", func(t *testing.T) {
+		t.Parallel()
+
+		base := template.Must(template.New("").Funcs(TemplateFuncMap()).Parse(
+			`{{define "t"}}{{ now.Location }} {{ ago .At }} {{ localNow.Location }}{{end}}`,
+		))
+		tmpl, err := ForDashboard(base, config.DashboardConfig{Timezone: "Europe/Zurich"})
+		require.NoError(t, err)
+		var buf bytes.Buffer
+		require.NoError(t, tmpl.ExecuteTemplate(&buf, "t", map[string]any{"At": time.Now().Add(-2 * time.Hour)}))
+		assert.Equal(t, "Local 2h0m0s Europe/Zurich", buf.String())
+	})
+
+	t.Run("rejects unknown settings", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := ForDashboard(parse(t), config.DashboardConfig{Timezone: "Nowhere/Special"})
+		require.Error(t, err)
+		_, err = ForDashboard(parse(t), config.DashboardConfig{Locale: "xx"})
+		require.EqualError(t, err, `locale "xx": unsupported`)
+	})
+}
+
+func TestTimeFuncs(t *testing.T) {
+	t.Parallel()
+
+	zurich, err := time.LoadLocation("Europe/Zurich")
+	require.NoError(t, err)
+	fm := timeFuncs(zurich, timeutil.DefaultLocale)
+
+	run := func(t *testing.T, src string, data any) (string, error) {
+		t.Helper()
+		tmpl := template.Must(template.New("t").Funcs(fm).Parse(src))
+		var buf bytes.Buffer
+		err := tmpl.Execute(&buf, data)
+		return buf.String(), err
+	}
+
+	t.Run("timeAgo", func(t *testing.T) {
+		t.Parallel()
+		out, err := run(t, `{{ timeAgo . }}`, time.Now().Add(-50*time.Hour))
+		require.NoError(t, err)
+		assert.Equal(t, "2 days ago", out)
+	})
+
+	t.Run("business days and week", func(t *testing.T) {
+		t.Parallel()
+		out, err := run(t, `{{ businessDaysBetween "2025-03-07" "2025-03-12" }} {{ weekOf "2025-03-10T12:00:00Z" }}`, nil)
+		require.NoError(t, err)
+		assert.Equal(t, "3 11", out)
+	})
+
+	t.Run("inTimezone", func(t *testing.T) {
+		t.Parallel()
+		out, err := run(t, `{{ inTimezone "Asia/Tokyo" 1741608000000 | localDate "2006-01-02 15:04" }}`, nil)
+		require.NoError(t, err)
+		assert.Equal(t, "2025-03-10 21:00", out)
+
+		_, err = run(t, `{{ inTimezone "Nowhere/Special" 0 }}`, nil)
+		require.Error(t, err)
+	})
+
+	t.Run("localNow is in the zone", func(t *testing.T) {
+		t.Parallel()
+		out, err := run(t, `{{ localNow.Location }}`, nil)
+		require.NoError(t, err)
+		assert.Equal(t, "Europe/Zurich", out)
+	})
+
+	t.Run("parse errors abort the template", func(t *testing.T) {
+		t.Parallel()
+		_, err := run(t, `{{ parseTime "soon" }}`, nil)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `parse time: unrecognized time "soon"`)
+	})
+}
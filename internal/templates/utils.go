@@ -1,12 +1,21 @@
 package templates
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
 	"html/template"
+	"maps"
+	"reflect"
+	"regexp"
 	"sort"
+	"strconv"
 	"strings"
 	"time"
 
+	"github.com/gi8lino/tiledash/internal/timeutil"
+	"github.com/gi8lino/tiledash/internal/transform"
+
 	"github.com/Masterminds/sprig/v3"
 )
 
@@ -24,6 +33,25 @@ func TemplateFuncMap() template.FuncMap {
 	fm["defaultStr"] = defaultStr
 	fm["typeOf"] = typeOf
 	fm["sumBy"] = sumBy
+	fm["groupBy"] = groupBy
+	fm["countBy"] = countBy
+	fm["where"] = where
+	fm["filter"] = filter
+	fm["pluck"] = pluck // replaces sprig's pluck; still accepts dicts as separate arguments
+	fm["topN"] = topN
+	fm["flatten"] = flatten
+	fm["barChart"] = barChart
+	fm["donutChart"] = donutChart
+	fm["sparkline"] = sparkline
+	fm["gauge"] = gauge
+	fm["jq"] = jqQuery
+	fm["jsonpath"] = jsonPathQuery
+	fm["markdown"] = markdown
+	fm["jiraWiki"] = jiraWiki
+	fm["adf"] = adf
+
+	// Time helpers in the server's time zone; ForDashboard binds them to a dashboard's.
+	maps.Copy(fm, timeFuncs(time.Local, timeutil.DefaultLocale))
 
 	return fm
 }
@@ -159,18 +187,305 @@ func typeOf(v any) string {
 	return fmt.Sprintf("%T", v)
 }
 
-// sumBy returns the sum of a field in a slice of map[string]any.
-func sumBy(field string, items []map[string]any) float64 {
+// sumBy returns the sum of a numeric field (dotted path) over a list of objects,
+// e.g. decoded JSON ([]any) or []map[string]any. Non-numeric values are skipped.
+func sumBy(path string, items any) float64 {
 	var total float64
-	for _, item := range items {
-		switch v := item[field].(type) {
-		case int:
-			total += float64(v)
-		case int64:
-			total += float64(v)
-		case float64:
-			total += v
+	for _, item := range toList(items) {
+		v, _ := lookupPath(item, path)
+		if f, ok := toFloat(v); ok {
+			total += f
 		}
 	}
 	return total
 }
+
+// groupBy groups a list of objects by the value at a dotted path.
+// Items without the path are grouped under "".
+func groupBy(path string, items any) map[string][]any {
+	groups := map[string][]any{}
+	for _, item := range toList(items) {
+		key := groupKey(item, path)
+		groups[key] = append(groups[key], item)
+	}
+	return groups
+}
+
+// countBy counts the items of a list per value at a dotted path and returns
+// a list of {"key", "count"} dicts sorted by count (descending), then key.
+func countBy(path string, items any) []any {
+	counts := map[string]int{}
+	for _, item := range toList(items) {
+		counts[groupKey(item, path)]++
+	}
+	keys := make([]string, 0, len(counts))
+	for k := range counts {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if counts[keys[i]] != counts[keys[j]] {
+			return counts[keys[i]] > counts[keys[j]]
+		}
+		return keys[i] < keys[j]
+	})
+	out := make([]any, 0, len(keys))
+	for _, k := range keys {
+		out = append(out, map[string]any{"key": k, "count": counts[k]})
+	}
+	return out
+}
+
+// where keeps the items whose value at a dotted path equals value.
+func where(path string, value any, items any) ([]any, error) {
+	return filter(path, "==", value, items)
+}
+
+// filter keeps the items whose value at a dotted path satisfies op against value.
+// Operators: == != < <= > >= (numbers compare numerically, everything else as strings),
+// contains (substring or list element), in (value is a list holding the field),
+// prefix, suffix, matches (regular expression) and exists (value is true or false).
+func filter(path, op string, value any, items any) ([]any, error) {
+	var re *regexp.Regexp
+	if op == "matches" {
+		var err error
+		if re, err = regexp.Compile(fmt.Sprint(value)); err != nil {
+			return nil, fmt.Errorf("filter: %w", err)
+		}
+	}
+
+	out := []any{}
+	for _, item := range toList(items) {
+		field, found := lookupPath(item, path)
+		var keep bool
+		switch op {
+		case "==", "eq":
+			keep = found && looseEqual(field, value)
+		case "!=", "ne":
+			keep = !found || !looseEqual(field, value)
+		case "<", "lt":
+			keep = found && looseCompare(field, value) < 0
+		case "<=", "le":
+			keep = found && looseCompare(field, value) <= 0
+		case ">", "gt":
+			keep = found && looseCompare(field, value) > 0
+		case ">=", "ge":
+			keep = found && looseCompare(field, value) >= 0
+		case "contains":
+			keep = found && contains(field, value)
+		case "in":
+			keep = found && contains(value, field)
+		case "prefix":
+			keep = found && strings.HasPrefix(fmt.Sprint(field), fmt.Sprint(value))
+		case "suffix":
+			keep = found && strings.HasSuffix(fmt.Sprint(field), fmt.Sprint(value))
+		case "matches":
+			keep = found && re.MatchString(fmt.Sprint(field))
+		case "exists":
+			want, _ := value.(bool)
+			keep = (found && field != nil) == want
+		default:
+			return nil, fmt.Errorf("filter: unknown operator %q", op)
+		}
+		if keep {
+			out = append(out, item)
+		}
+	}
+	return out, nil
+}
+
+// pluck returns the values at a dotted path of each item, skipping items without it.
+// Items are given as one list or, like sprig's pluck, as separate dicts.
+func pluck(path string, items ...any) []any {
+	if len(items) == 1 {
+		if list := toList(items[0]); list != nil {
+			items = list
+		}
+	}
+	out := []any{}
+	for _, item := range items {
+		if v, ok := lookupPath(item, path); ok {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+// topN returns the n items with the highest value at a dotted path ("" compares the
+// items themselves), highest first. Ties keep their original order.
+func topN(n int, path string, items any) []any {
+	sorted := append([]any(nil), toList(items)...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		vi, _ := lookupPath(sorted[i], path)
+		vj, _ := lookupPath(sorted[j], path)
+		return looseCompare(vi, vj) > 0
+	})
+	if n >= 0 && n < len(sorted) {
+		sorted = sorted[:n]
+	}
+	return sorted
+}
+
+// flatten returns the elements of nested lists as one flat list.
+func flatten(items any) []any {
+	out := []any{}
+	for _, item := range toList(items) {
+		if toList(item) != nil {
+			out = append(out, flatten(item)...)
+			continue
+		}
+		out = append(out, item)
+	}
+	return out
+}
+
+// lookupPath resolves a dotted path ("fields.assignee.displayName", "labels.0") in maps
+// and lists. An empty path returns the value itself.
+func lookupPath(v any, path string) (any, bool) {
+	if path == "" {
+		return v, true
+	}
+	for seg := range strings.SplitSeq(path, ".") {
+		switch t := v.(type) {
+		case map[string]any:
+			next, ok := t[seg]
+			if !ok {
+				return nil, false
+			}
+			v = next
+		default:
+			rv := reflect.ValueOf(v)
+			switch {
+			case !rv.IsValid():
+				return nil, false
+			case rv.Kind() == reflect.Map && rv.Type().Key().Kind() == reflect.String:
+				next := rv.MapIndex(reflect.ValueOf(seg).Convert(rv.Type().Key()))
+				if !next.IsValid() {
+					return nil, false
+				}
+				v = next.Interface()
+			case rv.Kind() == reflect.Slice || rv.Kind() == reflect.Array:
+				i, err := strconv.Atoi(seg)
+				if err != nil || i < 0 || i >= rv.Len() {
+					return nil, false
+				}
+				v = rv.Index(i).Interface()
+			default:
+				return nil, false
+			}
+		}
+	}
+	return v, true
+}
+
+// groupKey returns the value at path as a group key ("" when missing or null).
+func groupKey(item any, path string) string {
+	v, ok := lookupPath(item, path)
+	if !ok || v == nil {
+		return ""
+	}
+	return fmt.Sprint(v)
+}
+
+// toList converts any slice or array into []any; it returns nil for other values.
+func toList(v any) []any {
+	if list, ok := v.([]any); ok {
+		return list
+	}
+	rv := reflect.ValueOf(v)
+	if !rv.IsValid() || (rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array) {
+		return nil
+	}
+	out := make([]any, rv.Len())
+	for i := range out {
+		out[i] = rv.Index(i).Interface()
+	}
+	return out
+}
+
+// toFloat converts numeric values (including json.Number) to float64.
+func toFloat(v any) (float64, bool) {
+	switch n := v.(type) {
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case int32:
+		return float64(n), true
+	case float64:
+		return n, true
+	case float32:
+		return float64(n), true
+	case json.Number:
+		f, err := n.Float64()
+		return f, err == nil
+	}
+	return 0, false
+}
+
+// looseEqual compares numbers numerically and everything else by its string form.
+func looseEqual(a, b any) bool {
+	if fa, ok := toFloat(a); ok {
+		if fb, ok := toFloat(b); ok {
+			return fa == fb
+		}
+	}
+	return fmt.Sprint(a) == fmt.Sprint(b)
+}
+
+// looseCompare orders numbers numerically and everything else by its string form.
+// Missing values (nil) sort before everything else.
+func looseCompare(a, b any) int {
+	switch {
+	case a == nil && b == nil:
+		return 0
+	case a == nil:
+		return -1
+	case b == nil:
+		return 1
+	}
+	if fa, ok := toFloat(a); ok {
+		if fb, ok := toFloat(b); ok {
+			switch {
+			case fa < fb:
+				return -1
+			case fa > fb:
+				return 1
+			}
+			return 0
+		}
+	}
+	return strings.Compare(fmt.Sprint(a), fmt.Sprint(b))
+}
+
+// contains reports whether a list holds elem, or a string contains it as a substring.
+func contains(haystack, elem any) bool {
+	if list := toList(haystack); list != nil {
+		for _, v := range list {
+			if looseEqual(v, elem) {
+				return true
+			}
+		}
+		return false
+	}
+	return strings.Contains(fmt.Sprint(haystack), fmt.Sprint(elem))
+}
+
+// jqQuery runs a jq program on data. Compiled programs are cached, so it is cheap inside range.
+// One result is returned as is, several as a list.
+func jqQuery(expr string, data any) (any, error) {
+	fn, err := transform.JQ(expr)
+	if err != nil {
+		return nil, err
+	}
+	return fn(context.Background(), data)
+}
+
+// jsonPathQuery returns all matches of a JSONPath expression in data as a list.
+// Compiled expressions are cached, so it is cheap inside range.
+func jsonPathQuery(expr string, data any) (any, error) {
+	fn, err := transform.JSONPath(expr)
+	if err != nil {
+		return nil, err
+	}
+	return fn(context.Background(), data)
+}
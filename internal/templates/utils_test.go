@@ -1,6 +1,8 @@
 package templates
 
 import (
+	"bytes"
+	"encoding/json"
 	"html/template"
 	"testing"
 	"time"
@@ -27,6 +29,15 @@ func TestTemplateFuncMap(t *testing.T) {
 		"defaultStr",
 		"typeOf",
 		"sumBy",
+		"jq",
+		"jsonpath",
+		"groupBy",
+		"countBy",
+		"where",
+		"filter",
+		"pluck",
+		"topN",
+		"flatten",
 	} {
 		_, ok := fm[k]
 		assert.Truef(t, ok, "func %q should be present", k)
@@ -344,4 +355,195 @@ func TestSumBy(t *testing.T) {
 		items := []map[string]any{{}, {}}
 		assert.Equal(t, 0.0, sumBy("n", items))
 	})
+
+	t.Run("decoded JSON with dotted path", func(t *testing.T) {
+		t.Parallel()
+		assert.InDelta(t, 16.0, sumBy("fields.points", testIssues()), 1e-9)
+	})
+}
+
+// testIssues returns Jira-like issues as decoded from JSON.
+func testIssues() []any {
+	var issues []any
+	if err := json.Unmarshal([]byte(`[
+		{"key": "A-1", "fields": {"status": {"name": "Done"}, "points": 3, "assignee": {"displayName": "Ann"}, "labels": ["ui", "bug"]}},
+		{"key": "A-2", "fields": {"status": {"name": "Open"}, "points": 5, "assignee": null, "labels": ["api"]}},
+		{"key": "A-3", "fields": {"status": {"name": "Done"}, "points": 8, "assignee": {"displayName": "Bob"}, "labels": []}},
+		{"key": "A-4", "fields": {"status": {"name": "Open"}, "assignee": {"displayName": "Ann"}, "labels": ["bug"]}}
+	]`), &issues); err != nil {
+		panic(err)
+	}
+	return issues
+}
+
+func TestLookupPath(t *testing.T) {
+	t.Parallel()
+
+	issue := testIssues()[0]
+	v, ok := lookupPath(issue, "fields.assignee.displayName")
+	assert.True(t, ok)
+	assert.Equal(t, "Ann", v)
+
+	v, ok = lookupPath(issue, "fields.labels.1")
+	assert.True(t, ok)
+	assert.Equal(t, "bug", v)
+
+	v, ok = lookupPath(map[string]string{"a": "b"}, "a")
+	assert.True(t, ok)
+	assert.Equal(t, "b", v)
+
+	_, ok = lookupPath(issue, "fields.nope.x")
+	assert.False(t, ok)
+	_, ok = lookupPath(issue, "fields.labels.9")
+	assert.False(t, ok)
+
+	v, ok = lookupPath(issue, "")
+	assert.True(t, ok)
+	assert.Equal(t, issue, v)
+}
+
+func TestGroupBy(t *testing.T) {
+	t.Parallel()
+
+	groups := groupBy("fields.status.name", testIssues())
+	require.Len(t, groups, 2)
+	assert.Equal(t, []any{"A-1", "A-3"}, pluck("key", groups["Done"]))
+	assert.Equal(t, []any{"A-2", "A-4"}, pluck("key", groups["Open"]))
+
+	groups = groupBy("fields.assignee.displayName", testIssues())
+	assert.Equal(t, []any{"A-2"}, pluck("key", groups[""]))
+}
+
+func TestCountBy(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, []any{
+		map[string]any{"key": "Ann", "count": 2},
+		map[string]any{"key": "", "count": 1},
+		map[string]any{"key": "Bob", "count": 1},
+	}, countBy("fields.assignee.displayName", testIssues()))
+
+	assert.Empty(t, countBy("x", nil))
+}
+
+func TestFilter(t *testing.T) {
+	t.Parallel()
+
+	keys := func(t *testing.T, path, op string, value any) []any {
+		t.Helper()
+		out, err := filter(path, op, value, testIssues())
+		require.NoError(t, err)
+		return pluck("key", out)
+	}
+
+	assert.Equal(t, []any{"A-1", "A-3"}, keys(t, "fields.status.name", "==", "Done"))
+	assert.Equal(t, []any{"A-2", "A-4"}, keys(t, "fields.status.name", "!=", "Done"))
+	assert.Equal(t, []any{"A-2", "A-3"}, keys(t, "fields.points", ">=", 5))
+	assert.Equal(t, []any{"A-1"}, keys(t, "fields.points", "<", 5))
+	assert.Equal(t, []any{"A-3"}, keys(t, "fields.points", "gt", "5"))
+	assert.Equal(t, []any{"A-1", "A-4"}, keys(t, "fields.labels", "contains", "bug"))
+	assert.Equal(t, []any{"A-1", "A-2"}, keys(t, "key", "in", []any{"A-1", "A-2"}))
+	assert.Equal(t, []any{"A-1", "A-4"}, keys(t, "fields.assignee.displayName", "prefix", "A"))
+	assert.Equal(t, []any{"A-3"}, keys(t, "key", "suffix", "3"))
+	assert.Equal(t, []any{"A-2", "A-3"}, keys(t, "key", "matches", "^A-[23]$"))
+	assert.Equal(t, []any{"A-1", "A-3", "A-4"}, keys(t, "fields.assignee", "exists", true))
+	assert.Equal(t, []any{"A-2"}, keys(t, "fields.assignee", "exists", false))
+
+	out, err := where("fields.status.name", "Open", testIssues())
+	require.NoError(t, err)
+	assert.Equal(t, []any{"A-2", "A-4"}, pluck("key", out))
+
+	_, err = filter("key", "~~", "x", testIssues())
+	require.EqualError(t, err, `filter: unknown operator "~~"`)
+	_, err = filter("key", "matches", "(", testIssues())
+	require.Error(t, err)
+}
+
+func TestPluck(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, []any{"Ann", "Bob", "Ann"}, pluck("fields.assignee.displayName", testIssues()))
+	assert.Equal(t, []any{"x"}, pluck("a", map[string]any{"a": "x"}, map[string]any{"b": "y"}), "sprig-style dicts")
+	assert.Empty(t, pluck("a", nil))
+}
+
+func TestTopN(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, []any{"A-3", "A-2"}, pluck("key", topN(2, "fields.points", testIssues())))
+	assert.Len(t, topN(10, "fields.points", testIssues()), 4)
+	assert.Equal(t, []any{3.0, 2.0}, topN(2, "", []any{1.0, 3.0, 2.0}))
+}
+
+func TestFlatten(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, []any{"ui", "bug", "api", "bug"}, flatten(pluck("fields.labels", testIssues())))
+	assert.Equal(t, []any{1, 2, 3, "x"}, flatten([]any{1, []any{2, []int{3}}, "x"}))
+}
+
+func TestCollectionFuncsInTemplates(t *testing.T) {
+	t.Parallel()
+
+	tmpl := template.Must(template.New("t").Funcs(TemplateFuncMap()).Parse(
+		`{{ range countBy "fields.assignee.displayName" .Data | topN 2 "count" }}{{ .key | default "Unassigned" }}={{ .count }} {{ end }}` +
+			`{{ len (where "fields.status.name" "Done" .Data) }} {{ sumBy "fields.points" .Data }}`,
+	))
+	var buf bytes.Buffer
+	require.NoError(t, tmpl.Execute(&buf, map[string]any{"Data": testIssues()}))
+	assert.Equal(t, "Ann=2 Unassigned=1 2 16", buf.String())
+}
+
+func TestQueryFuncs(t *testing.T) {
+	t.Parallel()
+
+	data := map[string]any{"issues": []any{
+		map[string]any{"key": "A-1", "fields": map[string]any{"status": map[string]any{"name": "Done"}}},
+		map[string]any{"key": "A-2", "fields": map[string]any{"status": map[string]any{"name": "Open"}}},
+		map[string]any{"key": "A-3", "fields": map[string]any{"status": map[string]any{"name": "Done"}}},
+	}}
+
+	render := func(t *testing.T, src string) (string, error) {
+		t.Helper()
+		tmpl := template.Must(template.New("t").Funcs(TemplateFuncMap()).Parse(src))
+		var buf bytes.Buffer
+		err := tmpl.Execute(&buf, map[string]any{"Data": data})
+		return buf.String(), err
+	}
+
+	t.Run("jq", func(t *testing.T) {
+		t.Parallel()
+
+		out, err := render(t, `{{ range jq "[.issues[] | select(.fields.status.name == \"Done\") | .key]" .Data }}{{ . }} {{ end }}`)
+		require.NoError(t, err)
+		assert.Equal(t, "A-1 A-3 ", out)
+
+		out, err = render(t, `{{ jq ".issues | length" .Data }}`)
+		require.NoError(t, err)
+		assert.Equal(t, "3", out)
+	})
+
+	t.Run("jsonpath", func(t *testing.T) {
+		t.Parallel()
+
+		out, err := render(t, `{{ range .Data.issues }}{{ jsonpath "$.fields.status.name" . | first }},{{ end }}`)
+		require.NoError(t, err)
+		assert.Equal(t, "Done,Open,Done,", out)
+
+		out, err = render(t, `{{ len (jsonpath "$.issues[?(@.fields.status.name == 'Done')]" .Data) }}`)
+		require.NoError(t, err)
+		assert.Equal(t, "2", out)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := render(t, `{{ jq ".issues[" .Data }}`)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "jq: ")
+
+		_, err = render(t, `{{ jsonpath "$.issues[" .Data }}`)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "jsonpath: ")
+	})
 }
@@ -0,0 +1,190 @@
+package timeutil
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+)
+
+// unit indexes the words of a Locale.
+type unit int
+
+const (
+	second unit = iota
+	minute
+	hour
+	day
+	week
+	month
+	year
+)
+
+// unitWords are the singular and plural forms of a unit, standalone ("3 days")
+// and inside relative phrases ("vor 3 Tagen"), which differ in some languages.
+type unitWords struct {
+	one, many       string
+	relOne, relMany string
+}
+
+// Locale holds the words used to render times in a language.
+type Locale struct {
+	Name    string
+	units   [7]unitWords
+	ago     string // format of a past time, e.g. "%s ago"
+	in      string // format of a future time, e.g. "in %s"
+	justNow string
+	months  [12]string
+	days    [7]string // indexed by time.Weekday
+}
+
+// words returns the same unit words for standalone and relative use.
+func words(one, many string) unitWords {
+	return unitWords{one: one, many: many, relOne: one, relMany: many}
+}
+
+var locales = map[string]*Locale{
+	"en": {
+		Name: "en",
+		units: [7]unitWords{
+			words("second", "seconds"), words("minute", "minutes"), words("hour", "hours"),
+			words("day", "days"), words("week", "weeks"), words("month", "months"), words("year", "years"),
+		},
+		ago: "%s ago", in: "in %s", justNow: "just now",
+		months: [12]string{"January", "February", "March", "April", "May", "June", "July", "August", "September", "October", "November", "December"},
+		days:   [7]string{"Sunday", "Monday", "Tuesday", "Wednesday", "Thursday", "Friday", "Saturday"},
+	},
+	"de": {
+		Name: "de",
+		units: [7]unitWords{
+			words("Sekunde", "Sekunden"), words("Minute", "Minuten"), words("Stunde", "Stunden"),
+			{one: "Tag", many: "Tage", relOne: "Tag", relMany: "Tagen"},
+			words("Woche", "Wochen"),
+			{one: "Monat", many: "Monate", relOne: "Monat", relMany: "Monaten"},
+			{one: "Jahr", many: "Jahre", relOne: "Jahr", relMany: "Jahren"},
+		},
+		ago: "vor %s", in: "in %s", justNow: "gerade eben",
+		months: [12]string{"Januar", "Februar", "März", "April", "Mai", "Juni", "Juli", "August", "September", "Oktober", "November", "Dezember"},
+		days:   [7]string{"Sonntag", "Montag", "Dienstag", "Mittwoch", "Donnerstag", "Freitag", "Samstag"},
+	},
+	"fr": {
+		Name: "fr",
+		units: [7]unitWords{
+			words("seconde", "secondes"), words("minute", "minutes"), words("heure", "heures"),
+			words("jour", "jours"), words("semaine", "semaines"), words("mois", "mois"), words("an", "ans"),
+		},
+		ago: "il y a %s", in: "dans %s", justNow: "à l'instant",
+		months: [12]string{"janvier", "février", "mars", "avril", "mai", "juin", "juillet", "août", "septembre", "octobre", "novembre", "décembre"},
+		days:   [7]string{"dimanche", "lundi", "mardi", "mercredi", "jeudi", "vendredi", "samedi"},
+	},
+	"it": {
+		Name: "it",
+		units: [7]unitWords{
+			words("secondo", "secondi"), words("minuto", "minuti"), words("ora", "ore"),
+			words("giorno", "giorni"), words("settimana", "settimane"), words("mese", "mesi"), words("anno", "anni"),
+		},
+		ago: "%s fa", in: "tra %s", justNow: "adesso",
+		months: [12]string{"gennaio", "febbraio", "marzo", "aprile", "maggio", "giugno", "luglio", "agosto", "settembre", "ottobre", "novembre", "dicembre"},
+		days:   [7]string{"domenica", "lunedì", "martedì", "mercoledì", "giovedì", "venerdì", "sabato"},
+	},
+}
+
+// DefaultLocale is used when no locale is configured.
+var DefaultLocale = locales["en"]
+
+// LookupLocale returns the locale with the given name ("en", "de", "fr", "it").
+// A region suffix ("de-CH", "de_CH") is ignored.
+func LookupLocale(name string) (*Locale, bool) {
+	lang, _, _ := strings.Cut(strings.ReplaceAll(name, "_", "-"), "-")
+	l, ok := locales[strings.ToLower(lang)]
+	return l, ok
+}
+
+// Locales returns the names of the supported locales.
+func Locales() []string {
+	return slices.Sorted(maps.Keys(locales))
+}
+
+// Humanize renders a duration in its largest whole unit, e.g. "3 days".
+func (l *Locale) Humanize(d time.Duration) string {
+	n, u := split(d)
+	w := l.units[u]
+	if n == 1 {
+		return fmt.Sprintf("%d %s", n, w.one)
+	}
+	return fmt.Sprintf("%d %s", n, w.many)
+}
+
+// Ago renders t relative to now, e.g. "3 days ago", "in 2 hours" or "just now".
+func (l *Locale) Ago(t, now time.Time) string {
+	d := now.Sub(t)
+	if d.Abs() < time.Minute {
+		return l.justNow
+	}
+	n, u := split(d)
+	w := l.units[u]
+	amount := fmt.Sprintf("%d %s", n, w.relMany)
+	if n == 1 {
+		amount = fmt.Sprintf("%d %s", n, w.relOne)
+	}
+	if d < 0 {
+		return fmt.Sprintf(l.in, amount)
+	}
+	return fmt.Sprintf(l.ago, amount)
+}
+
+// Format formats t like time.Format with month and weekday names
+// (January, Jan, Monday, Mon) in the locale's language.
+func (l *Locale) Format(t time.Time, layout string) string {
+	// Swap the name tokens for placeholders Go's formatter leaves alone (no digits or
+	// layout letters), longest first.
+	names := []struct{ token, value string }{
+		{"January", l.months[t.Month()-1]},
+		{"Monday", l.days[t.Weekday()]},
+		{"Jan", short(l.months[t.Month()-1])},
+		{"Mon", short(l.days[t.Weekday()])},
+	}
+	placeholder := func(i int) string { return "\x00" + string(rune('a'+i)) + "\x00" }
+	for i, n := range names {
+		layout = strings.ReplaceAll(layout, n.token, placeholder(i))
+	}
+	out := t.Format(layout)
+	for i, n := range names {
+		out = strings.ReplaceAll(out, placeholder(i), n.value)
+	}
+	return out
+}
+
+// short abbreviates a month or weekday name to its first three letters.
+func short(name string) string {
+	r := []rune(name)
+	return string(r[:min(3, len(r))])
+}
+
+// split returns the largest whole unit of |d| and its count.
+func split(d time.Duration) (int64, unit) {
+	const (
+		dayDur   = 24 * time.Hour
+		weekDur  = 7 * dayDur
+		monthDur = 30 * dayDur
+		yearDur  = 365 * dayDur
+	)
+	d = d.Abs()
+	switch {
+	case d < time.Minute:
+		return int64(d / time.Second), second
+	case d < time.Hour:
+		return int64(d / time.Minute), minute
+	case d < dayDur:
+		return int64(d / time.Hour), hour
+	case d < weekDur:
+		return int64(d / dayDur), day
+	case d < monthDur:
+		return int64(d / weekDur), week
+	case d < yearDur:
+		return int64(d / monthDur), month
+	default:
+		return int64(d / yearDur), year
+	}
+}
@@ -0,0 +1,144 @@
+package timeutil
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// zonedLayouts are timestamp layouts that carry their own offset.
+var zonedLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05.000-0700", // Jira
+	"2006-01-02T15:04:05-0700",
+	time.RFC1123Z,
+	time.RFC1123,
+}
+
+// localLayouts are timestamp layouts without an offset; they are read in the target location.
+var localLayouts = []string{
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02",
+}
+
+// epochMillisThreshold separates epoch seconds from epoch milliseconds: 1e11 seconds is in
+// the year 5138, while 1e11 milliseconds is in 1973.
+const epochMillisThreshold = 1e11
+
+// Parse converts a time.Time, a timestamp string (RFC 3339, Jira, date-only, ...) or a
+// Unix epoch (milliseconds, or seconds for values below 1e11) into a time in loc.
+func Parse(v any, loc *time.Location) (time.Time, error) {
+	switch t := v.(type) {
+	case time.Time:
+		return t.In(loc), nil
+	case *time.Time:
+		if t == nil {
+			return time.Time{}, fmt.Errorf("parse time: nil time")
+		}
+		return t.In(loc), nil
+	case string:
+		return parseString(t, loc)
+	}
+	if n, ok := number(v); ok {
+		return epoch(n, loc), nil
+	}
+	return time.Time{}, fmt.Errorf("parse time: unsupported type %T", v)
+}
+
+// parseString parses a timestamp or numeric epoch string.
+func parseString(s string, loc *time.Location) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	if n, err := strconv.ParseFloat(s, 64); err == nil {
+		return epoch(n, loc), nil
+	}
+	for _, layout := range zonedLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t.In(loc), nil
+		}
+	}
+	for _, layout := range localLayouts {
+		if t, err := time.ParseInLocation(layout, s, loc); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("parse time: unrecognized time %q", s)
+}
+
+// epoch converts Unix seconds or milliseconds into a time in loc.
+func epoch(n float64, loc *time.Location) time.Time {
+	if math.Abs(n) >= epochMillisThreshold {
+		return time.UnixMilli(int64(n)).In(loc)
+	}
+	sec, frac := math.Modf(n)
+	return time.Unix(int64(sec), int64(frac*1e9)).In(loc)
+}
+
+// ParseDuration converts a time.Duration, a Go duration string ("90m") or a number of seconds.
+func ParseDuration(v any) (time.Duration, error) {
+	switch d := v.(type) {
+	case time.Duration:
+		return d, nil
+	case string:
+		if n, err := strconv.ParseFloat(strings.TrimSpace(d), 64); err == nil {
+			return time.Duration(n * float64(time.Second)), nil
+		}
+		parsed, err := time.ParseDuration(strings.TrimSpace(d))
+		if err != nil {
+			return 0, fmt.Errorf("parse duration: %w", err)
+		}
+		return parsed, nil
+	}
+	if n, ok := number(v); ok {
+		return time.Duration(n * float64(time.Second)), nil
+	}
+	return 0, fmt.Errorf("parse duration: unsupported type %T", v)
+}
+
+// BusinessDaysBetween counts the weekdays (Monday to Friday) from the day of from up to,
+// but not including, the day of to, in from's location. It is negative when to is earlier.
+func BusinessDaysBetween(from, to time.Time) int {
+	to = to.In(from.Location())
+	start := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, time.UTC)
+	end := time.Date(to.Year(), to.Month(), to.Day(), 0, 0, 0, 0, time.UTC)
+	if end.Before(start) {
+		return -BusinessDaysBetween(to, from)
+	}
+
+	days := int(end.Sub(start).Hours() / 24)
+	n := days / 7 * 5
+	for d := start.AddDate(0, 0, days/7*7); d.Before(end); d = d.AddDate(0, 0, 1) {
+		if wd := d.Weekday(); wd != time.Saturday && wd != time.Sunday {
+			n++
+		}
+	}
+	return n
+}
+
+// number converts numeric values (including json.Number) to float64.
+func number(v any) (float64, bool) {
+	switch n := v.(type) {
+	case int:
+		return float64(n), true
+	case int32:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case uint:
+		return float64(n), true
+	case uint64:
+		return float64(n), true
+	case float32:
+		return float64(n), true
+	case float64:
+		return n, true
+	case json.Number:
+		f, err := n.Float64()
+		return f, err == nil
+	}
+	return 0, false
+}
@@ -0,0 +1,124 @@
+package timeutil
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+
+	zurich, err := time.LoadLocation("Europe/Zurich")
+	require.NoError(t, err)
+	want := time.Date(2025, 3, 10, 13, 0, 0, 0, zurich) // 12:00 UTC
+
+	tests := map[string]any{
+		"RFC 3339":            "2025-03-10T12:00:00Z",
+		"RFC 3339 offset":     "2025-03-10T14:00:00+02:00",
+		"Jira":                "2025-03-10T12:00:00.000+0000",
+		"Jira without millis": "2025-03-10T13:00:00+0100",
+		"local layout":        "2025-03-10 13:00:00",
+		"epoch seconds":       int64(1741608000),
+		"epoch millis":        1741608000000.0,
+		"epoch millis string": "1741608000000",
+		"json.Number":         json.Number("1741608000"),
+		"time.Time":           time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC),
+	}
+	for name, in := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			got, err := Parse(in, zurich)
+			require.NoError(t, err)
+			assert.True(t, want.Equal(got), "got %s", got)
+			assert.Equal(t, zurich, got.Location())
+		})
+	}
+
+	t.Run("date only", func(t *testing.T) {
+		t.Parallel()
+		got, err := Parse("2025-03-10", zurich)
+		require.NoError(t, err)
+		assert.Equal(t, time.Date(2025, 3, 10, 0, 0, 0, 0, zurich), got)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		t.Parallel()
+		_, err := Parse("yesterday", zurich)
+		require.EqualError(t, err, `parse time: unrecognized time "yesterday"`)
+		_, err = Parse(true, zurich)
+		require.EqualError(t, err, "parse time: unsupported type bool")
+	})
+}
+
+func TestParseDuration(t *testing.T) {
+	t.Parallel()
+
+	for in, want := range map[any]time.Duration{
+		90 * time.Second: 90 * time.Second,
+		"1h30m":          90 * time.Minute,
+		"3600":           time.Hour,
+		7200:             2 * time.Hour,
+		1.5:              1500 * time.Millisecond,
+	} {
+		got, err := ParseDuration(in)
+		require.NoError(t, err)
+		assert.Equal(t, want, got, in)
+	}
+
+	_, err := ParseDuration("soon")
+	require.Error(t, err)
+}
+
+func TestLocale(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC)
+	en, _ := LookupLocale("en")
+	de, _ := LookupLocale("de_CH")
+	fr, _ := LookupLocale("FR")
+
+	assert.Equal(t, "3 days", en.Humanize(3*24*time.Hour+5*time.Hour))
+	assert.Equal(t, "1 hour", en.Humanize(-90*time.Minute))
+	assert.Equal(t, "0 seconds", en.Humanize(0))
+	assert.Equal(t, "2 weeks", en.Humanize(15*24*time.Hour))
+	assert.Equal(t, "1 year", en.Humanize(400*24*time.Hour))
+	assert.Equal(t, "3 Tage", de.Humanize(3*24*time.Hour))
+
+	assert.Equal(t, "3 days ago", en.Ago(now.Add(-72*time.Hour), now))
+	assert.Equal(t, "in 2 hours", en.Ago(now.Add(2*time.Hour), now))
+	assert.Equal(t, "just now", en.Ago(now.Add(-30*time.Second), now))
+	assert.Equal(t, "vor 3 Tagen", de.Ago(now.Add(-72*time.Hour), now))
+	assert.Equal(t, "vor 1 Monat", de.Ago(now.AddDate(0, 0, -31), now))
+	assert.Equal(t, "il y a 5 minutes", fr.Ago(now.Add(-5*time.Minute), now))
+
+	assert.Equal(t, "Monday, 10 March 2025", en.Format(now, "Monday, 2 January 2006"))
+	assert.Equal(t, "Montag, 10. März 2025", de.Format(now, "Monday, 2. January 2006"))
+	assert.Equal(t, "lun 10 mar", fr.Format(now, "Mon 2 Jan"))
+
+	_, ok := LookupLocale("xx")
+	assert.False(t, ok)
+	assert.Equal(t, []string{"de", "en", "fr", "it"}, Locales())
+}
+
+func TestBusinessDaysBetween(t *testing.T) {
+	t.Parallel()
+
+	mon := time.Date(2025, 3, 10, 9, 0, 0, 0, time.UTC)
+	tests := map[string]struct {
+		to   time.Time
+		want int
+	}{
+		"same day":          {mon.Add(5 * time.Hour), 0},
+		"to friday":         {mon.AddDate(0, 0, 4), 4},
+		"to next monday":    {mon.AddDate(0, 0, 7), 5},
+		"over two weekends": {mon.AddDate(0, 0, 16), 12},
+		"backwards":         {mon.AddDate(0, 0, -3), -1},
+	}
+	for name, tt := range tests {
+		assert.Equal(t, tt.want, BusinessDaysBetween(mon, tt.to), name)
+	}
+}
@@ -0,0 +1,602 @@
+package transform
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// step maps the nodes matched so far to the nodes matched by one path segment.
+type step func(nodes []any) []any
+
+// JSONPath compiles a JSONPath expression (Goessner style) and returns all matches as a list.
+// Supported: $, .name, ['name'], [n], [-n], [a,b], [start:end:step], * and ..name (recursive
+// descent), and filters such as [?(@.status == 'Done')], [?(@.points > 3 && @.assignee)]
+// with ==, !=, <, <=, >, >=, =~ (regular expression), && and ||.
+func JSONPath(expr string) (Func, error) {
+	if fn, ok := compiled.Get("jsonpath:" + expr); ok {
+		return fn, nil
+	}
+	steps, err := parseJSONPath(expr)
+	if err != nil {
+		return nil, fmt.Errorf("jsonpath: %w", err)
+	}
+
+	fn := Func(func(_ context.Context, in any) (any, error) {
+		v, err := normalize(in)
+		if err != nil {
+			return nil, err
+		}
+		nodes := []any{v}
+		for _, s := range steps {
+			nodes = s(nodes)
+		}
+		if nodes == nil {
+			nodes = []any{}
+		}
+		return nodes, nil
+	})
+	compiled.Add("jsonpath:"+expr, fn)
+	return fn, nil
+}
+
+// parseJSONPath splits a path into steps. Errors report the offset in expr they occurred at.
+func parseJSONPath(expr string) ([]step, error) {
+	s := strings.TrimSpace(expr)
+	// s is always a suffix of the trimmed expression (plus a prepended '.'), so the
+	// offset of what is left to parse is measured from the end.
+	end := len(strings.TrimRightFunc(expr, unicode.IsSpace))
+	pos := func() int { return end - len(s) }
+	fail := func(format string, args ...any) error {
+		return fmt.Errorf("%q: at offset %d: %s", expr, pos(), fmt.Sprintf(format, args...))
+	}
+
+	switch {
+	case strings.HasPrefix(s, "$"), strings.HasPrefix(s, "@"):
+		s = s[1:]
+	case s == "", strings.HasPrefix(s, "["), strings.HasPrefix(s, "."):
+	default:
+		s = "." + s // allow "issues[0].key" without the leading "$."
+	}
+
+	var steps []step
+	for s != "" {
+		if strings.HasPrefix(s, "..") {
+			s = s[2:]
+			steps = append(steps, descendants)
+			if s == "" {
+				return nil, fail("missing selector after '..'")
+			}
+			if s[0] != '[' {
+				s = "." + s
+			}
+		}
+
+		switch s[0] {
+		case '.':
+			s = s[1:]
+			n := strings.IndexAny(s, ".[")
+			if n < 0 {
+				n = len(s)
+			}
+			name := s[:n]
+			switch {
+			case name == "":
+				return nil, fail("empty name")
+			case strings.Contains(name, "]"):
+				return nil, fail("unexpected ']' in name %q", name)
+			case name == "*":
+				steps = append(steps, wildcard)
+			default:
+				steps = append(steps, childNames([]string{name}))
+			}
+			s = s[n:]
+		case '[':
+			n, err := closingBracket(s)
+			if err != nil {
+				return nil, fail("%v", err)
+			}
+			st, err := parseBracket(strings.TrimSpace(s[1:n]))
+			if err != nil {
+				return nil, fail("%v", err)
+			}
+			steps = append(steps, st)
+			s = s[n+1:]
+		default:
+			return nil, fail("unexpected %q", s)
+		}
+	}
+	return steps, nil
+}
+
+// closingBracket returns the index of the ']' closing the '[' at s[0], skipping quoted
+// strings and nested brackets.
+func closingBracket(s string) (int, error) {
+	depth := 0
+	var quote byte
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case quote != 0:
+			if c == '\\' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"':
+			quote = c
+		case c == '[' || c == '(':
+			depth++
+		case c == ']' || c == ')':
+			depth--
+			if depth == 0 {
+				if c != ']' {
+					return 0, fmt.Errorf("unbalanced %q", c)
+				}
+				return i, nil
+			}
+		}
+	}
+	return 0, fmt.Errorf("missing ']'")
+}
+
+// parseBracket parses the content of a [...] selector.
+func parseBracket(in string) (step, error) {
+	switch {
+	case in == "*":
+		return wildcard, nil
+	case strings.HasPrefix(in, "?"):
+		f := strings.TrimSpace(in[1:])
+		if strings.HasPrefix(f, "(") && strings.HasSuffix(f, ")") {
+			f = f[1 : len(f)-1]
+		}
+		pred, err := parseFilter(f)
+		if err != nil {
+			return nil, err
+		}
+		return filter(pred), nil
+	}
+
+	parts := splitTopLevel(in, ",")
+	if len(parts) == 1 && strings.Contains(in, ":") && !isQuoted(in) {
+		return parseSlice(in)
+	}
+
+	var (
+		names []string
+		idxs  []int
+	)
+	for _, p := range parts {
+		p = strings.TrimSpace(p)
+		if isQuoted(p) {
+			name, err := unquote(p)
+			if err != nil {
+				return nil, err
+			}
+			names = append(names, name)
+			continue
+		}
+		n, err := strconv.Atoi(p)
+		if err != nil {
+			return nil, fmt.Errorf("invalid selector %q", p)
+		}
+		idxs = append(idxs, n)
+	}
+	if len(names) > 0 && len(idxs) > 0 {
+		return nil, fmt.Errorf("cannot mix names and indexes in %q", in)
+	}
+	if len(names) > 0 {
+		return childNames(names), nil
+	}
+	return childIndexes(idxs), nil
+}
+
+// parseSlice parses start:end[:step].
+func parseSlice(in string) (step, error) {
+	parts := strings.Split(in, ":")
+	if len(parts) > 3 {
+		return nil, fmt.Errorf("invalid slice %q", in)
+	}
+	var bounds [3]*int
+	for i, p := range parts {
+		p = strings.TrimSpace(p)
+		if p == "" {
+			continue
+		}
+		n, err := strconv.Atoi(p)
+		if err != nil {
+			return nil, fmt.Errorf("invalid slice %q", in)
+		}
+		bounds[i] = &n
+	}
+	stride := 1
+	if bounds[2] != nil {
+		stride = *bounds[2]
+	}
+	if stride <= 0 {
+		return nil, fmt.Errorf("slice step must be positive in %q", in)
+	}
+
+	return func(nodes []any) []any {
+		var out []any
+		for _, n := range nodes {
+			list, ok := n.([]any)
+			if !ok {
+				continue
+			}
+			start, end := 0, len(list)
+			if bounds[0] != nil {
+				start = clampIndex(*bounds[0], len(list))
+			}
+			if bounds[1] != nil {
+				end = clampIndex(*bounds[1], len(list))
+			}
+			// A step beyond the list selects one element; clamping it keeps i from overflowing.
+			step := min(stride, len(list))
+			for i := start; i < end; i += step {
+				out = append(out, list[i])
+			}
+		}
+		return out
+	}, nil
+}
+
+// clampIndex resolves negative indexes from the end and clamps to [0, n].
+func clampIndex(i, n int) int {
+	if i < 0 {
+		i += n
+	}
+	return max(0, min(i, n))
+}
+
+// childNames selects the named members of objects.
+func childNames(names []string) step {
+	return func(nodes []any) []any {
+		var out []any
+		for _, n := range nodes {
+			m, ok := n.(map[string]any)
+			if !ok {
+				continue
+			}
+			for _, name := range names {
+				if v, ok := m[name]; ok {
+					out = append(out, v)
+				}
+			}
+		}
+		return out
+	}
+}
+
+// childIndexes selects elements of arrays; negative indexes count from the end.
+func childIndexes(idxs []int) step {
+	return func(nodes []any) []any {
+		var out []any
+		for _, n := range nodes {
+			list, ok := n.([]any)
+			if !ok {
+				continue
+			}
+			for _, i := range idxs {
+				if i < 0 {
+					i += len(list)
+				}
+				if i >= 0 && i < len(list) {
+					out = append(out, list[i])
+				}
+			}
+		}
+		return out
+	}
+}
+
+// wildcard selects all elements of arrays and all member values of objects (sorted by key).
+func wildcard(nodes []any) []any {
+	var out []any
+	for _, n := range nodes {
+		out = append(out, children(n)...)
+	}
+	return out
+}
+
+// descendants returns every node and, recursively, all of its children.
+func descendants(nodes []any) []any {
+	var out []any
+	var walk func(v any)
+	walk = func(v any) {
+		out = append(out, v)
+		for _, c := range children(v) {
+			walk(c)
+		}
+	}
+	for _, n := range nodes {
+		walk(n)
+	}
+	return out
+}
+
+// children returns the elements of an array or the values of an object sorted by key.
+func children(v any) []any {
+	switch t := v.(type) {
+	case []any:
+		return t
+	case map[string]any:
+		keys := make([]string, 0, len(t))
+		for k := range t {
+			keys = append(keys, k)
+		}
+		slices.Sort(keys)
+		out := make([]any, 0, len(keys))
+		for _, k := range keys {
+			out = append(out, t[k])
+		}
+		return out
+	}
+	return nil
+}
+
+// filter keeps the children of each node that satisfy pred.
+func filter(pred func(any) bool) step {
+	return func(nodes []any) []any {
+		var out []any
+		for _, n := range nodes {
+			for _, c := range children(n) {
+				if pred(c) {
+					out = append(out, c)
+				}
+			}
+		}
+		return out
+	}
+}
+
+// filterOps are the comparison operators, longest first so "<=" wins over "<".
+var filterOps = []string{"==", "!=", "<=", ">=", "=~", "<", ">"}
+
+// parseFilter parses a filter expression of comparisons joined by && and ||.
+func parseFilter(in string) (func(any) bool, error) {
+	if ors := splitTopLevel(in, "||"); len(ors) > 1 {
+		preds, err := parseAll(ors)
+		if err != nil {
+			return nil, err
+		}
+		return func(v any) bool {
+			return slices.ContainsFunc(preds, func(p func(any) bool) bool { return p(v) })
+		}, nil
+	}
+	if ands := splitTopLevel(in, "&&"); len(ands) > 1 {
+		preds, err := parseAll(ands)
+		if err != nil {
+			return nil, err
+		}
+		return func(v any) bool {
+			for _, p := range preds {
+				if !p(v) {
+					return false
+				}
+			}
+			return true
+		}, nil
+	}
+
+	in = strings.TrimSpace(in)
+	if strings.HasPrefix(in, "(") && strings.HasSuffix(in, ")") {
+		return parseFilter(in[1 : len(in)-1])
+	}
+	if rest, ok := strings.CutPrefix(in, "!"); ok {
+		pred, err := parseFilter(rest)
+		if err != nil {
+			return nil, err
+		}
+		return func(v any) bool { return !pred(v) }, nil
+	}
+
+	for _, op := range filterOps {
+		parts := splitTopLevel(in, op)
+		if len(parts) != 2 {
+			continue
+		}
+		left, err := parseOperand(parts[0])
+		if err != nil {
+			return nil, err
+		}
+		if op == "=~" {
+			pattern, err := unquote(strings.TrimSpace(parts[1]))
+			if err != nil {
+				return nil, fmt.Errorf("=~ expects a quoted regular expression: %w", err)
+			}
+			re, err := regexp.Compile(pattern)
+			if err != nil {
+				return nil, err
+			}
+			return func(v any) bool {
+				l, ok := left(v)
+				s, isStr := l.(string)
+				return ok && isStr && re.MatchString(s)
+			}, nil
+		}
+		right, err := parseOperand(parts[1])
+		if err != nil {
+			return nil, err
+		}
+		return func(v any) bool {
+			l, lok := left(v)
+			r, rok := right(v)
+			return lok && rok && compare(l, op, r)
+		}, nil
+	}
+
+	// A bare path tests for existence.
+	operand, err := parseOperand(in)
+	if err != nil {
+		return nil, err
+	}
+	return func(v any) bool {
+		_, ok := operand(v)
+		return ok
+	}, nil
+}
+
+// parseAll parses each filter expression.
+func parseAll(exprs []string) ([]func(any) bool, error) {
+	preds := make([]func(any) bool, 0, len(exprs))
+	for _, e := range exprs {
+		p, err := parseFilter(e)
+		if err != nil {
+			return nil, err
+		}
+		preds = append(preds, p)
+	}
+	return preds, nil
+}
+
+// parseOperand parses a relative path (@...) or a literal. The returned function reports
+// false when the path matches nothing.
+func parseOperand(in string) (func(any) (any, bool), error) {
+	in = strings.TrimSpace(in)
+	switch {
+	case strings.HasPrefix(in, "@"), strings.HasPrefix(in, "$"):
+		steps, err := parseJSONPath(in)
+		if err != nil {
+			return nil, err
+		}
+		return func(v any) (any, bool) {
+			nodes := []any{v}
+			for _, s := range steps {
+				nodes = s(nodes)
+			}
+			if len(nodes) == 0 {
+				return nil, false
+			}
+			return nodes[0], true
+		}, nil
+	case isQuoted(in):
+		s, err := unquote(in)
+		if err != nil {
+			return nil, err
+		}
+		return func(any) (any, bool) { return s, true }, nil
+	case in == "true", in == "false":
+		b := in == "true"
+		return func(any) (any, bool) { return b, true }, nil
+	case in == "null":
+		return func(any) (any, bool) { return nil, true }, nil
+	}
+	f, err := strconv.ParseFloat(in, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid operand %q", in)
+	}
+	return func(any) (any, bool) { return f, true }, nil
+}
+
+// compare applies a comparison operator; ordering is defined for numbers and strings.
+func compare(l any, op string, r any) bool {
+	switch op {
+	case "==":
+		return reflect.DeepEqual(l, r)
+	case "!=":
+		return !reflect.DeepEqual(l, r)
+	}
+
+	var c int
+	switch lv := l.(type) {
+	case float64:
+		rv, ok := r.(float64)
+		if !ok {
+			return false
+		}
+		c = cmpFloat(lv, rv)
+	case string:
+		rv, ok := r.(string)
+		if !ok {
+			return false
+		}
+		c = strings.Compare(lv, rv)
+	default:
+		return false
+	}
+	switch op {
+	case "<":
+		return c < 0
+	case "<=":
+		return c <= 0
+	case ">":
+		return c > 0
+	case ">=":
+		return c >= 0
+	}
+	return false
+}
+
+// cmpFloat compares two floats.
+func cmpFloat(a, b float64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+// splitTopLevel splits s on sep outside quotes, brackets and parentheses.
+func splitTopLevel(s, sep string) []string {
+	var (
+		parts []string
+		depth int
+		quote byte
+		start int
+	)
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case quote != 0:
+			if c == '\\' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"':
+			quote = c
+		case c == '[' || c == '(':
+			depth++
+		case c == ']' || c == ')':
+			depth--
+		case depth == 0 && strings.HasPrefix(s[i:], sep):
+			// "<" and ">" must not split "<=" and ">=".
+			if (sep == "<" || sep == ">") && i+1 < len(s) && s[i+1] == '=' {
+				continue
+			}
+			parts = append(parts, s[start:i])
+			i += len(sep) - 1
+			start = i + 1
+		}
+	}
+	return append(parts, s[start:])
+}
+
+// isQuoted reports whether s is a single- or double-quoted string.
+func isQuoted(s string) bool {
+	return len(s) >= 2 && (s[0] == '\'' || s[0] == '"') && s[len(s)-1] == s[0]
+}
+
+// unquote strips the quotes of a quoted string and resolves \\ and escaped quotes.
+func unquote(s string) (string, error) {
+	if !isQuoted(s) {
+		return "", fmt.Errorf("expected a quoted string, got %q", s)
+	}
+	body := s[1 : len(s)-1]
+	var b strings.Builder
+	for i := 0; i < len(body); i++ {
+		if body[i] == '\\' && i+1 < len(body) {
+			i++
+		}
+		b.WriteByte(body[i])
+	}
+	return b.String(), nil
+}
@@ -0,0 +1,290 @@
+package transform
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJSONPath(t *testing.T) {
+	t.Parallel()
+
+	var data any
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"total": 4,
+		"issues": [
+			{"key": "A-1", "fields": {"status": {"name": "Done"}, "points": 3, "assignee": {"displayName": "Ann"}}},
+			{"key": "A-2", "fields": {"status": {"name": "Open"}, "points": 5, "assignee": null}},
+			{"key": "A-3", "fields": {"status": {"name": "Done"}, "points": 8, "assignee": {"displayName": "Bob"}}},
+			{"key": "B-1", "fields": {"status": {"name": "In Review"}, "points": 1}}
+		],
+		"meta": {"b": 2, "a": 1, "it's": "quoted"}
+	}`), &data))
+
+	tests := map[string][]any{
+		`$.total`:               {4.0},
+		`total`:                 {4.0},
+		`$.issues[0].key`:       {"A-1"},
+		`$.issues[-1].key`:      {"B-1"},
+		`$['issues'][1]["key"]`: {"A-2"},
+		`$.issues[0,2].key`:     {"A-1", "A-3"},
+		`$.issues[1:3].key`:     {"A-2", "A-3"},
+		`$.issues[:2].key`:      {"A-1", "A-2"},
+		`$.issues[-2:].key`:     {"A-3", "B-1"},
+		`$.issues[::2].key`:     {"A-1", "A-3"},
+		`$.issues[*].key`:       {"A-1", "A-2", "A-3", "B-1"},
+		`$.meta.*`:              {1.0, 2.0, "quoted"},
+		`$.meta['it\'s']`:       {"quoted"},
+		`$.issues[*].fields.assignee.displayName`: {"Ann", "Bob"},
+		`$..displayName`: {"Ann", "Bob"},
+		`$..status.name`: {"Done", "Open", "Done", "In Review"},
+		`$.issues[?(@.fields.status.name == 'Done')].key`:                        {"A-1", "A-3"},
+		`$.issues[?(@.fields.status.name != "Done")].key`:                        {"A-2", "B-1"},
+		`$.issues[?(@.fields.points >= 5)].key`:                                  {"A-2", "A-3"},
+		`$.issues[?(@.fields.points < 5 && @.fields.status.name == 'Done')].key`: {"A-1"},
+		`$.issues[?(@.fields.points > 6 || @.key == 'B-1')].key`:                 {"A-3", "B-1"},
+		`$.issues[?(@.fields.assignee)].key`:                                     {"A-1", "A-2", "A-3"},
+		`$.issues[?(!@.fields.assignee)].key`:                                    {"B-1"},
+		`$.issues[?(@.fields.assignee == null)].key`:                             {"A-2"},
+		`$.issues[?(@.key =~ '^A-[12]$')].key`:                                   {"A-1", "A-2"},
+		`$.issues[?(@.key == 'X')].key`:                                          {},
+		`$.missing`:                                                              {},
+	}
+	for expr, want := range tests {
+		t.Run(expr, func(t *testing.T) {
+			t.Parallel()
+
+			fn, err := JSONPath(expr)
+			require.NoError(t, err)
+			got, err := fn(context.Background(), data)
+			require.NoError(t, err)
+			assert.Equal(t, want, got)
+		})
+	}
+
+	t.Run("typed input", func(t *testing.T) {
+		t.Parallel()
+
+		fn, err := JSONPath(`$.pages[*].n`)
+		require.NoError(t, err)
+		got, err := fn(context.Background(), map[string]any{"pages": []map[string]any{{"n": 1}, {"n": 2}}})
+		require.NoError(t, err)
+		assert.Equal(t, []any{1.0, 2.0}, got)
+	})
+
+	t.Run("invalid expressions", func(t *testing.T) {
+		t.Parallel()
+
+		for _, expr := range []string{`$.issues[`, `$.issues[?(@.a ==)]`, `$.a..`, `$.issues[a]`, `$.issues[0:1:0]`, `$.a[?(@.b =~ 'x(')]`} {
+			_, err := JSONPath(expr)
+			require.Error(t, err, expr)
+			assert.Contains(t, err.Error(), "jsonpath: ", expr)
+		}
+	})
+}
+
+func TestJSONPathGrammar(t *testing.T) {
+	t.Parallel()
+
+	var data any
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"list": [0, 1, 2, 3, 4, 5],
+		"nested": {"a": {"key": 1, "b": {"key": 2}}, "c": [{"key": 3}, {"other": 4}]},
+		"odd keys": {"a.b": "dot", "x]y": "bracket", "q\"d": "double", "s'q": "single", "": "empty"},
+		"items": [
+			{"name": "b", "n": 2, "ok": true, "tags": ["x"]},
+			{"name": "a", "n": 10, "ok": false, "tags": []},
+			{"name": "c", "n": 2.5, "ok": true},
+			{"name": "10", "n": "2"}
+		]
+	}`), &data))
+
+	groups := map[string]map[string][]any{
+		"root and member names": {
+			`$`:                {data},
+			``:                 {data},
+			`@.list[0]`:        {0.0},
+			`nested.a.key`:     {1.0},
+			`.nested.a.key`:    {1.0},
+			`$.nested.a.b.key`: {2.0},
+			`$.list.key`:       {},
+			`$.nested.a.key.x`: {},
+			`  $.list[1]  `:    {1.0},
+		},
+		"quoted keys": {
+			`$['odd keys']['a.b']`:            {"dot"},
+			`$["odd keys"]["x]y"]`:            {"bracket"},
+			`$['odd keys']["q\"d"]`:           {"double"},
+			`$['odd keys']['s\'q']`:           {"single"},
+			`$['odd keys']['']`:               {"empty"},
+			`$['odd keys']['a.b', 'x]y']`:     {"dot", "bracket"},
+			`$['odd keys']['missing', 'a.b']`: {"dot"},
+			`$.list['0']`:                     {},
+		},
+		"indexes and unions": {
+			`$.list[0]`:         {0.0},
+			`$.list[5]`:         {5.0},
+			`$.list[6]`:         {},
+			`$.list[-1]`:        {5.0},
+			`$.list[-6]`:        {0.0},
+			`$.list[-7]`:        {},
+			`$.list[ 1 , 3 ]`:   {1.0, 3.0},
+			`$.list[3,1,3]`:     {3.0, 1.0, 3.0},
+			`$.nested[0]`:       {},
+			`$.items[0].n`:      {2.0},
+			`$.items[0,1].tags`: {[]any{"x"}, []any{}},
+		},
+		"slices": {
+			`$.list[1:3]`:                    {1.0, 2.0},
+			`$.list[:2]`:                     {0.0, 1.0},
+			`$.list[4:]`:                     {4.0, 5.0},
+			`$.list[:]`:                      {0.0, 1.0, 2.0, 3.0, 4.0, 5.0},
+			`$.list[-2:]`:                    {4.0, 5.0},
+			`$.list[:-4]`:                    {0.0, 1.0},
+			`$.list[-4:-2]`:                  {2.0, 3.0},
+			`$.list[::2]`:                    {0.0, 2.0, 4.0},
+			`$.list[1::3]`:                   {1.0, 4.0},
+			`$.list[-5:-1:2]`:                {1.0, 3.0},
+			`$.list[3:1]`:                    {},
+			`$.list[10:]`:                    {},
+			`$.list[-10:2]`:                  {0.0, 1.0},
+			`$.list[ 1 : 2 ]`:                {1.0},
+			`$.nested[0:1]`:                  {},
+			`$.list[1::9223372036854775807]`: {1.0},
+			`$.list[9223372036854775807:]`:   {},
+			`$.list[-9223372036854775808:2]`: {0.0, 1.0},
+		},
+		"wildcards": {
+			`$.nested.*.key`:  {1.0},
+			`$.nested[*]`:     {data.(map[string]any)["nested"].(map[string]any)["a"], data.(map[string]any)["nested"].(map[string]any)["c"]},
+			`$.list[*]`:       {0.0, 1.0, 2.0, 3.0, 4.0, 5.0},
+			`$.items[*].name`: {"b", "a", "c", "10"},
+			`$.list[0].*`:     {},
+		},
+		"recursive descent": {
+			`$.nested..key`:   {1.0, 2.0, 3.0},
+			`$..b.key`:        {2.0},
+			`$.nested..[0]`:   {map[string]any{"key": 3.0}},
+			`$.nested.c..*`:   {map[string]any{"key": 3.0}, map[string]any{"other": 4.0}, 3.0, 4.0},
+			`$..['a.b']`:      {"dot"},
+			`$..missing`:      {},
+			`$.nested.a..key`: {1.0, 2.0},
+			`nested..other`:   {4.0},
+		},
+		"filters": {
+			`$.items[?(@.n == 2)].name`:                                {"b"},
+			`$.items[?(@.n == '2')].name`:                              {"10"},
+			`$.items[?(@.n > 2)].name`:                                 {"a", "c"},
+			`$.items[?(@.n <= 2.5)].name`:                              {"b", "c"},
+			`$.items[?(@.n != 2)].name`:                                {"a", "c", "10"},
+			`$.items[?(@.name < 'b')].name`:                            {"a", "10"},
+			`$.items[?(@.name >= "b")].name`:                           {"b", "c"},
+			`$.items[?(@.ok == true)].name`:                            {"b", "c"},
+			`$.items[?(@.ok == false)].name`:                           {"a"},
+			`$.items[?(@.ok)].name`:                                    {"b", "a", "c"},
+			`$.items[?(!@.ok)].name`:                                   {"10"},
+			`$.items[?(@.tags[0] == 'x')].name`:                        {"b"},
+			`$.items[?(@.name =~ '^[a-b]$')].name`:                     {"b", "a"},
+			`$.items[?(@.n =~ '2')].name`:                              {"10"},
+			`$.items[?(@.n > 1 && @.n < 3 && @.ok)].name`:              {"b", "c"},
+			`$.items[?(@.name == 'a' || @.name == 'c')].name`:          {"a", "c"},
+			`$.items[?((@.n == 2 || @.n == 10) && @.ok == true)].name`: {"b"},
+			`$.items[?(@.n == 2 || @.n == 10 && @.ok == true)].name`:   {"b"},
+			`$.items[?(@.missing == null)].name`:                       {},
+			`$.items[? @.n == 10].name`:                                {"a"},
+			`$.nested[?(@.key)]`:                                       {data.(map[string]any)["nested"].(map[string]any)["a"]},
+			`$.list[?(@ > 3)]`:                                         {4.0, 5.0},
+			`$.list[?(@ > 'a')]`:                                       {},
+		},
+	}
+	for group, tests := range groups {
+		t.Run(group, func(t *testing.T) {
+			t.Parallel()
+
+			for expr, want := range tests {
+				fn, err := JSONPath(expr)
+				require.NoError(t, err, expr)
+				got, err := fn(context.Background(), data)
+				require.NoError(t, err, expr)
+				assert.Equal(t, want, got, expr)
+			}
+		})
+	}
+}
+
+func TestJSONPathErrors(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]string{
+		// Path segments report the offset they start at.
+		`$.`:                  `"$.": at offset 2: empty name`,
+		`$.a.`:                `"$.a.": at offset 4: empty name`,
+		`a.`:                  `"a.": at offset 2: empty name`,
+		`  $.a.`:              `"  $.a.": at offset 6: empty name`,
+		`$.a..`:               `"$.a..": at offset 5: missing selector after '..'`,
+		`$a`:                  `"$a": at offset 1: unexpected "a"`,
+		`$.a]`:                `"$.a]": at offset 2: unexpected ']' in name "a]"`,
+		`$.issues[`:           `"$.issues[": at offset 8: missing ']'`,
+		`$.issues['a]`:        `"$.issues['a]": at offset 8: missing ']'`,
+		`$.issues[(]`:         `"$.issues[(]": at offset 8: missing ']'`,
+		`$.issues[)`:          `"$.issues[)": at offset 8: unbalanced ')'`,
+		`$.a[0][x]`:           `"$.a[0][x]": at offset 6: invalid selector "x"`,
+		`$.a[1.5]`:            `"$.a[1.5]": at offset 3: invalid selector "1.5"`,
+		`$.a[0,'b']`:          `"$.a[0,'b']": at offset 3: cannot mix names and indexes in "0,'b'"`,
+		`$.a[0,]`:             `"$.a[0,]": at offset 3: invalid selector ""`,
+		`$.a['b]`:             `"$.a['b]": at offset 3: missing ']'`,
+		`$.a[1:2:3:4]`:        `"$.a[1:2:3:4]": at offset 3: invalid slice "1:2:3:4"`,
+		`$.a[a:b]`:            `"$.a[a:b]": at offset 3: invalid slice "a:b"`,
+		`$.a[0:1:0]`:          `"$.a[0:1:0]": at offset 3: slice step must be positive in "0:1:0"`,
+		`$.a[::-1]`:           `"$.a[::-1]": at offset 3: slice step must be positive in "::-1"`,
+		`$.a[?(@.b ==)]`:      `"$.a[?(@.b ==)]": at offset 3: invalid operand ""`,
+		`$.a[?(@.b == x)]`:    `"$.a[?(@.b == x)]": at offset 3: invalid operand "x"`,
+		`$.a[?(@.b && )]`:     `"$.a[?(@.b && )]": at offset 3: invalid operand ""`,
+		`$.a[?(@.b =~ x)]`:    `"$.a[?(@.b =~ x)]": at offset 3: =~ expects a quoted regular expression: expected a quoted string, got "x"`,
+		`$.a[?(@.b =~ 'x(')]`: "\"$.a[?(@.b =~ 'x(')]\": at offset 3: error parsing regexp: missing closing ): `x(`",
+		// Paths inside filters report their own offsets too.
+		`$.a[?(@.)]`:     `"$.a[?(@.)]": at offset 3: "@.": at offset 2: empty name`,
+		`$.a[?(@.b[x])]`: `"$.a[?(@.b[x])]": at offset 3: "@.b[x]": at offset 3: invalid selector "x"`,
+	}
+	for expr, want := range tests {
+		t.Run(expr, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := JSONPath(expr)
+			require.Error(t, err)
+			assert.EqualError(t, err, "jsonpath: "+want)
+		})
+	}
+}
+
+func FuzzJSONPath(f *testing.F) {
+	var data any
+	require.NoError(f, json.Unmarshal([]byte(`{
+		"list": [0, 1, 2, 3, 4, 5],
+		"nested": {"a": {"key": 1, "b": {"key": 2}}, "c": [{"key": 3}, {"other": 4}]},
+		"items": [{"name": "b", "n": 2, "ok": true, "tags": ["x"]}, {"name": "a", "n": 10, "ok": false, "tags": []}],
+		"odd keys": {"a.b": "dot", "x]y": "bracket", "s'q": "single", "": "empty"}
+	}`), &data))
+
+	for _, expr := range []string{
+		`$`, `list`, `$.list[0]`, `$.list[-1]`, `$.list[0,2]`, `$.list[1:3]`, `$.list[::2]`,
+		`$.list[1::9223372036854775807]`, `$.list[-9223372036854775808:]`, `$.*`, `$..key`,
+		`$['odd keys']['x]y']`, `$["odd keys"]["s'q"]`, `$.items[?(@.n > 2 && @.ok == false)].name`,
+		`$.items[?(!@.tags || @.name =~ '^a')]`, `$.items[?(@.tags[0] == 'x')]`, `$.a[?(@.b[x])]`,
+	} {
+		f.Add(expr)
+	}
+
+	f.Fuzz(func(t *testing.T, expr string) {
+		fn, err := JSONPath(expr)
+		if err != nil {
+			return
+		}
+		if _, err := fn(context.Background(), data); err != nil {
+			t.Fatalf("%q: %v", expr, err)
+		}
+	})
+}
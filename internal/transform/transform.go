@@ -0,0 +1,196 @@
+package transform
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/gi8lino/tiledash/internal/lru"
+
+	"github.com/itchyny/gojq"
+	"github.com/jmespath/go-jmespath"
+)
+
+// Func reshapes a decoded JSON value.
+type Func func(ctx context.Context, in any) (any, error)
+
+// maxCompiled bounds the memoized expressions; template helpers take arbitrary expressions.
+const maxCompiled = 256
+
+// compiled memoizes compiled expressions by language and source, since template
+// helpers compile the same expression on every render.
+var compiled = lru.New[string, Func](maxCompiled, nil)
+
+// Compile compiles whichever of a jq program or a JMESPath expression is set;
+// it returns nil when neither is.
+func Compile(jq, jmespath string) (Func, error) {
+	switch {
+	case jq != "" && jmespath != "":
+		return nil, errors.New("set either jq or jmespath, not both")
+	case jq != "":
+		return JQ(jq)
+	case jmespath != "":
+		return JMESPath(jmespath)
+	default:
+		return nil, nil
+	}
+}
+
+// Check reports whether Compile accepts jq and jmespath; it is the config.TransformCheck used by Validate.
+func Check(jq, jmespath string) error {
+	_, err := Compile(jq, jmespath)
+	return err
+}
+
+// JQ compiles a jq expression. A program emitting one value returns it as is;
+// no value returns nil and several values are collected into a list.
+func JQ(expr string) (Func, error) {
+	if fn, ok := compiled.Get("jq:" + expr); ok {
+		return fn, nil
+	}
+	query, err := gojq.Parse(expr)
+	if err != nil {
+		return nil, fmt.Errorf("jq: %w", err)
+	}
+	code, err := gojq.Compile(query)
+	if err != nil {
+		return nil, fmt.Errorf("jq: %w", err)
+	}
+
+	fn := Func(func(ctx context.Context, in any) (any, error) {
+		v, err := normalize(in)
+		if err != nil {
+			return nil, err
+		}
+		var out []any
+		iter := code.RunWithContext(ctx, v)
+		for {
+			v, ok := iter.Next()
+			if !ok {
+				break
+			}
+			if err, ok := v.(error); ok {
+				return nil, fmt.Errorf("jq: %w", err)
+			}
+			out = append(out, v)
+		}
+		switch len(out) {
+		case 0:
+			return nil, nil
+		case 1:
+			return out[0], nil
+		default:
+			return out, nil
+		}
+	})
+	compiled.Add("jq:"+expr, fn)
+	return fn, nil
+}
+
+// JMESPath compiles a JMESPath expression.
+func JMESPath(expr string) (Func, error) {
+	if fn, ok := compiled.Get("jmespath:" + expr); ok {
+		return fn, nil
+	}
+	query, err := jmespath.Compile(expr)
+	if err != nil {
+		return nil, fmt.Errorf("jmespath: %w", err)
+	}
+
+	fn := Func(func(_ context.Context, in any) (any, error) {
+		v, err := normalize(in)
+		if err != nil {
+			return nil, err
+		}
+		out, err := query.Search(v)
+		if err != nil {
+			return nil, fmt.Errorf("jmespath: %w", err)
+		}
+		return out, nil
+	})
+	compiled.Add("jmespath:"+expr, fn)
+	return fn, nil
+}
+
+// normalize converts a value into the plain JSON types (map[string]any, []any,
+// float64, ...) the expression engines expect, e.g. for typed page slices and the
+// json.Number values of decoded responses. Known types are converted in a single walk
+// that copies maps and slices, so cached responses are never modified; anything else
+// falls back to a JSON round-trip.
+func normalize(in any) (any, error) {
+	if v, _, ok := plain(in); ok {
+		return v, nil
+	}
+	b, err := json.Marshal(in)
+	if err != nil {
+		return nil, fmt.Errorf("transform input: %w", err)
+	}
+	var v any
+	if err := json.Unmarshal(b, &v); err != nil {
+		return nil, fmt.Errorf("transform input: %w", err)
+	}
+	return v, nil
+}
+
+// plain returns v with json.Number converted to float64 and typed page slices to []any.
+// Maps and slices are copied only when something inside them is converted; changed reports
+// whether that happened. It reports false if v holds a type it doesn't know.
+func plain(v any) (out any, changed, ok bool) {
+	switch t := v.(type) {
+	case nil, bool, string, float64:
+		return v, false, true
+	case json.Number:
+		f, err := t.Float64()
+		return f, true, err == nil
+	case []any:
+		var cp []any
+		for i, e := range t {
+			p, changed, ok := plain(e)
+			if !ok {
+				return nil, false, false
+			}
+			if changed && cp == nil {
+				cp = slices.Clone(t)
+			}
+			if cp != nil {
+				cp[i] = p
+			}
+		}
+		if cp == nil {
+			return t, false, true
+		}
+		return cp, true, true
+	case []map[string]any:
+		cp := make([]any, len(t))
+		for i, e := range t {
+			p, _, ok := plain(e)
+			if !ok {
+				return nil, false, false
+			}
+			cp[i] = p
+		}
+		return cp, true, true
+	case map[string]any:
+		var cp map[string]any
+		for k, e := range t {
+			p, changed, ok := plain(e)
+			if !ok {
+				return nil, false, false
+			}
+			if changed && cp == nil {
+				cp = maps.Clone(t)
+			}
+			if cp != nil {
+				cp[k] = p
+			}
+		}
+		if cp == nil {
+			return t, false, true
+		}
+		return cp, true, true
+	}
+	return nil, false, false
+}
@@ -0,0 +1,194 @@
+package transform
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJQ(t *testing.T) {
+	t.Parallel()
+
+	data := map[string]any{"issues": []map[string]any{
+		{"key": "A-1", "status": "Open"},
+		{"key": "A-2", "status": "Done"},
+		{"key": "A-3", "status": "Open"},
+	}}
+
+	t.Run("single value", func(t *testing.T) {
+		t.Parallel()
+
+		fn, err := JQ(`[.issues[] | select(.status == "Open") | .key]`)
+		require.NoError(t, err)
+		got, err := fn(context.Background(), data)
+		require.NoError(t, err)
+		assert.Equal(t, []any{"A-1", "A-3"}, got)
+	})
+
+	t.Run("several values are collected", func(t *testing.T) {
+		t.Parallel()
+
+		fn, err := JQ(`.issues[].key`)
+		require.NoError(t, err)
+		got, err := fn(context.Background(), data)
+		require.NoError(t, err)
+		assert.Equal(t, []any{"A-1", "A-2", "A-3"}, got)
+
+		fn, err = JQ(`empty`)
+		require.NoError(t, err)
+		got, err = fn(context.Background(), data)
+		require.NoError(t, err)
+		assert.Nil(t, got)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := JQ(`.issues[`)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "jq: ")
+
+		fn, err := JQ(`.issues[0] + 1`)
+		require.NoError(t, err)
+		_, err = fn(context.Background(), map[string]any{"issues": []any{"x"}})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "jq: ")
+	})
+
+	t.Run("compiled once", func(t *testing.T) {
+		t.Parallel()
+
+		a, err := JQ(`.x`)
+		require.NoError(t, err)
+		b, err := JQ(`.x`)
+		require.NoError(t, err)
+		assert.Equal(t, reflect.ValueOf(a).Pointer(), reflect.ValueOf(b).Pointer())
+	})
+}
+
+func TestJMESPath(t *testing.T) {
+	t.Parallel()
+
+	data := map[string]any{"issues": []map[string]any{
+		{"key": "A-1", "status": "Open"},
+		{"key": "A-2", "status": "Done"},
+	}}
+
+	fn, err := JMESPath(`issues[?status=='Open'].key`)
+	require.NoError(t, err)
+	got, err := fn(context.Background(), data)
+	require.NoError(t, err)
+	assert.Equal(t, []any{"A-1"}, got)
+
+	fn, err = JMESPath(`length(issues)`)
+	require.NoError(t, err)
+	got, err = fn(context.Background(), data)
+	require.NoError(t, err)
+	assert.Equal(t, 2.0, got)
+
+	_, err = JMESPath(`issues[?`)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "jmespath: ")
+}
+
+func TestCompile(t *testing.T) {
+	t.Parallel()
+
+	data := map[string]any{"issues": []any{1, 2}}
+
+	fn, err := Compile("", "")
+	require.NoError(t, err)
+	assert.Nil(t, fn)
+
+	fn, err = Compile(`.issues | length`, "")
+	require.NoError(t, err)
+	got, err := fn(context.Background(), data)
+	require.NoError(t, err)
+	assert.Equal(t, 2, got)
+
+	fn, err = Compile("", `length(issues)`)
+	require.NoError(t, err)
+	got, err = fn(context.Background(), data)
+	require.NoError(t, err)
+	assert.Equal(t, 2.0, got)
+
+	_, err = Compile(".", "@")
+	assert.EqualError(t, err, "set either jq or jmespath, not both")
+	assert.EqualError(t, Check(".", "@"), "set either jq or jmespath, not both")
+	assert.ErrorContains(t, Check(".issues[", ""), "jq: ")
+	assert.NoError(t, Check("", ""))
+}
+
+func TestNormalize(t *testing.T) {
+	t.Parallel()
+
+	t.Run("converts json.Number without touching the input", func(t *testing.T) {
+		t.Parallel()
+
+		page := map[string]any{"total": json.Number("3"), "issues": []any{map[string]any{"points": json.Number("2.5")}}}
+		in := map[string]any{"pages": []map[string]any{page}}
+
+		got, err := normalize(in)
+		require.NoError(t, err)
+		assert.Equal(t, map[string]any{"pages": []any{
+			map[string]any{"total": 3.0, "issues": []any{map[string]any{"points": 2.5}}},
+		}}, got)
+		assert.Equal(t, json.Number("3"), page["total"], "cached responses must not be modified")
+	})
+
+	t.Run("copies only what contains numbers", func(t *testing.T) {
+		t.Parallel()
+
+		plainPart := map[string]any{"name": "a", "tags": []any{"x"}}
+		in := map[string]any{"plain": plainPart, "numbers": []any{json.Number("1"), "b"}}
+
+		got, err := normalize(in)
+		require.NoError(t, err)
+		out := got.(map[string]any)
+		assert.Equal(t, []any{1.0, "b"}, out["numbers"])
+		assert.Equal(t, reflect.ValueOf(plainPart).Pointer(), reflect.ValueOf(out["plain"]).Pointer())
+		assert.NotEqual(t, reflect.ValueOf(in).Pointer(), reflect.ValueOf(out).Pointer())
+
+		unchanged := map[string]any{"plain": plainPart}
+		got, err = normalize(unchanged)
+		require.NoError(t, err)
+		assert.Equal(t, reflect.ValueOf(unchanged).Pointer(), reflect.ValueOf(got).Pointer())
+	})
+
+	t.Run("jq and jmespath see numbers", func(t *testing.T) {
+		t.Parallel()
+
+		data := map[string]any{"issues": []any{
+			map[string]any{"points": json.Number("3")},
+			map[string]any{"points": json.Number("8")},
+		}}
+
+		fn, err := JQ(`[.issues[] | select(.points > 5)] | length`)
+		require.NoError(t, err)
+		got, err := fn(context.Background(), data)
+		require.NoError(t, err)
+		assert.Equal(t, 1, got)
+
+		fn, err = JMESPath(`sum(issues[].points)`)
+		require.NoError(t, err)
+		got, err = fn(context.Background(), data)
+		require.NoError(t, err)
+		assert.Equal(t, 11.0, got)
+	})
+
+	t.Run("other types fall back to a JSON round-trip", func(t *testing.T) {
+		t.Parallel()
+
+		got, err := normalize(map[string]int{"n": 1})
+		require.NoError(t, err)
+		assert.Equal(t, map[string]any{"n": 1.0}, got)
+
+		_, err = normalize(map[string]any{"c": make(chan int)})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "transform input: ")
+	})
+}
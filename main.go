@@ -4,6 +4,7 @@ import (
 	"context"
 	"embed"
 	"os"
+	_ "time/tzdata" // dashboard timezones work without zoneinfo in the image
 
 	"github.com/gi8lino/tiledash/internal/app"
 )
@@ -0,0 +1,103 @@
+document.addEventListener("DOMContentLoaded", function () {
+  const entries = JSON.parse(
+    document.getElementById("playlist-entries")?.textContent || "[]",
+  );
+  const start =
+    parseInt(
+      document.querySelector('meta[name="playlist-start"]')?.content,
+      10,
+    ) || 0;
+  const frames = document.querySelectorAll(".playlist-frame");
+  const status = document.getElementById("playlist-status");
+
+  if (entries.length === 0 || frames.length < 2) return;
+
+  let current = -1; // index of the entry on screen
+  let front = 0; // index of the visible iframe
+  let timer = null;
+  let paused = false;
+
+  /**
+   * Loads entry i into the hidden iframe and swaps it in once loaded, so the
+   * screen never shows a blank page between dashboards.
+   */
+  function show(i) {
+    i = ((i % entries.length) + entries.length) % entries.length;
+    const entry = entries[i];
+    const back = frames[1 - front];
+
+    clearTimeout(timer);
+    back.onload = function () {
+      back.onload = null;
+      frames[front].classList.remove("active");
+      back.classList.add("active");
+      front = 1 - front;
+      listenForKeys(back);
+    };
+    back.src = entry.url;
+    current = i;
+
+    // Keep ?start= in sync so a reload resumes at the current entry
+    const url = new URL(location.href);
+    url.searchParams.set("start", String(i + 1));
+    history.replaceState(null, "", url);
+
+    updateStatus();
+    schedule();
+  }
+
+  /**
+   * Arms the dwell timer for the current entry unless paused.
+   */
+  function schedule() {
+    clearTimeout(timer);
+    if (paused) return;
+    timer = setTimeout(() => show(current + 1), entries[current].seconds * 1000);
+  }
+
+  function updateStatus() {
+    const entry = entries[current];
+    status.textContent = `Paused – ${entry.title || entry.name} (${current + 1}/${entries.length})`;
+  }
+
+  function togglePause() {
+    paused = !paused;
+    document.body.classList.toggle("paused", paused);
+    updateStatus();
+    schedule();
+  }
+
+  /**
+   * Space or "p" pauses/resumes; arrow keys step through the entries.
+   */
+  function onKey(e) {
+    switch (e.key) {
+      case " ":
+      case "p":
+      case "P":
+        e.preventDefault();
+        togglePause();
+        break;
+      case "ArrowRight":
+        show(current + 1);
+        break;
+      case "ArrowLeft":
+        show(current - 1);
+        break;
+    }
+  }
+
+  /**
+   * Keypresses inside a dashboard go to its iframe; forward them (same origin).
+   */
+  function listenForKeys(frame) {
+    try {
+      frame.contentWindow.document.addEventListener("keydown", onKey);
+    } catch (err) {
+      console.warn("Cannot listen for keys in playlist frame", err);
+    }
+  }
+
+  document.addEventListener("keydown", onKey);
+  show(start);
+});
@@ -4,6 +4,14 @@ document.addEventListener("DOMContentLoaded", function () {
     document.querySelector('meta[name="config-hash"]')?.content || "";
   const routePrefix =
     document.querySelector('meta[name="route-prefix"]')?.content || "";
+  // Tile API root; named dashboards use /api/v1/d/{name}
+  const apiBase =
+    document.querySelector('meta[name="api-base"]')?.content ||
+    `${routePrefix}/api/v1`;
+  // Resolved dashboard parameters, forwarded to every tile request
+  const params =
+    document.querySelector('meta[name="params"]')?.content || "";
+  const paramsQuery = params ? `?${params}` : "";
 
   // Track all card elements
   const cards = document.querySelectorAll("[data-tile-id]");
@@ -17,8 +25,16 @@ document.addEventListener("DOMContentLoaded", function () {
     if (!card || inFlight.has(id)) return;
     inFlight.add(id);
 
-    fetch(`${routePrefix}/api/v1/tile/${id}`)
-      .then((res) => res.text())
+    fetch(`${apiBase}/tile/${id}${paramsQuery}`)
+      .then((res) => {
+        // The tile hash is served as the ETag; remember it so the refresh loop
+        // doesn't reload tiles the shell had no warm hash for.
+        const etag = res.headers.get("ETag");
+        if (res.ok && etag) {
+          tileHashes[id] = etag.replace(/^W\//, "").replace(/"/g, "");
+        }
+        return res.text();
+      })
       .then((html) => {
         card.innerHTML = html;
 
@@ -59,7 +75,8 @@ document.addEventListener("DOMContentLoaded", function () {
   function injectDebugLabel(card) {
     const row = card.getAttribute("data-row");
     const col = card.getAttribute("data-col");
-    const span = card.getAttribute("data-col-span");
+    const colSpan = card.getAttribute("data-col-span");
+    const rowSpan = card.getAttribute("data-row-span");
     const tmpl = card.getAttribute("data-template");
     const title = card.getAttribute("data-tile-title") || "Untitled";
     const id = card.getAttribute("data-tile-id");
@@ -71,7 +88,7 @@ document.addEventListener("DOMContentLoaded", function () {
 
     const label = document.createElement("div");
     label.className = "debug-label";
-    label.innerHTML = `id: ${id} | title: ${title}\nrow: ${row} | col: ${col} | span: ${span}${hidden}\ntemplate: ${tmpl}`;
+    label.innerHTML = `id: ${id} | title: ${title}\nrow: ${row} | col: ${col} | colSpan: ${colSpan} | rowSpan: ${rowSpan}${hidden}\ntemplate: ${tmpl}`;
     card.appendChild(label);
   }
 
@@ -110,7 +127,7 @@ document.addEventListener("DOMContentLoaded", function () {
       );
     }
 
-    fetch(`${routePrefix}/api/v1/hash/config`)
+    fetch(`${apiBase}/hash/config`)
       .then((res) => res.text())
       .then((newConfigHash) => {
         const configMatches = newConfigHash === configHash;
@@ -129,7 +146,7 @@ document.addEventListener("DOMContentLoaded", function () {
           const id = card.getAttribute("data-tile-id");
           const title = card.getAttribute("data-tile-title") || "Untitled";
           const oldHash = tileHashes[id];
-          fetch(`${routePrefix}/api/v1/hash/${id}`)
+          fetch(`${apiBase}/hash/${id}${paramsQuery}`)
             .then((res) => res.text())
             .then((newHash) => {
               const hashMatches = oldHash === newHash;
@@ -153,8 +170,33 @@ document.addEventListener("DOMContentLoaded", function () {
       });
   }
 
+  // Keep ?page= in sync with the selected tab so the URL can be shared
+  document
+    .querySelectorAll('[data-bs-toggle="tab"][data-page]')
+    .forEach((tab) => {
+      tab.addEventListener("shown.bs.tab", () => {
+        const url = new URL(location.href);
+        url.searchParams.set("page", tab.dataset.page);
+        history.replaceState(null, "", url);
+      });
+    });
+
+  // Reload the dashboard with the new value when a parameter changes,
+  // keeping the rest of the query (e.g. ?page=) intact
+  document.querySelectorAll("[data-param]").forEach((input) => {
+    input.addEventListener("change", () => {
+      const url = new URL(location.href);
+      url.searchParams.set(input.dataset.param, input.value);
+      location.assign(url);
+    });
+  });
+  document.querySelector("form.td-params")?.addEventListener("submit", (e) => {
+    e.preventDefault();
+  });
+
   // Handle debug toggle via keypress
   document.addEventListener("keydown", function (e) {
+    if (e.target.closest?.("input, select, textarea")) return;
     if (e.key === "d" || e.key === "D") toggleDebug();
   });
 